@@ -19,7 +19,7 @@ func main() {
 	// CRITICAL: Redirect standard log output to stderr first (before any logging)
 	log.SetOutput(os.Stderr)
 
-	// Create structured logger (JSON format to stderr)
+	// Create structured logger (JSON format to stderr) until the config is loaded
 	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
@@ -36,6 +36,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Recreate the logger with the configured level and format
+	logger = newLogger(cfg)
+	slog.SetDefault(logger)
+
 	switch cfg.Transport {
 	case "sse":
 		runSSEMode(ctx, cfg, logger)
@@ -44,9 +48,63 @@ func main() {
 	}
 }
 
+// newLogger creates the stderr logger described by cfg's LogLevel and LogFormat.
+func newLogger(cfg *config.Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.LogLevel}
+	if cfg.LogFormat == "text" {
+		return slog.New(slog.NewTextHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+}
+
 // runStdioMode is the original flow: authenticate first (blocking), then serve MCP on stdio.
+// With STDIO_DEFER_AUTH it serves immediately and authenticates in the background.
 func runStdioMode(ctx context.Context, cfg *config.Config, logger *slog.Logger) {
-	oauthCfg := auth.NewOAuth2Config(cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.OAuthRedirectURL)
+	if cfg.StdioDeferAuth {
+		runDeferredStdioMode(ctx, cfg, logger)
+		return
+	}
+
+	ytClient, tokenSource, err := connectYouTube(ctx, cfg, logger, nil)
+	if err != nil {
+		logger.Error("failed to connect to youtube", "error", err)
+		os.Exit(1)
+	}
+
+	// Create and run MCP server (stdio transport)
+	srv := server.NewServer(logger, ytClient, tokenSource, cfg, nil)
+	if err := srv.Run(ctx); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runDeferredStdioMode serves MCP on stdio right away while authenticating in
+// the background; until then tools return the URL to visit.
+func runDeferredStdioMode(ctx context.Context, cfg *config.Config, logger *slog.Logger) {
+	srv := server.NewDeferredServer(logger, cfg)
+
+	go func() {
+		ytClient, tokenSource, err := connectYouTube(ctx, cfg, logger, srv.SetAuthURL)
+		if err != nil {
+			logger.Error("failed to connect to youtube", "error", err)
+			srv.FailAuth(err)
+			return
+		}
+		srv.CompleteAuth(ytClient, tokenSource)
+	}()
+
+	if err := srv.Run(ctx); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// connectYouTube authenticates (loading a saved token or running the local
+// OAuth callback flow, reporting its URL to onAuthURL) and returns a YouTube
+// client validated by fetching the user's channel.
+func connectYouTube(ctx context.Context, cfg *config.Config, logger *slog.Logger, onAuthURL func(string)) (*youtube.Client, *auth.PersistingTokenSource, error) {
+	oauthCfg := auth.NewOAuth2Config(cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.OAuthRedirectURL, cfg.ReadOnly)
 
 	// Select token storage: env-based (Railway) or file-based (local)
 	var storage auth.TokenStorage
@@ -58,39 +116,40 @@ func runStdioMode(ctx context.Context, cfg *config.Config, logger *slog.Logger)
 		storage = auth.NewFileTokenStorage(auth.DefaultTokenPath())
 	}
 
-	// Authenticate (either load existing token or run local OAuth callback flow)
-	httpClient, err := auth.Authenticate(ctx, oauthCfg, storage, cfg.OAuthPort, logger)
+	// Authenticate (either load existing token or run the OAuth flow; port 0 reads the code from stdin)
+	port := cfg.OAuthPort
+	if cfg.ManualAuth {
+		port = 0
+	}
+	httpClient, tokenSource, err := auth.AuthenticateWithOptions(ctx, oauthCfg, storage, logger, auth.AuthOptions{
+		Port:                port,
+		OnAuthURL:           onAuthURL,
+		RequireRefreshToken: cfg.RequireRefreshToken,
+		SkipConsent:         !cfg.ForceConsent,
+	})
 	if err != nil {
-		logger.Error("authentication failed", "error", err)
-		os.Exit(1)
+		return nil, nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
 	// Create YouTube API client
-	ytClient, err := youtube.NewClient(ctx, httpClient)
+	ytClient, err := youtube.NewClient(ctx, httpClient, server.YouTubeOptions(cfg))
 	if err != nil {
-		logger.Error("failed to create youtube client", "error", err)
-		os.Exit(1)
+		return nil, nil, fmt.Errorf("failed to create youtube client: %w", err)
 	}
 
 	// Validate authentication by fetching channel info
 	channelName, err := ytClient.ValidateAuth(ctx)
 	if err != nil {
-		logger.Error("auth validation failed", "error", err)
-		os.Exit(1)
+		return nil, nil, fmt.Errorf("auth validation failed: %w", err)
 	}
 	logger.Info("authenticated with youtube", "channel", channelName)
 
-	// Create and run MCP server (stdio transport)
-	srv := server.NewServer(logger, ytClient, cfg.Transport, cfg.Port, nil)
-	if err := srv.Run(ctx); err != nil {
-		logger.Error("server failed", "error", err)
-		os.Exit(1)
-	}
+	return ytClient, tokenSource, nil
 }
 
 // runSSEMode starts the HTTP server with MCP OAuth specification support.
 // The server acts as its own OAuth Authorization Server, proxying auth to Google.
-// YouTube client is created lazily after the first successful OAuth flow.
+// Each authenticated user gets a YouTube client, created lazily on their first MCP request.
 func runSSEMode(ctx context.Context, cfg *config.Config, logger *slog.Logger) {
 	if cfg.BaseURL == "" {
 		fmt.Fprintln(os.Stderr, "BASE_URL is required for SSE mode")
@@ -102,14 +161,22 @@ func runSSEMode(ctx context.Context, cfg *config.Config, logger *slog.Logger) {
 		cfg.GoogleClientID,
 		cfg.GoogleClientSecret,
 		cfg.BaseURL+"/callback",
+		cfg.ReadOnly,
 	)
 
 	// Create MCP OAuth Authorization Server
-	mcpOAuth := auth.NewMCPOAuthServer(cfg.BaseURL, googleCfg, logger)
+	mcpOAuth := auth.NewMCPOAuthServer(cfg.BaseURL, googleCfg, logger, auth.MCPOAuthOptions{
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		AuthCodeTTL:     cfg.AuthCodeTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+
+		RequireRefreshToken: cfg.RequireRefreshToken,
+		SkipConsent:         !cfg.ForceConsent,
+	})
 	mcpOAuth.StartCleanup(ctx)
 
-	// Create and run MCP server (SSE transport, nil ytClient — lazy init after OAuth)
-	srv := server.NewServer(logger, nil, cfg.Transport, cfg.Port, mcpOAuth)
+	// Create and run MCP server (SSE transport, nil ytClient — lazy per-user init after OAuth)
+	srv := server.NewServer(logger, nil, nil, cfg, mcpOAuth)
 	if err := srv.Run(ctx); err != nil {
 		logger.Error("server failed", "error", err)
 		os.Exit(1)
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// maxAuthCodeAttempts is how many times the manual flow prompts for a code
+// before giving up on empty or garbled input.
+const maxAuthCodeAttempts = 3
+
+// readAuthCode prompts on w for the authorization code and reads it from r,
+// one line per attempt. The user may paste the bare code or the whole redirect
+// URL; a URL must carry the flow's state.
+func readAuthCode(ctx context.Context, r io.Reader, w io.Writer, state string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		fmt.Fprint(w, "Paste the authorization code, or the whole URL from the address bar: ")
+
+		type line struct {
+			text string
+			err  error
+		}
+		lines := make(chan line, 1)
+		go func() {
+			text, err := readLine(r)
+			lines <- line{text, err}
+		}()
+
+		var input line
+		select {
+		case input = <-lines:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if input.err != nil {
+			return "", fmt.Errorf("failed to read authorization code: %w", input.err)
+		}
+
+		code, err := parseAuthCode(input.text, state)
+		if err == nil {
+			return code, nil
+		}
+		if attempt == maxAuthCodeAttempts {
+			return "", err
+		}
+		fmt.Fprintf(w, "%v, try again.\n", err)
+	}
+}
+
+// readLine reads one line from r a byte at a time, so nothing past the line is
+// consumed: in stdio mode the same stdin carries MCP messages afterwards.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(line) > 0 {
+				return string(line), nil
+			}
+			return "", err
+		}
+	}
+}
+
+// parseAuthCode extracts the authorization code from pasted input: either the
+// code itself or a redirect URL (or its query) with code and state parameters.
+func parseAuthCode(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("no authorization code entered")
+	}
+
+	query, isQuery := "", false
+	if _, after, ok := strings.Cut(input, "?"); ok {
+		query, isQuery = after, true
+	} else if strings.Contains(input, "code=") || strings.Contains(input, "error=") {
+		query, isQuery = input, true
+	}
+	if isQuery {
+		params, err := url.ParseQuery(query)
+		if err != nil {
+			return "", fmt.Errorf("garbled redirect URL: %w", err)
+		}
+		if denied := params.Get("error"); denied != "" {
+			return "", fmt.Errorf("authorization was denied: %s", denied)
+		}
+		if subtle.ConstantTimeCompare([]byte(params.Get("state")), []byte(state)) != 1 {
+			return "", errors.New("redirect URL is from a different authorization attempt (state mismatch)")
+		}
+		input = params.Get("code")
+		if input == "" {
+			return "", errors.New("redirect URL has no code parameter")
+		}
+	}
+
+	if strings.ContainsFunc(input, unicode.IsSpace) {
+		return "", errors.New("garbled authorization code: it must not contain spaces")
+	}
+	return input, nil
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAuthCode(t *testing.T) {
+	const state = "state-123"
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"bare code", "4/0AbCd\n", "4/0AbCd", ""},
+		{"code without newline at EOF", "4/0AbCd", "4/0AbCd", ""},
+		{"surrounding spaces", "  4/0AbCd \r\n", "4/0AbCd", ""},
+		{"redirect URL", "http://localhost/callback?state=state-123&code=4/0AbCd\n", "4/0AbCd", ""},
+		{"query only", "state=state-123&code=4/0AbCd\n", "4/0AbCd", ""},
+		{"retry after empty line", "\n4/0AbCd\n", "4/0AbCd", ""},
+		{"retry after garbled code", "4/0A bCd\n4/0AbCd\n", "4/0AbCd", ""},
+		{"empty every time", "\n\n\n", "", "no authorization code entered"},
+		{"garbled every time", "a b\nc d\ne f\n", "", "garbled authorization code"},
+		{"state mismatch", strings.Repeat("http://localhost/callback?state=other&code=4/0AbCd\n", 3), "", "state mismatch"},
+		{"denied", strings.Repeat("http://localhost/callback?state=state-123&error=access_denied\n", 3), "", "authorization was denied: access_denied"},
+		{"no code parameter", strings.Repeat("http://localhost/callback?state=state-123\n", 3), "", "no code parameter"},
+		{"input ends", "", "", "failed to read authorization code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompts strings.Builder
+			got, err := readAuthCode(context.Background(), strings.NewReader(tt.input), &prompts, state)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readAuthCode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("code = %q, want %q", got, tt.want)
+			}
+			if retries := strings.Count(tt.input, "\n") - 1; retries > 0 && strings.Count(prompts.String(), "try again") != retries {
+				t.Errorf("prompts = %q, want %d retry messages", prompts.String(), retries)
+			}
+		})
+	}
+}
+
+func TestParseAuthCodeGarbledURL(t *testing.T) {
+	_, err := parseAuthCode("http://localhost/callback?code=%zz", "state")
+	if err == nil || !strings.Contains(err.Error(), "garbled redirect URL") {
+		t.Errorf("err = %v, want a garbled redirect URL error", err)
+	}
+}
+
+func TestReadAuthCodeLeavesRestOfInput(t *testing.T) {
+	r := strings.NewReader("4/0AbCd\n{\"jsonrpc\":\"2.0\"}\n")
+	if _, err := readAuthCode(context.Background(), r, io.Discard, "state"); err != nil {
+		t.Fatalf("readAuthCode: %v", err)
+	}
+	rest, _ := io.ReadAll(r)
+	if string(rest) != "{\"jsonrpc\":\"2.0\"}\n" {
+		t.Errorf("input left after the code = %q, want the MCP message untouched", rest)
+	}
+}
+
+func TestReadAuthCodeRespectsCancellation(t *testing.T) {
+	pr, pw := io.Pipe()
+	t.Cleanup(func() { pw.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := readAuthCode(ctx, pr, io.Discard, "state")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded while waiting for input", err)
+	}
+}
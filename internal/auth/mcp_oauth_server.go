@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"cmp"
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
@@ -43,6 +44,10 @@ type dcrClient struct {
 	RedirectURIs []string `json:"redirect_uris"`
 }
 
+// pendingAuthTTL is how long the user has to complete Google consent for an
+// authorization request before its state is rejected.
+const pendingAuthTTL = 10 * time.Minute
+
 // pendingAuth tracks an in-flight authorization request.
 type pendingAuth struct {
 	clientID      string
@@ -55,6 +60,7 @@ type pendingAuth struct {
 // authCode is a single-use MCP authorization code.
 type authCode struct {
 	clientID      string
+	grantID       string
 	redirectURI   string
 	codeChallenge string
 	createdAt     time.Time
@@ -63,42 +69,97 @@ type authCode struct {
 // accessToken tracks an issued access token.
 type accessToken struct {
 	clientID  string
+	grantID   string
 	expiresAt time.Time
 }
 
 // refreshToken tracks an issued refresh token.
 type refreshToken struct {
-	clientID string
+	clientID  string
+	grantID   string
+	expiresAt time.Time
+}
+
+// Default lifetimes of the tokens and codes an MCPOAuthServer issues.
+const (
+	DefaultAccessTokenTTL  = time.Hour
+	DefaultAuthCodeTTL     = 10 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// MCPOAuthOptions configures optional MCPOAuthServer behavior.
+type MCPOAuthOptions struct {
+	// AccessTokenTTL is the lifetime of issued access tokens.
+	// Zero uses DefaultAccessTokenTTL.
+	AccessTokenTTL time.Duration
+
+	// AuthCodeTTL is how long an authorization code can be exchanged.
+	// Zero uses DefaultAuthCodeTTL.
+	AuthCodeTTL time.Duration
+
+	// RefreshTokenTTL is how long an unused refresh token stays valid. Each
+	// refresh issues a new one, so only grants nobody has used for this long
+	// expire. Zero uses DefaultRefreshTokenTTL.
+	RefreshTokenTTL time.Duration
+
+	// RequireRefreshToken rejects Google tokens issued without a refresh
+	// token instead of only logging a warning.
+	RequireRefreshToken bool
+
+	// SkipConsent leaves out prompt=consent when redirecting to Google, so
+	// returning users skip the consent screen; see authCodeOptions.
+	SkipConsent bool
 }
 
 // MCPOAuthServer implements a full OAuth 2.0 Authorization Server
 // for the MCP specification (RFC 9728 + RFC 8414 + DCR).
 // It proxies authorization to Google and issues its own opaque tokens.
+//
+// Each completed Google authorization is a grant with its own Google token.
+// The MCP tokens issued for it, including refreshed ones, carry the grant ID,
+// which is reported as the token's user ID, so clients authorized by
+// different Google users never share a YouTube account. A grant is dropped
+// once no code or token carries it any more, or when it is revoked.
 type MCPOAuthServer struct {
 	baseURL  string
 	googleCfg *oauth2.Config
 	logger   *slog.Logger
 
+	accessTokenTTL  time.Duration
+	authCodeTTL     time.Duration
+	refreshTokenTTL time.Duration
+
+	requireRefreshToken bool
+	skipConsent         bool
+
 	mu            sync.Mutex
+	onGrant       func(grantID string) // called after each completed Google authorization
+	onGrantRemoved func(grantID string) // called after a grant is pruned or revoked
 	clients       map[string]*dcrClient    // client_id -> client
 	pendingAuths  map[string]*pendingAuth  // google_state -> pending
 	authCodes     map[string]*authCode     // code -> auth code record
 	accessTokens  map[string]*accessToken  // token -> access token record
 	refreshTokens map[string]*refreshToken // token -> refresh token record
-	googleToken   *oauth2.Token            // single-tenant Google token
+	googleTokens  map[string]*oauth2.Token // grant ID -> Google token
 }
 
 // NewMCPOAuthServer creates a new MCP OAuth Authorization Server.
-func NewMCPOAuthServer(baseURL string, googleCfg *oauth2.Config, logger *slog.Logger) *MCPOAuthServer {
+func NewMCPOAuthServer(baseURL string, googleCfg *oauth2.Config, logger *slog.Logger, opts MCPOAuthOptions) *MCPOAuthServer {
 	return &MCPOAuthServer{
-		baseURL:       baseURL,
-		googleCfg:     googleCfg,
-		logger:        logger,
+		baseURL:        baseURL,
+		googleCfg:      googleCfg,
+		logger:         logger,
+		accessTokenTTL: cmp.Or(opts.AccessTokenTTL, DefaultAccessTokenTTL),
+		authCodeTTL:    cmp.Or(opts.AuthCodeTTL, DefaultAuthCodeTTL),
+		refreshTokenTTL: cmp.Or(opts.RefreshTokenTTL, DefaultRefreshTokenTTL),
+		requireRefreshToken: opts.RequireRefreshToken,
+		skipConsent:         opts.SkipConsent,
 		clients:       make(map[string]*dcrClient),
 		pendingAuths:  make(map[string]*pendingAuth),
 		authCodes:     make(map[string]*authCode),
 		accessTokens:  make(map[string]*accessToken),
 		refreshTokens: make(map[string]*refreshToken),
+		googleTokens:  make(map[string]*oauth2.Token),
 	}
 }
 
@@ -257,10 +318,7 @@ func (s *MCPOAuthServer) AuthorizeHandler() http.HandlerFunc {
 		s.mu.Unlock()
 
 		// Redirect to Google consent
-		authURL := s.googleCfg.AuthCodeURL(googleState,
-			oauth2.AccessTypeOffline,
-			oauth2.SetAuthURLParam("prompt", "consent"),
-		)
+		authURL := s.googleCfg.AuthCodeURL(googleState, authCodeOptions(s.skipConsent)...)
 		http.Redirect(w, r, authURL, http.StatusFound)
 	}
 }
@@ -290,6 +348,12 @@ func (s *MCPOAuthServer) GoogleCallbackHandler() http.HandlerFunc {
 			return
 		}
 
+		// Enforce the TTL here too; cleanup only prunes every few minutes
+		if time.Since(pending.createdAt) > pendingAuthTTL {
+			http.Error(w, "Authorization request expired, please start again", http.StatusBadRequest)
+			return
+		}
+
 		// Exchange Google code for token
 		token, err := s.googleCfg.Exchange(r.Context(), googleCode)
 		if err != nil {
@@ -297,25 +361,33 @@ func (s *MCPOAuthServer) GoogleCallbackHandler() http.HandlerFunc {
 			http.Error(w, "Google authentication failed", http.StatusInternalServerError)
 			return
 		}
+		if err := checkRefreshToken(token, "google callback", s.requireRefreshToken, s.logger); err != nil {
+			s.logger.Error("Google token rejected", "error", err)
+			http.Error(w, "Google did not issue a refresh token; remove this app's access at "+revokeAccessURL+" and sign in again", http.StatusBadGateway)
+			return
+		}
 
-		// Store Google token
-		s.mu.Lock()
-		s.googleToken = token
-		s.mu.Unlock()
-
-		s.logger.Info("Google token obtained successfully")
-
-		// Generate MCP auth code
+		// Store Google token under a new grant for this authorization, together
+		// with the MCP auth code carrying it so cleanup never sees it unused
+		grantID := generateToken(16)
 		mcpCode := generateToken(32)
 		s.mu.Lock()
+		s.googleTokens[grantID] = token
 		s.authCodes[mcpCode] = &authCode{
 			clientID:      pending.clientID,
+			grantID:       grantID,
 			redirectURI:   pending.redirectURI,
 			codeChallenge: pending.codeChallenge,
 			createdAt:     time.Now(),
 		}
+		onGrant := s.onGrant
 		s.mu.Unlock()
 
+		s.logger.Info("Google token obtained successfully", "client_id", pending.clientID)
+		if onGrant != nil {
+			go onGrant(grantID)
+		}
+
 		// Redirect back to client with MCP code
 		redirectURL, err := url.Parse(pending.redirectURI)
 		if err != nil {
@@ -375,6 +447,7 @@ func (s *MCPOAuthServer) TokenHandler() http.HandlerFunc {
 func (s *MCPOAuthServer) handleAuthorizationCodeGrant(w http.ResponseWriter, r *http.Request, clientID string) {
 	code := r.FormValue("code")
 	codeVerifier := r.FormValue("code_verifier")
+	redirectURI := r.FormValue("redirect_uri")
 
 	// Look up and consume auth code (single-use)
 	s.mu.Lock()
@@ -389,8 +462,8 @@ func (s *MCPOAuthServer) handleAuthorizationCodeGrant(w http.ResponseWriter, r *
 		return
 	}
 
-	// Check TTL (10 minutes)
-	if time.Since(ac.createdAt) > 10*time.Minute {
+	// Check TTL
+	if time.Since(ac.createdAt) > s.authCodeTTL {
 		jsonError(w, "invalid_grant", "Authorization code expired", http.StatusBadRequest)
 		return
 	}
@@ -401,13 +474,19 @@ func (s *MCPOAuthServer) handleAuthorizationCodeGrant(w http.ResponseWriter, r *
 		return
 	}
 
+	// Verify redirect_uri matches the authorize request (RFC 6749 section 4.1.3)
+	if redirectURI != ac.redirectURI {
+		jsonError(w, "invalid_grant", "redirect_uri mismatch", http.StatusBadRequest)
+		return
+	}
+
 	// Verify PKCE
 	if !verifyPKCE(codeVerifier, ac.codeChallenge) {
 		jsonError(w, "invalid_grant", "PKCE verification failed", http.StatusBadRequest)
 		return
 	}
 
-	s.issueTokens(w, clientID)
+	s.issueTokens(w, clientID, ac.grantID)
 }
 
 func (s *MCPOAuthServer) handleRefreshTokenGrant(w http.ResponseWriter, r *http.Request, clientID string) {
@@ -430,28 +509,35 @@ func (s *MCPOAuthServer) handleRefreshTokenGrant(w http.ResponseWriter, r *http.
 		return
 	}
 
-	s.issueTokens(w, clientID)
+	if time.Now().After(rtRecord.expiresAt) || s.googleToken(rtRecord.grantID) == nil {
+		jsonError(w, "invalid_grant", "Refresh token expired or revoked", http.StatusBadRequest)
+		return
+	}
+
+	s.issueTokens(w, clientID, rtRecord.grantID)
 }
 
-func (s *MCPOAuthServer) issueTokens(w http.ResponseWriter, clientID string) {
+func (s *MCPOAuthServer) issueTokens(w http.ResponseWriter, clientID, grantID string) {
 	accessTok := generateToken(32)
 	refreshTok := generateToken(32)
-	expiresIn := 3600 // 1 hour
 
 	s.mu.Lock()
 	s.accessTokens[accessTok] = &accessToken{
 		clientID:  clientID,
-		expiresAt: time.Now().Add(time.Duration(expiresIn) * time.Second),
+		grantID:   grantID,
+		expiresAt: time.Now().Add(s.accessTokenTTL),
 	}
 	s.refreshTokens[refreshTok] = &refreshToken{
-		clientID: clientID,
+		clientID:  clientID,
+		grantID:   grantID,
+		expiresAt: time.Now().Add(s.refreshTokenTTL),
 	}
 	s.mu.Unlock()
 
 	resp := map[string]any{
 		"access_token":  accessTok,
 		"token_type":    "Bearer",
-		"expires_in":    expiresIn,
+		"expires_in":    int(s.accessTokenTTL.Seconds()),
 		"refresh_token": refreshTok,
 	}
 
@@ -480,28 +566,94 @@ func (s *MCPOAuthServer) TokenVerifier() mcpauth.TokenVerifier {
 
 		return &mcpauth.TokenInfo{
 			Expiration: at.expiresAt,
+			UserID:     at.grantID,
 		}, nil
 	}
 }
 
-// GetGoogleHTTPClient returns an HTTP client authenticated with the stored Google token.
-func (s *MCPOAuthServer) GetGoogleHTTPClient(ctx context.Context) (*http.Client, error) {
+// GetGoogleHTTPClient returns an HTTP client authenticated with the Google
+// token of userID, the user ID the TokenVerifier reported for a bearer token,
+// and a reporter on the token the client uses. Refreshed tokens are stored
+// back under the grant while it exists.
+func (s *MCPOAuthServer) GetGoogleHTTPClient(ctx context.Context, userID string) (*http.Client, TokenStatusReporter, error) {
+	token := s.googleToken(userID)
+	if token == nil {
+		return nil, nil, fmt.Errorf("no Google token available")
+	}
+
+	source := &grantTokenSource{
+		server:  s,
+		grantID: userID,
+		base:    newTokenSource(ctx, s.googleCfg, token),
+	}
+	return oauth2.NewClient(ctx, source), source, nil
+}
+
+// OnGrant registers fn to be called, in its own goroutine, with the grant ID
+// (the user ID of the MCP tokens issued for it) of each completed Google
+// authorization. It never delays the redirect back to the client.
+func (s *MCPOAuthServer) OnGrant(fn func(grantID string)) {
 	s.mu.Lock()
-	token := s.googleToken
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+	s.onGrant = fn
+}
 
-	if token == nil {
-		return nil, fmt.Errorf("no Google token available")
+// OnGrantRemoved registers fn to be called, in its own goroutine, with the
+// grant ID of each grant that is pruned or revoked, so per-user state built
+// for it can be dropped.
+func (s *MCPOAuthServer) OnGrantRemoved(fn func(grantID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onGrantRemoved = fn
+}
+
+// RevokeGrant drops the grant of userID with its Google token and every MCP
+// code and token carrying it, so its clients must authorize again.
+func (s *MCPOAuthServer) RevokeGrant(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.authCodes {
+		if v.grantID == userID {
+			delete(s.authCodes, k)
+		}
+	}
+	for k, v := range s.accessTokens {
+		if v.grantID == userID {
+			delete(s.accessTokens, k)
+		}
+	}
+	for k, v := range s.refreshTokens {
+		if v.grantID == userID {
+			delete(s.refreshTokens, k)
+		}
 	}
+	s.removeGrantLocked(userID)
+}
 
-	return s.googleCfg.Client(ctx, token), nil
+// removeGrantLocked drops the Google token of grantID and reports it to the
+// OnGrantRemoved callback. s.mu must be held.
+func (s *MCPOAuthServer) removeGrantLocked(grantID string) {
+	if _, ok := s.googleTokens[grantID]; !ok {
+		return
+	}
+	delete(s.googleTokens, grantID)
+	if s.onGrantRemoved != nil {
+		go s.onGrantRemoved(grantID)
+	}
 }
 
-// HasGoogleToken reports whether a Google token has been stored.
+// HasGoogleToken reports whether any user has stored a Google token.
 func (s *MCPOAuthServer) HasGoogleToken() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.googleToken != nil
+	return len(s.googleTokens) > 0
+}
+
+// googleToken returns the Google token of userID, or nil if there is none.
+func (s *MCPOAuthServer) googleToken(userID string) *oauth2.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.googleTokens[userID]
 }
 
 // StartCleanup runs a background goroutine that prunes expired state every 5 minutes.
@@ -527,12 +679,12 @@ func (s *MCPOAuthServer) cleanup() {
 	defer s.mu.Unlock()
 
 	for k, v := range s.pendingAuths {
-		if now.Sub(v.createdAt) > 10*time.Minute {
+		if now.Sub(v.createdAt) > pendingAuthTTL {
 			delete(s.pendingAuths, k)
 		}
 	}
 	for k, v := range s.authCodes {
-		if now.Sub(v.createdAt) > 10*time.Minute {
+		if now.Sub(v.createdAt) > s.authCodeTTL {
 			delete(s.authCodes, k)
 		}
 	}
@@ -541,6 +693,28 @@ func (s *MCPOAuthServer) cleanup() {
 			delete(s.accessTokens, k)
 		}
 	}
+	for k, v := range s.refreshTokens {
+		if now.After(v.expiresAt) {
+			delete(s.refreshTokens, k)
+		}
+	}
+
+	// Drop the grants no code or token carries any more
+	live := make(map[string]struct{}, len(s.googleTokens))
+	for _, v := range s.authCodes {
+		live[v.grantID] = struct{}{}
+	}
+	for _, v := range s.accessTokens {
+		live[v.grantID] = struct{}{}
+	}
+	for _, v := range s.refreshTokens {
+		live[v.grantID] = struct{}{}
+	}
+	for grantID := range s.googleTokens {
+		if _, ok := live[grantID]; !ok {
+			s.removeGrantLocked(grantID)
+		}
+	}
 }
 
 // verifyPKCE checks that SHA256(verifier) base64url-encoded matches the challenge.
@@ -0,0 +1,389 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"golang.org/x/oauth2"
+)
+
+// fakeGoogle serves Google's token endpoint, issuing "google-access-<code>"
+// for the authorization code <code> and "google-access-<refresh token>" on a
+// refresh, and an API endpoint that echoes the bearer token it was called with.
+func fakeGoogle(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		code := r.FormValue("code")
+		if r.FormValue("grant_type") == "refresh_token" {
+			code = r.FormValue("refresh_token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "google-access-" + code,
+			"refresh_token": "google-refresh-" + code,
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("GET /whoami", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestOAuthServer(google *httptest.Server) *MCPOAuthServer {
+	return newTestOAuthServerWithOptions(google, MCPOAuthOptions{})
+}
+
+func newTestOAuthServerWithOptions(google *httptest.Server, opts MCPOAuthOptions) *MCPOAuthServer {
+	googleCfg := &oauth2.Config{
+		ClientID:     "google-client",
+		ClientSecret: "google-secret",
+		RedirectURL:  "https://mcp.example/callback",
+		Endpoint:     oauth2.Endpoint{AuthURL: google.URL + "/auth", TokenURL: google.URL + "/token"},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewMCPOAuthServer("https://mcp.example", googleCfg, logger, opts)
+}
+
+// testRedirectURI is the redirect URI test clients register and authorize with.
+const testRedirectURI = "http://client.example/cb"
+
+// authorizeClient runs the whole MCP OAuth flow for a new client whose user
+// authorizes Google with googleCode, and returns the MCP access token.
+func authorizeClient(t *testing.T, s *MCPOAuthServer, googleCode string) string {
+	t.Helper()
+	client, code := requestAuthCode(t, s, googleCode)
+	rec := exchangeAuthCode(s, client, code, "verifier-"+googleCode, testRedirectURI)
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&tokens); err != nil || tokens.AccessToken == "" {
+		t.Fatalf("token: status %d, err %v", rec.Code, err)
+	}
+	return tokens.AccessToken
+}
+
+// requestAuthCode registers a new client and runs the MCP OAuth flow up to
+// the MCP authorization code, with the user authorizing Google with
+// googleCode and the PKCE verifier "verifier-<googleCode>".
+func requestAuthCode(t *testing.T, s *MCPOAuthServer, googleCode string) (dcrClient, string) {
+	t.Helper()
+
+	// Dynamic client registration
+	rec := httptest.NewRecorder()
+	s.RegisterHandler()(rec, httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"redirect_uris":["`+testRedirectURI+`"]}`)))
+	var client dcrClient
+	if err := json.NewDecoder(rec.Body).Decode(&client); err != nil {
+		t.Fatalf("register: %v (status %d)", err, rec.Code)
+	}
+
+	// Authorize with PKCE; the redirect to Google carries our state
+	sum := sha256.Sum256([]byte("verifier-" + googleCode))
+	q := url.Values{
+		"client_id":             {client.ClientID},
+		"redirect_uri":          {testRedirectURI},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+	rec = httptest.NewRecorder()
+	s.AuthorizeHandler()(rec, httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+	googleURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || rec.Code != http.StatusFound {
+		t.Fatalf("authorize: status %d, location %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// Google calls back with its code; we redirect to the client with an MCP code
+	q = url.Values{"code": {googleCode}, "state": {googleURL.Query().Get("state")}}
+	rec = httptest.NewRecorder()
+	s.GoogleCallbackHandler()(rec, httptest.NewRequest(http.MethodGet, "/callback?"+q.Encode(), nil))
+	clientURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || rec.Code != http.StatusFound {
+		t.Fatalf("callback: status %d, body %q", rec.Code, rec.Body.String())
+	}
+	return client, clientURL.Query().Get("code")
+}
+
+// exchangeAuthCode posts an authorization_code grant to the token endpoint.
+func exchangeAuthCode(s *MCPOAuthServer, client dcrClient, code, verifier, redirectURI string) *httptest.ResponseRecorder {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {client.ClientID},
+		"client_secret": {client.ClientSecret},
+		"code":          {code},
+		"code_verifier": {verifier},
+		"redirect_uri":  {redirectURI},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.TokenHandler()(rec, req)
+	return rec
+}
+
+// googleTokenFor returns the Google access token the HTTP client for the MCP
+// bearer token presents to Google.
+func googleTokenFor(t *testing.T, s *MCPOAuthServer, google *httptest.Server, bearer string) string {
+	t.Helper()
+	info, err := s.TokenVerifier()(context.Background(), bearer, nil)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	httpClient, _, err := s.GetGoogleHTTPClient(context.Background(), info.UserID)
+	if err != nil {
+		t.Fatalf("google client: %v", err)
+	}
+	resp, err := httpClient.Get(google.URL + "/whoami")
+	if err != nil {
+		t.Fatalf("whoami: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestMCPOAuthServerIsolatesGoogleTokens(t *testing.T) {
+	google := fakeGoogle(t)
+	s := newTestOAuthServer(google)
+
+	// Two clients, authorized by different Google users, at the same time
+	users := []string{"alice", "bob"}
+	bearers := make([]string, len(users))
+	t.Run("authorize", func(t *testing.T) {
+		for i, user := range users {
+			t.Run(user, func(t *testing.T) {
+				t.Parallel()
+				bearers[i] = authorizeClient(t, s, user)
+			})
+		}
+	})
+	if t.Failed() {
+		return
+	}
+
+	for i, user := range users {
+		want := "google-access-" + user
+		if got := googleTokenFor(t, s, google, bearers[i]); got != want {
+			t.Errorf("client of %s used Google token %q, want %q", user, got, want)
+		}
+	}
+}
+
+func TestMCPOAuthServerUnknownUserHasNoGoogleToken(t *testing.T) {
+	google := fakeGoogle(t)
+	s := newTestOAuthServer(google)
+	authorizeClient(t, s, "alice")
+
+	if _, _, err := s.GetGoogleHTTPClient(context.Background(), "someone-else"); err == nil {
+		t.Fatal("GetGoogleHTTPClient for an unknown user succeeded, want an error")
+	}
+}
+
+func TestMCPOAuthServerCleanupPrunesUnusedGrants(t *testing.T) {
+	google := fakeGoogle(t)
+	s := newTestOAuthServer(google)
+	removed := make(chan string, 2)
+	s.OnGrantRemoved(func(grantID string) { removed <- grantID })
+
+	idle := authorizeClient(t, s, "idle")
+	active := authorizeClient(t, s, "active")
+	idleInfo, _ := s.TokenVerifier()(context.Background(), idle, nil)
+	activeInfo, _ := s.TokenVerifier()(context.Background(), active, nil)
+
+	// The idle client's tokens all expire; the active one still holds a refresh token
+	s.mu.Lock()
+	for _, v := range s.accessTokens {
+		v.expiresAt = time.Now().Add(-time.Minute)
+	}
+	for _, v := range s.refreshTokens {
+		if v.grantID == idleInfo.UserID {
+			v.expiresAt = time.Now().Add(-time.Minute)
+		}
+	}
+	s.mu.Unlock()
+	s.cleanup()
+
+	if s.googleToken(idleInfo.UserID) != nil {
+		t.Error("idle grant was kept, want it pruned")
+	}
+	if s.googleToken(activeInfo.UserID) == nil {
+		t.Error("grant with a live refresh token was pruned, want it kept")
+	}
+	if got := <-removed; got != idleInfo.UserID {
+		t.Errorf("OnGrantRemoved got %q, want the idle grant %q", got, idleInfo.UserID)
+	}
+}
+
+func TestMCPOAuthServerRevokeGrant(t *testing.T) {
+	google := fakeGoogle(t)
+	s := newTestOAuthServer(google)
+	bearer := authorizeClient(t, s, "alice")
+	info, _ := s.TokenVerifier()(context.Background(), bearer, nil)
+
+	s.RevokeGrant(info.UserID)
+	if _, err := s.TokenVerifier()(context.Background(), bearer, nil); err == nil {
+		t.Error("access token of a revoked grant still verifies")
+	}
+	if s.HasGoogleToken() {
+		t.Error("Google token of a revoked grant is still held")
+	}
+	s.mu.Lock()
+	refreshTokens := len(s.refreshTokens)
+	s.mu.Unlock()
+	if refreshTokens != 0 {
+		t.Errorf("%d refresh tokens left, want the revoked grant's dropped", refreshTokens)
+	}
+}
+
+func TestGoogleTokenStatusUsesTheClientTokenSource(t *testing.T) {
+	var refreshes atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		// The first token expires within ExpiryMargin, so it is refreshed before use
+		token := map[string]any{"access_token": "google-access-first", "refresh_token": "google-refresh", "token_type": "Bearer", "expires_in": 60}
+		if r.FormValue("grant_type") == "refresh_token" {
+			refreshes.Add(1)
+			token["access_token"], token["expires_in"] = "google-access-refreshed", 3600
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(token)
+	})
+	mux.HandleFunc("GET /whoami", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	})
+	google := httptest.NewServer(mux)
+	t.Cleanup(google.Close)
+	s := newTestOAuthServer(google)
+	bearer := authorizeClient(t, s, "alice")
+	info, _ := s.TokenVerifier()(context.Background(), bearer, nil)
+
+	httpClient, reporter, err := s.GetGoogleHTTPClient(context.Background(), info.UserID)
+	if err != nil {
+		t.Fatalf("google client: %v", err)
+	}
+	status, err := reporter.TokenStatus(context.Background())
+	if err != nil {
+		t.Fatalf("TokenStatus: %v", err)
+	}
+	if time.Until(status.Expiry) < 50*time.Minute {
+		t.Errorf("status expiry %s, want the refreshed token's", status.Expiry)
+	}
+	if got := s.googleToken(info.UserID).AccessToken; got != "google-access-refreshed" {
+		t.Errorf("stored Google token %q, want the refreshed one", got)
+	}
+
+	// The client serves the token the status refreshed, without refreshing again
+	resp, err := httpClient.Get(google.URL + "/whoami")
+	if err != nil {
+		t.Fatalf("whoami: %v", err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "google-access-refreshed" {
+		t.Errorf("client used %q, want google-access-refreshed", body)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("%d refreshes, want 1", n)
+	}
+}
+
+func TestGoogleCallbackRejectsExpiredPendingAuth(t *testing.T) {
+	s := newTestOAuthServer(fakeGoogle(t))
+	pending := func(age time.Duration) *pendingAuth {
+		return &pendingAuth{
+			clientID:      "client",
+			redirectURI:   "http://client.example/cb",
+			codeChallenge: "challenge",
+			createdAt:     time.Now().Add(-age),
+		}
+	}
+	// cleanup has not run, so only the callback's own age check can reject
+	s.mu.Lock()
+	s.pendingAuths["stale-state"] = pending(pendingAuthTTL + time.Minute)
+	s.pendingAuths["fresh-state"] = pending(pendingAuthTTL - time.Minute)
+	s.mu.Unlock()
+
+	rec := httptest.NewRecorder()
+	s.GoogleCallbackHandler()(rec, httptest.NewRequest(http.MethodGet, "/callback?code=alice&state=stale-state", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "expired") {
+		t.Errorf("stale state: status %d, body %q, want 400 expired", rec.Code, rec.Body.String())
+	}
+	s.mu.Lock()
+	grants := len(s.googleTokens)
+	s.mu.Unlock()
+	if grants != 0 {
+		t.Errorf("grants after a stale callback = %d, want 0", grants)
+	}
+
+	rec = httptest.NewRecorder()
+	s.GoogleCallbackHandler()(rec, httptest.NewRequest(http.MethodGet, "/callback?code=alice&state=fresh-state", nil))
+	if rec.Code != http.StatusFound {
+		t.Errorf("fresh state: status %d, body %q, want 302", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAuthorizationCodeGrantRejectsRedirectURIMismatch(t *testing.T) {
+	s := newTestOAuthServer(fakeGoogle(t))
+	client, code := requestAuthCode(t, s, "alice")
+
+	rec := exchangeAuthCode(s, client, code, "verifier-alice", "http://attacker.example/cb")
+	var resp struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if rec.Code != http.StatusBadRequest || resp.Error != "invalid_grant" {
+		t.Errorf("mismatched redirect_uri: status %d, error %q, want 400 invalid_grant", rec.Code, resp.Error)
+	}
+}
+
+func TestMCPOAuthServerShortTTLs(t *testing.T) {
+	s := newTestOAuthServerWithOptions(fakeGoogle(t), MCPOAuthOptions{AccessTokenTTL: time.Second, AuthCodeTTL: time.Second})
+
+	client, code := requestAuthCode(t, s, "alice")
+	rec := exchangeAuthCode(s, client, code, "verifier-alice", testRedirectURI)
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&tokens); err != nil {
+		t.Fatalf("token: status %d, err %v", rec.Code, err)
+	}
+	if tokens.ExpiresIn != 1 {
+		t.Errorf("expires_in = %d, want 1", tokens.ExpiresIn)
+	}
+	if _, err := s.TokenVerifier()(context.Background(), tokens.AccessToken, nil); err != nil {
+		t.Fatalf("fresh access token rejected: %v", err)
+	}
+	client, unused := requestAuthCode(t, s, "bob")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	if _, err := s.TokenVerifier()(context.Background(), tokens.AccessToken, nil); !errors.Is(err, mcpauth.ErrInvalidToken) {
+		t.Errorf("access token past its TTL: err = %v, want invalid token", err)
+	}
+	rec = exchangeAuthCode(s, client, unused, "verifier-bob", testRedirectURI)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "expired") {
+		t.Errorf("auth code past its TTL: status %d, body %q, want 400 expired", rec.Code, rec.Body.String())
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -14,55 +15,110 @@ import (
 )
 
 // NewOAuth2Config creates a new OAuth2 configuration for Google YouTube API.
-func NewOAuth2Config(clientID, clientSecret, redirectURL string) *oauth2.Config {
+// When readOnly is true only the youtube.readonly scope is requested, so the
+// resulting token cannot modify the user's library.
+func NewOAuth2Config(clientID, clientSecret, redirectURL string, readOnly bool) *oauth2.Config {
 	return &oauth2.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		RedirectURL:  redirectURL,
 		Endpoint:     google.Endpoint,
-		Scopes:       []string{youtube.YoutubeScope},
+		Scopes:       Scopes(readOnly),
 	}
 }
 
+// Scopes returns the Google OAuth scopes to request: read-only or full read/write access.
+func Scopes(readOnly bool) []string {
+	if readOnly {
+		return []string{youtube.YoutubeReadonlyScope}
+	}
+	return []string{youtube.YoutubeScope}
+}
+
+// authCodeOptions returns the AuthCodeURL options of both OAuth flows. Offline
+// access asks for a refresh token, but Google only issues one with the consent
+// screen, so it is forced unless skipConsent is set: returning users then skip
+// the screen, at the risk of getting no refresh token.
+func authCodeOptions(skipConsent bool) []oauth2.AuthCodeOption {
+	opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline}
+	if !skipConsent {
+		opts = append(opts, oauth2.SetAuthURLParam("prompt", "consent"))
+	}
+	return opts
+}
+
 // Authenticate performs OAuth2 authentication, either by loading a saved token
-// or initiating a web-based OAuth2 flow with a local callback server.
-// Returns an authenticated HTTP client.
-func Authenticate(ctx context.Context, cfg *oauth2.Config, storage TokenStorage, port int, logger *slog.Logger) (*http.Client, error) {
+// or initiating a web-based OAuth2 flow with a local callback server. With
+// port 0 the callback server is skipped and the user pastes the code on stdin
+// instead, for machines without a browser.
+// Returns an authenticated HTTP client and the token source backing it.
+func Authenticate(ctx context.Context, cfg *oauth2.Config, storage TokenStorage, port int, logger *slog.Logger) (*http.Client, *PersistingTokenSource, error) {
+	return AuthenticateWithOptions(ctx, cfg, storage, logger, AuthOptions{Port: port})
+}
+
+// AuthOptions configures the OAuth flow of AuthenticateWithOptions.
+type AuthOptions struct {
+	// Port is the local callback server's port; 0 reads the code from stdin.
+	Port int
+
+	// OnAuthURL, if non-nil, receives the authorization URL when the web flow
+	// is needed, so it can be shown somewhere other than stderr.
+	OnAuthURL func(authURL string)
+
+	// RequireRefreshToken fails authentication, instead of only warning, when
+	// the token has no refresh token.
+	RequireRefreshToken bool
+
+	// SkipConsent leaves out prompt=consent, so users who already granted
+	// access are not shown the consent screen again; see authCodeOptions.
+	SkipConsent bool
+}
+
+// AuthenticateWithOptions is Authenticate with the flow configured by opts.
+func AuthenticateWithOptions(ctx context.Context, cfg *oauth2.Config, storage TokenStorage, logger *slog.Logger, opts AuthOptions) (*http.Client, *PersistingTokenSource, error) {
+	port, onAuthURL := opts.Port, opts.OnAuthURL
+
 	// Try to load saved token
 	token, err := storage.Load()
 	if err == nil {
 		// Token loaded successfully - create client with persisting token source
 		logger.Info("Loaded token from storage")
-		baseSource := cfg.TokenSource(ctx, token)
-		persistingSource := NewPersistingTokenSource(baseSource, storage, logger)
-		return oauth2.NewClient(ctx, persistingSource), nil
+		if err := checkRefreshToken(token, "storage", opts.RequireRefreshToken, logger); err != nil {
+			return nil, nil, fmt.Errorf("%w; delete the saved token (or OAUTH_TOKEN_JSON) to authorize again", err)
+		}
+		baseSource := newTokenSource(ctx, cfg, token)
+		persistingSource := newPersistingTokenSource(baseSource, storage, logger, token)
+		return oauth2.NewClient(ctx, persistingSource), persistingSource, nil
 	}
 
 	logger.Info("No saved token found, starting OAuth2 flow", "error", err.Error())
 
-	// No saved token - start OAuth2 web flow
-	authURL := cfg.AuthCodeURL("state",
-		oauth2.AccessTypeOffline,
-		oauth2.SetAuthURLParam("prompt", "consent"), // Force refresh token on re-auth
-	)
+	// No saved token - start OAuth2 web flow with a random state to prevent CSRF
+	state := generateToken(16)
+	stateIssuedAt := time.Now()
+	authURL := cfg.AuthCodeURL(state, authCodeOptions(opts.SkipConsent)...)
 
 	fmt.Fprintf(os.Stderr, "\nVisit this URL to authorize:\n%s\n\n", authURL)
+	if onAuthURL != nil {
+		onAuthURL(authURL)
+	}
+
+	// Manual flow: no callback server, the user copies the code from the browser
+	if port == 0 {
+		fmt.Fprintf(os.Stderr, "After authorizing, the browser is redirected to %s, which may fail to load.\n", cfg.RedirectURL)
+		code, err := readAuthCode(ctx, os.Stdin, os.Stderr, state)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ExchangeAndSave(ctx, cfg, code, storage, opts.RequireRefreshToken, logger)
+	}
 
 	// Start local callback server
 	codeCh := make(chan string, 1)
 	errCh := make(chan error, 1)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			errCh <- fmt.Errorf("no authorization code in callback")
-			http.Error(w, "Authorization failed: no code", http.StatusBadRequest)
-			return
-		}
-		codeCh <- code
-		fmt.Fprintf(w, "Authorization successful! You can close this window.")
-	})
+	mux.HandleFunc("/callback", callbackHandler(state, stateIssuedAt, codeCh, errCh, logger))
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
@@ -84,9 +140,9 @@ func Authenticate(ctx context.Context, cfg *oauth2.Config, storage TokenStorage,
 	case code = <-codeCh:
 		logger.Info("Received authorization code")
 	case err := <-errCh:
-		return nil, err
+		return nil, nil, err
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
 	}
 
 	// Shut down callback server
@@ -97,19 +153,51 @@ func Authenticate(ctx context.Context, cfg *oauth2.Config, storage TokenStorage,
 	}
 
 	// Exchange authorization code for token and save
-	return ExchangeAndSave(ctx, cfg, code, storage, logger)
+	return ExchangeAndSave(ctx, cfg, code, storage, opts.RequireRefreshToken, logger)
+}
+
+// callbackHandler serves the local flow's /callback: it sends the code to codeCh
+// once the state matches the one issued at issuedAt, answering forged or stale
+// callbacks with 400 without aborting the flow.
+func callbackHandler(state string, issuedAt time.Time, codeCh chan<- string, errCh chan<- error, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Reject forged or stale callbacks without aborting the flow
+		callbackState := r.URL.Query().Get("state")
+		if subtle.ConstantTimeCompare([]byte(callbackState), []byte(state)) != 1 {
+			logger.Warn("Rejected OAuth callback with invalid state")
+			http.Error(w, "Authorization failed: invalid state", http.StatusBadRequest)
+			return
+		}
+		if time.Since(issuedAt) > 10*time.Minute {
+			http.Error(w, "Authorization failed: state expired, restart the server to retry", http.StatusBadRequest)
+			return
+		}
+
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			errCh <- fmt.Errorf("no authorization code in callback")
+			http.Error(w, "Authorization failed: no code", http.StatusBadRequest)
+			return
+		}
+		codeCh <- code
+		fmt.Fprintf(w, "Authorization successful! You can close this window.")
+	}
 }
 
 // ExchangeAndSave exchanges an authorization code for a token, saves it to storage,
-// and returns an authenticated HTTP client. It is used both by the local OAuth callback
+// and returns an authenticated HTTP client and its token source. It is used both by the local OAuth callback
 // server (in Authenticate) and by the server-side /callback HTTP handler.
-func ExchangeAndSave(ctx context.Context, cfg *oauth2.Config, code string, storage TokenStorage, logger *slog.Logger) (*http.Client, error) {
+// A token without a refresh token is logged, or rejected if requireRefreshToken is set.
+func ExchangeAndSave(ctx context.Context, cfg *oauth2.Config, code string, storage TokenStorage, requireRefreshToken bool, logger *slog.Logger) (*http.Client, *PersistingTokenSource, error) {
 	token, err := cfg.Exchange(ctx, code)
 	if err != nil {
-		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
+		return nil, nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
 
 	logger.Info("Successfully exchanged code for token")
+	if err := checkRefreshToken(token, "exchange", requireRefreshToken, logger); err != nil {
+		return nil, nil, err
+	}
 
 	// Save token
 	if err := storage.Save(token); err != nil {
@@ -120,7 +208,42 @@ func ExchangeAndSave(ctx context.Context, cfg *oauth2.Config, code string, stora
 	}
 
 	// Create client with persisting token source
-	baseSource := cfg.TokenSource(ctx, token)
-	persistingSource := NewPersistingTokenSource(baseSource, storage, logger)
-	return oauth2.NewClient(ctx, persistingSource), nil
+	baseSource := newTokenSource(ctx, cfg, token)
+	persistingSource := newPersistingTokenSource(baseSource, storage, logger, token)
+	return oauth2.NewClient(ctx, persistingSource), persistingSource, nil
+}
+
+// ExpiryMargin is how long before its expiry an access token is refreshed, so
+// it does not expire in the middle of a request. oauth2 alone refreshes only
+// 10 seconds early.
+const ExpiryMargin = 5 * time.Minute
+
+// newTokenSource returns a token source for cfg that starts from token and
+// refreshes it ExpiryMargin before it expires.
+func newTokenSource(ctx context.Context, cfg *oauth2.Config, token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.ReuseTokenSourceWithExpiry(token, &refreshTokenSource{
+		ctx:          ctx,
+		cfg:          cfg,
+		refreshToken: token.RefreshToken,
+	}, ExpiryMargin)
+}
+
+// refreshTokenSource fetches a new access token with the refresh token on
+// every call. cfg.TokenSource can't serve this: it reuses its token until the
+// last 10 seconds, which would defeat ExpiryMargin. Calls are serialized by
+// the wrapping ReuseTokenSource.
+type refreshTokenSource struct {
+	ctx          context.Context
+	cfg          *oauth2.Config
+	refreshToken string
+}
+
+// Token refreshes the access token, remembering a rotated refresh token.
+func (r *refreshTokenSource) Token() (*oauth2.Token, error) {
+	token, err := r.cfg.TokenSource(r.ctx, &oauth2.Token{RefreshToken: r.refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	r.refreshToken = token.RefreshToken
+	return token, nil
 }
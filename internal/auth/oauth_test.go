@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestNewTokenSourceRefreshesWithinExpiryMargin(t *testing.T) {
+	google := fakeGoogle(t)
+	cfg := &oauth2.Config{
+		ClientID:     "google-client",
+		ClientSecret: "google-secret",
+		Endpoint:     oauth2.Endpoint{TokenURL: google.URL + "/token"},
+	}
+
+	tests := []struct {
+		name    string
+		expires time.Duration
+		want    string
+	}{
+		{"outside margin", ExpiryMargin + time.Minute, "current"},
+		{"inside margin", ExpiryMargin - time.Minute, "google-access-rotating"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &oauth2.Token{
+				AccessToken:  "current",
+				RefreshToken: "rotating",
+				Expiry:       time.Now().Add(tt.expires),
+			}
+			got, err := newTokenSource(context.Background(), cfg, token).Token()
+			if err != nil {
+				t.Fatalf("Token: %v", err)
+			}
+			if got.AccessToken != tt.want {
+				t.Errorf("access token %q, want %q", got.AccessToken, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackHandlerRejectsInvalidState(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	handler := callbackHandler("good-state", time.Now(), codeCh, errCh, logger)
+
+	for _, query := range []string{"?code=c1&state=bad-state", "?code=c1", "?code=c1&state="} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/callback"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("callback%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+	select {
+	case code := <-codeCh:
+		t.Fatalf("code %q accepted from a callback with an invalid state", code)
+	case err := <-errCh:
+		t.Fatalf("invalid state aborted the flow: %v", err)
+	default:
+	}
+
+	// The genuine callback still completes the flow
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/callback?code=c1&state=good-state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback with the issued state: status = %d, want 200", rec.Code)
+	}
+	if code := <-codeCh; code != "c1" {
+		t.Errorf("code = %q, want c1", code)
+	}
+}
+
+func TestCallbackHandlerRejectsStaleState(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	codeCh := make(chan string, 1)
+	handler := callbackHandler("good-state", time.Now().Add(-11*time.Minute), codeCh, make(chan error, 1), logger)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/callback?code=c1&state=good-state", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	if len(codeCh) != 0 {
+		t.Error("code accepted from a stale callback")
+	}
+}
+
+func TestAuthCodeOptions(t *testing.T) {
+	cfg := &oauth2.Config{ClientID: "google-client", Endpoint: oauth2.Endpoint{AuthURL: "https://accounts.example/auth"}}
+
+	tests := []struct {
+		skipConsent bool
+		wantPrompt  string
+	}{
+		{false, "consent"},
+		{true, ""},
+	}
+	for _, tt := range tests {
+		authURL, err := url.Parse(cfg.AuthCodeURL("state", authCodeOptions(tt.skipConsent)...))
+		if err != nil {
+			t.Fatalf("parse auth URL: %v", err)
+		}
+		q := authURL.Query()
+		if got := q.Get("access_type"); got != "offline" {
+			t.Errorf("skipConsent %v: access_type = %q, want offline", tt.skipConsent, got)
+		}
+		if got := q.Get("prompt"); got != tt.wantPrompt {
+			t.Errorf("skipConsent %v: prompt = %q, want %q", tt.skipConsent, got, tt.wantPrompt)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrNoRefreshToken means a Google token has no refresh token, so it stops
+// working once its access token expires, within about an hour.
+var ErrNoRefreshToken = errors.New("OAuth token has no refresh token")
+
+// revokeAccessURL is where users remove the app's access, so the next
+// authorization issues a fresh refresh token.
+const revokeAccessURL = "https://myaccount.google.com/permissions"
+
+// checkRefreshToken logs a prominent warning when token, obtained from source,
+// has no refresh token, or returns ErrNoRefreshToken instead if require is set.
+func checkRefreshToken(token *oauth2.Token, source string, require bool, logger *slog.Logger) error {
+	if token.RefreshToken != "" {
+		return nil
+	}
+	if require {
+		return fmt.Errorf("%w (from %s); remove the app's access at %s and authorize again", ErrNoRefreshToken, source, revokeAccessURL)
+	}
+	logger.Warn("OAUTH TOKEN HAS NO REFRESH TOKEN: YouTube access will stop when it expires; remove the app's access at "+revokeAccessURL+" and authorize again",
+		"source", source, "expiry", token.Expiry)
+	return nil
+}
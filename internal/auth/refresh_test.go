@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestCheckRefreshToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    *oauth2.Token
+		require  bool
+		wantErr  error
+		wantWarn bool
+	}{
+		{"has refresh token", &oauth2.Token{AccessToken: "a", RefreshToken: "r"}, false, nil, false},
+		{"has refresh token, required", &oauth2.Token{AccessToken: "a", RefreshToken: "r"}, true, nil, false},
+		{"no refresh token", &oauth2.Token{AccessToken: "a"}, false, nil, true},
+		{"no refresh token, required", &oauth2.Token{AccessToken: "a"}, true, ErrNoRefreshToken, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+			err := checkRefreshToken(tt.token, "exchange", tt.require, logger)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			warned := strings.Contains(logs.String(), "level=WARN") && strings.Contains(logs.String(), "NO REFRESH TOKEN")
+			if warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v; logs: %s", warned, tt.wantWarn, logs.String())
+			}
+			if tt.wantWarn && !strings.Contains(logs.String(), "source=exchange") {
+				t.Errorf("warning does not name the token's source: %s", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// testingRefreshTokenLifetime is how long Google keeps refresh tokens valid for
+// OAuth apps whose consent screen is still in "Testing" publishing status.
+const testingRefreshTokenLifetime = 7 * 24 * time.Hour
+
+// accessTokenLifetime is the lifetime Google assigns to access tokens. Used to
+// estimate when a token was originally issued from its expiry.
+const accessTokenLifetime = time.Hour
+
+// TokenStatus describes the validity of the current Google OAuth token.
+type TokenStatus struct {
+	// Expiry is when the current access token expires.
+	Expiry time.Time
+
+	// HasRefreshToken reports whether a refresh token is available.
+	HasRefreshToken bool
+
+	// RefreshPersisted reports whether refreshed tokens survive a process restart.
+	RefreshPersisted bool
+
+	// Storage names where the token is kept: "file", "env", or "memory".
+	Storage string
+
+	// ReauthEstimate is the estimated time re-authentication will be needed.
+	// Zero when no re-authentication is expected.
+	ReauthEstimate time.Time
+
+	// ReauthReason explains the ReauthEstimate.
+	ReauthReason string
+}
+
+// TokenStatusReporter reports the status of the current OAuth token.
+// Implementations may refresh the token, but never call the YouTube API.
+type TokenStatusReporter interface {
+	TokenStatus(ctx context.Context) (*TokenStatus, error)
+}
+
+// TokenStatus refreshes the token if needed and reports its validity.
+func (p *PersistingTokenSource) TokenStatus(_ context.Context) (*TokenStatus, error) {
+	token, err := p.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	status := &TokenStatus{
+		Expiry:          token.Expiry,
+		HasRefreshToken: token.RefreshToken != "",
+	}
+
+	switch storage := p.storage.(type) {
+	case *FileTokenStorage:
+		status.Storage = "file"
+		status.RefreshPersisted = true
+	case *EnvTokenStorage:
+		status.Storage = "env"
+		// The original OAUTH_TOKEN_JSON is reloaded on every restart, so its
+		// issue time bounds the refresh token's lifetime for testing-mode apps.
+		if original, err := storage.Load(); err == nil && !original.Expiry.IsZero() && status.HasRefreshToken {
+			status.ReauthEstimate = original.Expiry.Add(-accessTokenLifetime).Add(testingRefreshTokenLifetime)
+			status.ReauthReason = "refreshes are not persisted to OAUTH_TOKEN_JSON; if the OAuth app is in testing mode, Google revokes its refresh token 7 days after issue"
+		}
+	case *MemoryTokenStorage:
+		status.Storage = "memory"
+	}
+
+	if !status.HasRefreshToken {
+		status.ReauthEstimate = token.Expiry
+		status.ReauthReason = "no refresh token; re-authentication is required once the access token expires"
+	}
+
+	return status, nil
+}
+
+// grantTokenSource is the token source of a Google HTTP client for one grant
+// of an MCPOAuthServer. It stores refreshed tokens back under the grant, unless
+// the grant has been dropped meanwhile, and reports on the token it serves.
+type grantTokenSource struct {
+	server  *MCPOAuthServer
+	grantID string
+	base    oauth2.TokenSource
+}
+
+// Token returns the current token, refreshing it if needed.
+func (g *grantTokenSource) Token() (*oauth2.Token, error) {
+	token, err := g.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s := g.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok := s.googleTokens[g.grantID]; ok && stored.AccessToken != token.AccessToken {
+		s.googleTokens[g.grantID] = token
+	}
+	return token, nil
+}
+
+// TokenStatus refreshes the grant's Google token if needed, exactly as the
+// HTTP client would, and reports its validity.
+func (g *grantTokenSource) TokenStatus(_ context.Context) (*TokenStatus, error) {
+	token, err := g.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh Google token: %w", err)
+	}
+
+	status := &TokenStatus{
+		Expiry:          token.Expiry,
+		HasRefreshToken: token.RefreshToken != "",
+		Storage:         "memory",
+		ReauthReason:    "the Google token is held in memory; re-authentication is required after a server restart",
+	}
+	if !status.HasRefreshToken {
+		status.ReauthEstimate = token.Expiry
+		status.ReauthReason = "no refresh token; re-authentication is required once the access token expires"
+	}
+
+	return status, nil
+}
+
+// Verify interfaces are implemented at compile time
+var _ TokenStatusReporter = (*PersistingTokenSource)(nil)
+var _ TokenStatusReporter = (*grantTokenSource)(nil)
@@ -139,10 +139,17 @@ type PersistingTokenSource struct {
 
 // NewPersistingTokenSource creates a new PersistingTokenSource.
 func NewPersistingTokenSource(base oauth2.TokenSource, storage TokenStorage, logger *slog.Logger) *PersistingTokenSource {
+	return newPersistingTokenSource(base, storage, logger, nil)
+}
+
+// newPersistingTokenSource creates a PersistingTokenSource whose storage
+// already holds current, so only a token base rotates to is saved.
+func newPersistingTokenSource(base oauth2.TokenSource, storage TokenStorage, logger *slog.Logger, current *oauth2.Token) *PersistingTokenSource {
 	return &PersistingTokenSource{
-		base:    base,
-		storage: storage,
-		logger:  logger,
+		base:      base,
+		storage:   storage,
+		logger:    logger,
+		lastToken: current,
 	}
 }
 
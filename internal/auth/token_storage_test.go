@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeTokenSource returns its tokens in order, repeating the last one.
+type fakeTokenSource struct {
+	mu     sync.Mutex
+	tokens []*oauth2.Token
+}
+
+func (f *fakeTokenSource) Token() (*oauth2.Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	token := f.tokens[0]
+	if len(f.tokens) > 1 {
+		f.tokens = f.tokens[1:]
+	}
+	return token, nil
+}
+
+// countingStorage records the tokens saved to it.
+type countingStorage struct {
+	MemoryTokenStorage
+	mu    sync.Mutex
+	saved []string
+}
+
+func (c *countingStorage) Save(token *oauth2.Token) error {
+	c.mu.Lock()
+	c.saved = append(c.saved, token.AccessToken)
+	c.mu.Unlock()
+	return c.MemoryTokenStorage.Save(token)
+}
+
+func TestPersistingTokenSourceSavesRotationOnce(t *testing.T) {
+	t1 := &oauth2.Token{AccessToken: "t1"}
+	t2 := &oauth2.Token{AccessToken: "t2"}
+	base := &fakeTokenSource{tokens: []*oauth2.Token{t1, t2}}
+	storage := &countingStorage{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	source := newPersistingTokenSource(base, storage, logger, t1)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := source.Token(); err != nil {
+				t.Errorf("Token: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(storage.saved) != 1 || storage.saved[0] != "t2" {
+		t.Fatalf("saved %v, want exactly [t2]", storage.saved)
+	}
+	if got, _ := storage.Load(); got.AccessToken != "t2" {
+		t.Errorf("storage holds %q, want t2", got.AccessToken)
+	}
+}
+
+func TestPersistingTokenSourceSavesFirstTokenWithoutCurrent(t *testing.T) {
+	base := &fakeTokenSource{tokens: []*oauth2.Token{{AccessToken: "t1"}}}
+	storage := &countingStorage{}
+	source := NewPersistingTokenSource(base, storage, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for range 3 {
+		if _, err := source.Token(); err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+	}
+	if len(storage.saved) != 1 || storage.saved[0] != "t1" {
+		t.Fatalf("saved %v, want exactly [t1]", storage.saved)
+	}
+}
@@ -1,6 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
 )
@@ -17,8 +22,31 @@ type Config struct {
 	OAuthRedirectURL string `env:"OAUTH_REDIRECT_URL" envDefault:"http://localhost:8080/callback"`
 
 	// OAuthPort is the port for the local OAuth callback server (default: 8080).
+	// 0 skips the callback server, like ManualAuth.
 	OAuthPort int `env:"OAUTH_PORT" envDefault:"8080"`
 
+	// ManualAuth makes the stdio OAuth flow read the authorization code pasted
+	// on stdin instead of running the local callback server, for machines
+	// without a browser (default: false). Run the server once in a terminal to
+	// save the token.
+	ManualAuth bool `env:"MANUAL_AUTH" envDefault:"false"`
+
+	// RequireRefreshToken rejects Google tokens without a refresh token, which
+	// stop working within an hour, instead of only logging a warning
+	// (default: false).
+	RequireRefreshToken bool `env:"REQUIRE_REFRESH_TOKEN" envDefault:"false"`
+
+	// ForceConsent shows Google's consent screen on every authorization, the
+	// only way to be sure a refresh token is issued (default: true). Disabling
+	// it spares returning users the screen, but a user who granted access
+	// before may then get a token without a refresh token.
+	ForceConsent bool `env:"FORCE_CONSENT" envDefault:"true"`
+
+	// StdioDeferAuth makes stdio mode serve MCP immediately and run the OAuth
+	// flow in the background; until it completes, tools return the URL to visit
+	// (default: false, authenticate before serving).
+	StdioDeferAuth bool `env:"STDIO_DEFER_AUTH" envDefault:"false"`
+
 	// BaseURL is the public base URL of the server (required for SSE mode).
 	// Example: https://youtube-music-mcp-production.up.railway.app
 	BaseURL string `env:"BASE_URL"`
@@ -30,10 +58,139 @@ type Config struct {
 	// Port is the HTTP port for SSE transport (Railway sets this automatically).
 	Port int `env:"PORT" envDefault:"8080"`
 
+	// BindAddress is the interface the SSE server listens on, e.g. "127.0.0.1"
+	// for local-only access. Empty (default) listens on all interfaces.
+	BindAddress string `env:"BIND_ADDRESS"`
+
+	// TLSCertFile and TLSKeyFile, when both set, make the SSE server serve HTTPS
+	// itself. Leave them unset behind a TLS-terminating proxy such as Railway's.
+	TLSCertFile string `env:"TLS_CERT_FILE"`
+	TLSKeyFile  string `env:"TLS_KEY_FILE"`
+
 	// TokenJSON is the raw JSON of an OAuth token for environments without
 	// filesystem token storage (e.g., Railway). When set, FileTokenStorage
 	// is not used.
 	TokenJSON string `env:"OAUTH_TOKEN_JSON"`
+
+	// CacheTTL is how long liked videos and subscriptions are cached between
+	// tool calls (default: 5m). Set to 0 to disable caching.
+	CacheTTL time.Duration `env:"CACHE_TTL" envDefault:"5m"`
+
+	// DedupStrategy is the default deduplication strategy for recommend-playlist:
+	// "title" (default) also collapses different uploads with the same normalized
+	// title and channel, "id" collapses identical video IDs only.
+	DedupStrategy string `env:"DEDUP_STRATEGY" envDefault:"title"`
+
+	// APITimeout bounds each YouTube API request, or each page of a paginated
+	// listing (default: 30s). A negative value disables the timeout.
+	APITimeout time.Duration `env:"API_TIMEOUT" envDefault:"30s"`
+
+	// APIRateLimit caps YouTube API requests per second per user, so bursts of
+	// concurrent tool calls don't trip YouTube's rate limits (default: 10).
+	// Zero disables the limit.
+	APIRateLimit float64 `env:"API_RATE_LIMIT" envDefault:"10"`
+
+	// DailyQuota is the YouTube Data API daily quota of the Google Cloud project
+	// (default: 10000). Used to report usage and warn before it runs out.
+	DailyQuota int `env:"YOUTUBE_DAILY_QUOTA" envDefault:"10000"`
+
+	// QuotaWarningThreshold is the fraction of DailyQuota after which tool
+	// outputs carry a quota warning (default: 0.8).
+	QuotaWarningThreshold float64 `env:"QUOTA_WARNING_THRESHOLD" envDefault:"0.8"`
+
+	// QuotaWarnings enables the quota warning in tool outputs (default: true).
+	QuotaWarnings bool `env:"QUOTA_WARNINGS" envDefault:"true"`
+
+	// QuotaCosts overrides the quota cost of YouTube API operations by kind:
+	// "list", "search" and "write", e.g. "search:100,write:50" (default: YouTube's
+	// published costs). Used for usage tracking and the cost estimates in tools.
+	QuotaCosts map[string]int `env:"QUOTA_COSTS"`
+
+	// MaxTasteVideos caps how many of the most recent liked videos the taste
+	// analysis and recommendation tools fetch, trading completeness for speed,
+	// quota and output size on large libraries (default: 0, all). Tools can
+	// override it per call with maxLiked.
+	MaxTasteVideos int `env:"MAX_TASTE_VIDEOS" envDefault:"0"`
+
+	// MaxQuotaPerCall caps the estimated quota a single tool call that creates
+	// a playlist or adds, moves, rates or removes songs may spend; calls
+	// estimated to cost more are refused before any write (default: 0, no cap).
+	MaxQuotaPerCall int `env:"MAX_QUOTA_PER_CALL" envDefault:"0"`
+
+	// ExplainMode makes every tool append a short trailer describing the API
+	// operations it performed, the quota spent, and any fallbacks taken (default: false).
+	ExplainMode bool `env:"EXPLAIN_MODE" envDefault:"false"`
+
+	// ReadOnly requests only the youtube.readonly OAuth scope and does not
+	// register tools that modify the library (default: false). Switching from
+	// read-only to full access requires re-authenticating to obtain a new token.
+	ReadOnly bool `env:"READ_ONLY" envDefault:"false"`
+
+	// AppName names this deployment in the User-Agent of YouTube API requests,
+	// as "<AppName>/<version>", so its traffic can be told apart in Google
+	// Cloud dashboards (default: youtube-music-mcp).
+	AppName string `env:"APP_NAME" envDefault:"youtube-music-mcp"`
+
+	// LogLevel is the minimum log level: "debug", "info" (default), "warn" or "error".
+	LogLevel slog.Level `env:"LOG_LEVEL" envDefault:"info"`
+
+	// LogFormat selects the log output format: "json" (default) or "text".
+	// Use "text" for human-readable logs when running locally.
+	LogFormat string `env:"LOG_FORMAT" envDefault:"json"`
+
+	// SearchRegion is the default ISO 3166-1 alpha-2 region code of searches,
+	// e.g. "DE". When unset YouTube infers it from the authenticated account.
+	SearchRegion string `env:"SEARCH_REGION"`
+
+	// SearchLanguage is the default ISO 639-1 relevance language of searches,
+	// e.g. "de". When unset YouTube infers it from the authenticated account.
+	SearchLanguage string `env:"SEARCH_LANGUAGE"`
+
+	// PlaylistPrefix is prepended to the titles of playlists this server creates
+	// and identifies them when looking for previous recommendations (default: [YM-MCP]).
+	// Use distinct prefixes for multiple servers sharing one account.
+	PlaylistPrefix string `env:"PLAYLIST_PREFIX" envDefault:"[YM-MCP]"`
+
+	// DefaultPlaylistPrivacy is the privacy status of created playlists when a
+	// tool call does not specify one: "private" (default), "unlisted" or "public".
+	DefaultPlaylistPrivacy string `env:"DEFAULT_PLAYLIST_PRIVACY" envDefault:"private"`
+
+	// AuthCheckInterval is how often SSE mode revalidates each connected user's
+	// YouTube auth in the background, costing 1 quota unit per user, and
+	// disconnects users whose tokens stopped working (default: 15m). Zero
+	// disables it.
+	AuthCheckInterval time.Duration `env:"AUTH_CHECK_INTERVAL" envDefault:"15m"`
+
+	// AuthWebhookURL, when set, receives a JSON POST with the channel name and
+	// time each time a user completes authentication in SSE mode, e.g. to start
+	// an initial sync. Delivery is best effort and never fails the auth flow.
+	AuthWebhookURL string `env:"AUTH_WEBHOOK_URL"`
+
+	// ShutdownTimeout bounds how long SSE shutdown waits for in-flight requests
+	// to finish before forcing them closed (default: 10s).
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+
+	// AllowedOrigins lists the origins allowed to call the HTTP endpoints from a
+	// browser, comma-separated; "*" allows any origin. Empty (default) sends no
+	// CORS headers.
+	AllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" envSeparator:","`
+
+	// MetricsToken, when set, is the bearer token required to read the SSE-mode
+	// /metrics endpoint. Empty leaves /metrics open.
+	MetricsToken string `env:"METRICS_TOKEN"`
+
+	// AccessTokenTTL is the lifetime of the access tokens the SSE-mode MCP OAuth
+	// server issues (default: 1h). Shorter is safer; clients refresh more often.
+	AccessTokenTTL time.Duration `env:"MCP_ACCESS_TOKEN_TTL" envDefault:"1h"`
+
+	// AuthCodeTTL is how long an MCP authorization code can be exchanged for
+	// tokens (default: 10m).
+	AuthCodeTTL time.Duration `env:"MCP_AUTH_CODE_TTL" envDefault:"10m"`
+
+	// RefreshTokenTTL is how long an unused MCP refresh token stays valid;
+	// users who haven't connected for this long must authorize again, and
+	// their Google token is dropped (default: 720h, 30 days).
+	RefreshTokenTTL time.Duration `env:"MCP_REFRESH_TOKEN_TTL" envDefault:"720h"`
 }
 
 // Load loads the configuration from environment variables.
@@ -48,6 +205,77 @@ func Load() (*Config, error) {
 	if err := env.Parse(cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
+
+// validate rejects option values that parse but are not supported.
+func (c *Config) validate() error {
+	switch c.LogFormat {
+	case "json", "text":
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q: must be \"json\" or \"text\"", c.LogFormat)
+	}
+	if c.SearchRegion != "" && len(c.SearchRegion) != 2 {
+		return fmt.Errorf("invalid SEARCH_REGION %q: must be a 2-letter ISO 3166-1 code", c.SearchRegion)
+	}
+	if c.SearchLanguage != "" && (len(c.SearchLanguage) < 2 || len(c.SearchLanguage) > 7) {
+		return fmt.Errorf("invalid SEARCH_LANGUAGE %q: must be an ISO 639-1 code such as \"en\"", c.SearchLanguage)
+	}
+	for kind, units := range c.QuotaCosts {
+		switch kind {
+		case "list", "search", "write":
+		default:
+			return fmt.Errorf("invalid QUOTA_COSTS operation %q: must be \"list\", \"search\" or \"write\"", kind)
+		}
+		if units <= 0 {
+			return fmt.Errorf("invalid QUOTA_COSTS %s cost %d: must be positive", kind, units)
+		}
+	}
+	if c.StdioDeferAuth && (c.ManualAuth || c.OAuthPort == 0) {
+		return fmt.Errorf("STDIO_DEFER_AUTH cannot be combined with MANUAL_AUTH or OAUTH_PORT=0: stdin carries MCP messages while serving")
+	}
+	if c.APIRateLimit < 0 {
+		return fmt.Errorf("invalid API_RATE_LIMIT %g: must be 0 (no limit) or positive", c.APIRateLimit)
+	}
+	if c.AuthCheckInterval < 0 {
+		return fmt.Errorf("invalid AUTH_CHECK_INTERVAL %s: must be 0 (disabled) or positive", c.AuthCheckInterval)
+	}
+	if c.AuthWebhookURL != "" {
+		if u, err := url.Parse(c.AuthWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid AUTH_WEBHOOK_URL %q: must be an http or https URL", c.AuthWebhookURL)
+		}
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.MaxTasteVideos < 0 {
+		return fmt.Errorf("invalid MAX_TASTE_VIDEOS %d: must be 0 (all) or positive", c.MaxTasteVideos)
+	}
+	if c.MaxQuotaPerCall < 0 {
+		return fmt.Errorf("invalid MAX_QUOTA_PER_CALL %d: must be 0 (no cap) or positive", c.MaxQuotaPerCall)
+	}
+	switch c.DefaultPlaylistPrivacy {
+	case "private", "unlisted", "public":
+	default:
+		return fmt.Errorf("invalid DEFAULT_PLAYLIST_PRIVACY %q: must be \"private\", \"unlisted\" or \"public\"", c.DefaultPlaylistPrivacy)
+	}
+	switch c.DedupStrategy {
+	case "id", "title":
+	default:
+		return fmt.Errorf("invalid DEDUP_STRATEGY %q: must be \"id\" or \"title\"", c.DedupStrategy)
+	}
+	if c.AccessTokenTTL <= 0 {
+		return fmt.Errorf("invalid MCP_ACCESS_TOKEN_TTL %s: must be positive", c.AccessTokenTTL)
+	}
+	if c.AuthCodeTTL <= 0 {
+		return fmt.Errorf("invalid MCP_AUTH_CODE_TTL %s: must be positive", c.AuthCodeTTL)
+	}
+	if c.RefreshTokenTTL <= 0 {
+		return fmt.Errorf("invalid MCP_REFRESH_TOKEN_TTL %s: must be positive", c.RefreshTokenTTL)
+	}
+	return nil
+}
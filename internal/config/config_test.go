@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDefaultPlaylistPrivacy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: "private"},
+		{value: "unlisted", want: "unlisted"},
+		{value: "public", want: "public"},
+		{value: "shared", wantErr: true},
+		{value: "Unlisted", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("GOOGLE_CLIENT_ID", "client")
+			t.Setenv("GOOGLE_CLIENT_SECRET", "secret")
+			t.Setenv("DEFAULT_PLAYLIST_PRIVACY", tt.value)
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "DEFAULT_PLAYLIST_PRIVACY") {
+					t.Errorf("Load() err = %v, want a DEFAULT_PLAYLIST_PRIVACY error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() err = %v", err)
+			}
+			if cfg.DefaultPlaylistPrivacy != tt.want {
+				t.Errorf("DefaultPlaylistPrivacy = %q, want %q", cfg.DefaultPlaylistPrivacy, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// authCheckTolerance is how many check intervals a user's YouTube auth may
+// keep failing before the user is disconnected, so one transient error doesn't
+// drop them.
+const authCheckTolerance = 3
+
+// authCheckTimeout bounds a single background auth validation.
+const authCheckTimeout = 30 * time.Second
+
+// authCheck records the outcome of a tenant's periodic auth validation. It is
+// safe for concurrent use.
+type authCheck struct {
+	mu          sync.Mutex
+	lastSuccess time.Time
+	lastError   string
+}
+
+// record stores the outcome of a validation that finished at now.
+func (a *authCheck) record(now time.Time, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err != nil {
+		a.lastError = err.Error()
+		return
+	}
+	a.lastSuccess = now
+	a.lastError = ""
+}
+
+// state returns the last successful validation time and the error of the
+// latest validation, empty if it succeeded.
+func (a *authCheck) state() (lastSuccess time.Time, lastError string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lastSuccess, a.lastError
+}
+
+// wedged reports whether validation has been failing for longer than
+// authCheckTolerance intervals, e.g. because the refresh token was revoked
+// while the token still looks valid.
+func (a *authCheck) wedged(now time.Time, interval time.Duration) bool {
+	lastSuccess, lastError := a.state()
+	return lastError != "" && now.Sub(lastSuccess) > authCheckTolerance*interval
+}
+
+// runAuthChecks validates every connected user's YouTube auth each interval
+// until ctx is done. Each check costs 1 quota unit per user.
+func (s *Server) runAuthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.checkAuth(ctx, interval)
+	}
+}
+
+// checkAuth validates the YouTube auth of every connected user once. Users
+// whose auth has been failing for longer than authCheckTolerance intervals are
+// disconnected: their server and grant are dropped, so they re-authorize
+// afresh, and /ready lists them without failing for everybody else.
+func (s *Server) checkAuth(ctx context.Context, interval time.Duration) {
+	s.mu.Lock()
+	tenants := maps.Clone(s.tenants)
+	s.mu.Unlock()
+
+	for userID, t := range tenants {
+		checkCtx, cancel := context.WithTimeout(ctx, authCheckTimeout)
+		_, err := t.ytClient.ValidateAuth(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.logger.Warn("background auth check failed", "channel", t.channelName, "error", err)
+		}
+		now := time.Now()
+		t.authCheck.record(now, err)
+		if t.authCheck.wedged(now, interval) {
+			s.disconnect(userID, t, now)
+		}
+	}
+}
+
+// disconnect drops the wedged server t of user userID and revokes its grant,
+// unless the server has already been replaced, and remembers its last auth
+// check for /ready.
+func (s *Server) disconnect(userID string, t *Server, now time.Time) {
+	check := t.authCheckOutput(now, s.cfg.AuthCheckInterval)
+
+	s.mu.Lock()
+	if s.tenants[userID] != t {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.tenants, userID)
+	s.disconnected[t.channelName] = check
+	s.mu.Unlock()
+
+	// Its MCP tokens stop working too, so the client starts a new authorization
+	s.mcpOAuth.RevokeGrant(userID)
+	s.logger.Warn("disconnected user whose YouTube auth keeps failing; they must re-authenticate", "channel", t.channelName, "error", check.LastError)
+}
+
+// authCheckOutput reports the background auth validation state of a per-user server.
+func (s *Server) authCheckOutput(now time.Time, interval time.Duration) authCheckOutput {
+	lastSuccess, lastError := s.authCheck.state()
+	check := authCheckOutput{
+		Channel:   s.channelName,
+		LastError: lastError,
+		Wedged:    s.authCheck.wedged(now, interval),
+	}
+	if !lastSuccess.IsZero() {
+		check.LastSuccess = lastSuccess.Format(time.RFC3339)
+	}
+	return check
+}
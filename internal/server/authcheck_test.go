@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/auth"
+	"github.com/gxravel/youtube-music-mcp/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// fakeGoogleAPI serves Google's token endpoint, issuing "google-access-<code>"
+// for the authorization code <code>, and channels.list, answering with the
+// channel "<code>" unless that user's access has been revoked.
+type fakeGoogleAPI struct {
+	mu      sync.Mutex
+	revoked map[string]bool
+}
+
+func (g *fakeGoogleAPI) setRevoked(code string, revoked bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.revoked[code] = revoked
+}
+
+func (g *fakeGoogleAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]any{
+			"access_token":  "google-access-" + r.FormValue("code"),
+			"refresh_token": "google-refresh-" + r.FormValue("code"),
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		code := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer google-access-")
+		g.mu.Lock()
+		revoked := g.revoked[code]
+		g.mu.Unlock()
+		if revoked {
+			writeAPIError(w, http.StatusUnauthorized, "authError")
+			return
+		}
+		writeJSON(w, map[string]any{"items": []map[string]any{{"snippet": map[string]any{"title": code}}}})
+	})
+	return mux
+}
+
+// newTestSSEServer returns an SSE-mode server whose MCP OAuth server and
+// per-user YouTube clients call google instead of Google, and the context
+// tenants must be created with so their API calls reach it.
+func newTestSSEServer(t *testing.T, cfg *config.Config, google *fakeGoogleAPI) (*Server, context.Context) {
+	t.Helper()
+	srv := httptest.NewServer(google.handler())
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	googleCfg := &oauth2.Config{
+		ClientID:     "google-client",
+		ClientSecret: "google-secret",
+		RedirectURL:  "https://mcp.example/google-callback",
+		Endpoint:     oauth2.Endpoint{AuthURL: srv.URL + "/auth", TokenURL: srv.URL + "/token"},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mcpOAuth := auth.NewMCPOAuthServer("https://mcp.example", googleCfg, logger, auth.MCPOAuthOptions{})
+
+	httpClient := &http.Client{Transport: redirectTransport{target: target}}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	return NewServer(logger, nil, nil, cfg, mcpOAuth), ctx
+}
+
+// grantGoogleAccess runs the MCP OAuth flow up to Google's callback for a
+// user who authorizes Google with googleCode, and returns their grant ID.
+func grantGoogleAccess(t *testing.T, mcpOAuth *auth.MCPOAuthServer, googleCode string) string {
+	t.Helper()
+	const redirectURI = "http://client.example/cb"
+
+	grants := make(chan string, 1)
+	mcpOAuth.OnGrant(func(grantID string) { grants <- grantID })
+
+	rec := httptest.NewRecorder()
+	mcpOAuth.RegisterHandler()(rec, httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"redirect_uris":["`+redirectURI+`"]}`)))
+	var client struct {
+		ClientID string `json:"client_id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&client); err != nil {
+		t.Fatalf("register: %v (status %d)", err, rec.Code)
+	}
+
+	sum := sha256.Sum256([]byte("verifier"))
+	q := url.Values{
+		"client_id":             {client.ClientID},
+		"redirect_uri":          {redirectURI},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+	rec = httptest.NewRecorder()
+	mcpOAuth.AuthorizeHandler()(rec, httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+	googleURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || rec.Code != http.StatusFound {
+		t.Fatalf("authorize: status %d, location %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	q = url.Values{"code": {googleCode}, "state": {googleURL.Query().Get("state")}}
+	rec = httptest.NewRecorder()
+	mcpOAuth.GoogleCallbackHandler()(rec, httptest.NewRequest(http.MethodGet, "/google-callback?"+q.Encode(), nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("callback: status %d, body %q", rec.Code, rec.Body.String())
+	}
+	return <-grants
+}
+
+func TestCheckAuthDisconnectsUserWhoseAuthStartsFailing(t *testing.T) {
+	google := &fakeGoogleAPI{revoked: make(map[string]bool)}
+	cfg := testConfig(t, map[string]string{"AUTH_CHECK_INTERVAL": "1m"})
+	root, ctx := newTestSSEServer(t, cfg, google)
+
+	var users []string
+	for _, code := range []string{"alice", "bob"} {
+		userID := grantGoogleAccess(t, root.mcpOAuth, code)
+		if _, err := root.tenant(ctx, userID); err != nil {
+			t.Fatalf("tenant %s: %v", code, err)
+		}
+		users = append(users, userID)
+	}
+
+	// Alice revokes access; one failed check is tolerated
+	google.setRevoked("alice", true)
+	root.checkAuth(ctx, cfg.AuthCheckInterval)
+	if got := len(root.tenantList()); got != 2 {
+		t.Fatalf("tenants after one failed check = %d, want 2", got)
+	}
+
+	// Once it has been failing for longer than the tolerance she is dropped
+	root.mu.Lock()
+	alice := root.tenants[users[0]]
+	root.mu.Unlock()
+	alice.authCheck.mu.Lock()
+	alice.authCheck.lastSuccess = time.Now().Add(-(authCheckTolerance + 1) * cfg.AuthCheckInterval)
+	alice.authCheck.mu.Unlock()
+	root.checkAuth(ctx, cfg.AuthCheckInterval)
+
+	out := root.readiness()
+	if !out.Ready {
+		t.Errorf("readiness = not ready (%s), want ready for the healthy user", out.Reason)
+	}
+	if len(out.Channels) != 1 || out.Channels[0] != "bob" {
+		t.Errorf("channels = %v, want [bob]", out.Channels)
+	}
+	if len(out.Disconnected) != 1 || out.Disconnected[0].Channel != "alice" || !out.Disconnected[0].Wedged || out.Disconnected[0].LastError == "" {
+		t.Errorf("disconnected = %+v, want alice with her last error", out.Disconnected)
+	}
+
+	// Her grant was revoked, so no server is recreated for it
+	if _, err := root.tenant(ctx, users[0]); err == nil || !strings.Contains(err.Error(), "no Google token") {
+		t.Errorf("tenant of the disconnected user: err = %v, want no Google token", err)
+	}
+
+	// Re-authorizing creates a new grant and clears the report
+	google.setRevoked("alice", false)
+	userID := grantGoogleAccess(t, root.mcpOAuth, "alice")
+	if _, err := root.tenant(ctx, userID); err != nil {
+		t.Fatalf("tenant after re-authorizing: %v", err)
+	}
+	if out := root.readiness(); len(out.Disconnected) != 0 {
+		t.Errorf("disconnected after re-authorizing = %+v, want none", out.Disconnected)
+	}
+}
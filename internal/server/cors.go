@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+)
+
+// CORS headers for browser-based MCP clients. Mcp-Session-Id and
+// Mcp-Protocol-Version are used by the streamable HTTP transport.
+const (
+	corsAllowMethods  = "GET, POST, DELETE, OPTIONS"
+	corsAllowHeaders  = "Authorization, Content-Type, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID"
+	corsExposeHeaders = "Mcp-Session-Id, WWW-Authenticate"
+)
+
+// corsMiddleware adds CORS headers for requests from allowedOrigins and answers
+// preflight OPTIONS requests. "*" allows any origin. With no allowed origins
+// the handler is returned unchanged and no CORS headers are sent.
+func corsMiddleware(allowedOrigins []string, next http.Handler) http.Handler {
+	if len(allowedOrigins) == 0 {
+		return next
+	}
+	allowAll := slices.Contains(allowedOrigins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || (!allowAll && !slices.Contains(allowedOrigins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+
+		// Preflight: answer directly so method-specific routes don't reject it
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
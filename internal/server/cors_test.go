@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		method      string
+		origin      string
+		wantOrigin  string
+		wantStatus  int
+		wantMethods bool
+	}{
+		{name: "allowed origin", allowed: []string{"https://app.example"}, method: http.MethodPost, origin: "https://app.example", wantOrigin: "https://app.example", wantStatus: http.StatusTeapot},
+		{name: "disallowed origin", allowed: []string{"https://app.example"}, method: http.MethodPost, origin: "https://evil.example", wantStatus: http.StatusTeapot},
+		{name: "no origin", allowed: []string{"https://app.example"}, method: http.MethodPost, wantStatus: http.StatusTeapot},
+		{name: "wildcard", allowed: []string{"*"}, method: http.MethodPost, origin: "https://any.example", wantOrigin: "https://any.example", wantStatus: http.StatusTeapot},
+		{name: "preflight from allowed origin", allowed: []string{"https://app.example"}, method: http.MethodOptions, origin: "https://app.example", wantOrigin: "https://app.example", wantStatus: http.StatusNoContent, wantMethods: true},
+		{name: "preflight from disallowed origin", allowed: []string{"https://app.example"}, method: http.MethodOptions, origin: "https://evil.example", wantStatus: http.StatusTeapot},
+		{name: "no allowed origins", method: http.MethodPost, origin: "https://app.example", wantStatus: http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/mcp", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			corsMiddleware(tt.allowed, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			wantExpose := ""
+			if tt.wantOrigin != "" {
+				wantExpose = corsExposeHeaders
+			}
+			if got := rec.Header().Get("Access-Control-Expose-Headers"); got != wantExpose {
+				t.Errorf("Access-Control-Expose-Headers = %q, want %q", got, wantExpose)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Methods") != ""; got != tt.wantMethods {
+				t.Errorf("Access-Control-Allow-Methods set = %v, want %v", got, tt.wantMethods)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Deduplication strategies for recommend-playlist results.
+const (
+	// dedupByID collapses only results with the same video ID.
+	dedupByID = "id"
+	// dedupByTitle additionally collapses different uploads of the same song
+	// (same normalized title and channel).
+	dedupByTitle = "title"
+)
+
+// titleNoiseRe matches bracketed decorations that differ between uploads of the same song,
+// e.g. "(Official Video)", "[Official Music Video]", "(Lyrics)", "(HD)".
+var titleNoiseRe = regexp.MustCompile(`(?i)[(\[][^)\]]*\b(official|video|audio|lyrics?|lyric video|visualizer|hd|hq|4k|remaster(ed)?|mv|m/v)\b[^)\]]*[)\]]`)
+
+// featuringRe matches a featured-artist credit, bracketed ("(feat. X)") or
+// trailing ("Song ft. X"), which uploads of the same song credit inconsistently.
+var featuringRe = regexp.MustCompile(`(?i)[(\[]\s*(feat|ft|featuring)\b[^)\]]*[)\]]|\s(feat|ft|featuring)\b.*$`)
+
+// channelNoiseRe matches auto-generated channel suffixes such as "Artist - Topic" or "ArtistVEVO".
+var channelNoiseRe = regexp.MustCompile(`(?i)(\s*-\s*topic|vevo|\s+official)$`)
+
+// nonWordRe matches runs of characters that are not letters or digits.
+var nonWordRe = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// normalizeTitle lowercases a video title and strips common upload decorations
+// and featured-artist credits.
+func normalizeTitle(title string) string {
+	title = titleNoiseRe.ReplaceAllString(title, " ")
+	title = featuringRe.ReplaceAllString(title, " ")
+	title = nonWordRe.ReplaceAllString(strings.ToLower(title), " ")
+	return strings.TrimSpace(title)
+}
+
+// normalizeChannel lowercases a channel name and strips auto-generated suffixes.
+func normalizeChannel(channel string) string {
+	channel = channelNoiseRe.ReplaceAllString(strings.TrimSpace(channel), "")
+	channel = nonWordRe.ReplaceAllString(strings.ToLower(channel), " ")
+	return strings.TrimSpace(channel)
+}
+
+// trackKey returns the key used to detect near-duplicate tracks.
+func trackKey(title, channel string) string {
+	return normalizeTitle(title) + "|" + normalizeChannel(channel)
+}
+
+// validateDedupStrategy returns the strategy to use, falling back to fallback when empty.
+func validateDedupStrategy(strategy, fallback string) (string, error) {
+	if strategy == "" {
+		strategy = fallback
+	}
+	switch strategy {
+	case dedupByID, dedupByTitle:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid dedupStrategy %q: must be one of '%s' or '%s'", strategy, dedupByID, dedupByTitle)
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/gxravel/youtube-music-mcp/internal/auth"
+	"github.com/gxravel/youtube-music-mcp/internal/config"
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// deferredAuth is the progress of the OAuth flow of a stdio server that
+// serves before it is authenticated.
+type deferredAuth struct {
+	authURL string // URL the user must visit; empty until the web flow starts
+	err     error  // why the flow failed, if it did
+}
+
+// authRequiredOutput is the structured result of a tool called before OAuth completes.
+type authRequiredOutput struct {
+	AuthRequired bool   `json:"authRequired"`
+	AuthURL      string `json:"authUrl,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// NewDeferredServer creates a stdio server that serves MCP before the user is
+// authenticated. Its tools are listed right away but return an "authentication
+// required" result until CompleteAuth supplies the YouTube client.
+func NewDeferredServer(logger *slog.Logger, cfg *config.Config) *Server {
+	s := NewServer(logger, nil, nil, cfg, nil)
+	s.deferredAuth = &deferredAuth{}
+	s.registerTools()
+	return s
+}
+
+// SetAuthURL records the URL the user must visit to authorize, to be returned
+// by tools until authentication completes.
+func (s *Server) SetAuthURL(authURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deferredAuth != nil {
+		s.deferredAuth.authURL = authURL
+	}
+}
+
+// FailAuth records that the deferred OAuth flow failed, so tools report why.
+func (s *Server) FailAuth(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deferredAuth != nil {
+		s.deferredAuth.err = err
+	}
+}
+
+// CompleteAuth makes the tools of a deferred server use ytClient from now on.
+func (s *Server) CompleteAuth(ytClient *youtube.Client, tokenStatus auth.TokenStatusReporter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ytClient = ytClient
+	s.tokenStatus = tokenStatus
+	s.deferredAuth = nil
+}
+
+// deferredAuthMiddleware answers tool calls made before a deferred server is
+// authenticated with the authorization URL instead of running the tool.
+func (s *Server) deferredAuthMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+
+		s.mu.Lock()
+		pending := s.deferredAuth
+		var out authRequiredOutput
+		if pending != nil {
+			out = authRequiredOutput{AuthRequired: true, AuthURL: pending.authURL}
+			if pending.err != nil {
+				out.Error = pending.err.Error()
+			}
+		}
+		s.mu.Unlock()
+
+		if pending == nil {
+			return next(ctx, method, req)
+		}
+
+		var text string
+		switch {
+		case out.Error != "":
+			text = fmt.Sprintf("YouTube authentication failed: %s. Restart the server to try again.", out.Error)
+		case out.AuthURL != "":
+			text = fmt.Sprintf("Authentication required: visit this URL to authorize YouTube access, then retry:\n%s", out.AuthURL)
+		default:
+			text = "Authentication in progress: connecting to YouTube, retry in a moment."
+		}
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{&mcp.TextContent{Text: text}},
+			StructuredContent: out,
+			IsError:           true,
+		}, nil
+	}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// authRequired decodes the authentication-required output of a tool called
+// on a deferred server before it is authenticated.
+func authRequired(t *testing.T, s *Server) (authRequiredOutput, string) {
+	t.Helper()
+	result := callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome"})
+	if !result.IsError {
+		t.Fatalf("tool ran before authentication: %s", resultText(result))
+	}
+	var out authRequiredOutput
+	data, _ := json.Marshal(result.StructuredContent)
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	return out, resultText(result)
+}
+
+func TestDeferredServerAnswersUntilAuthenticated(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("aaaaaaaaaaa"))
+	})
+	cfg := testConfig(t, nil)
+	s := NewDeferredServer(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+
+	// Before the web flow starts there is no URL yet
+	out, text := authRequired(t, s)
+	if !out.AuthRequired || out.AuthURL != "" || !strings.Contains(text, "in progress") {
+		t.Errorf("before the URL: %+v, %q; want authentication in progress", out, text)
+	}
+
+	const authURL = "https://accounts.google.com/o/oauth2/auth?state=xyz"
+	s.SetAuthURL(authURL)
+	out, text = authRequired(t, s)
+	if !out.AuthRequired || out.AuthURL != authURL || !strings.Contains(text, authURL) {
+		t.Errorf("with the URL: %+v, %q; want the URL to visit", out, text)
+	}
+
+	// Once authenticated the tools run
+	s.CompleteAuth(newTestYouTubeClient(t, cfg, api), nil)
+	var items getPlaylistItemsOutput
+	decodeOutput(t, callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome"}), &items)
+	if len(items.Items) != 1 || items.Items[0].ID != "aaaaaaaaaaa" {
+		t.Errorf("items after authentication = %+v, want aaaaaaaaaaa", items.Items)
+	}
+}
+
+func TestDeferredServerReportsFailedAuth(t *testing.T) {
+	s := NewDeferredServer(slog.New(slog.NewTextHandler(io.Discard, nil)), testConfig(t, nil))
+	s.SetAuthURL("https://accounts.google.com/o/oauth2/auth")
+	s.FailAuth(errors.New("access denied"))
+
+	out, text := authRequired(t, s)
+	if out.Error != "access denied" || !strings.Contains(text, "authentication failed: access denied") {
+		t.Errorf("after a failed flow: %+v, %q; want the failure reported", out, text)
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolErrorOutput is the structured content of a failed tool call whose error
+// class is known, so the client can decide whether retrying makes sense.
+type toolErrorOutput struct {
+	ErrorCode string `json:"errorCode"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// toolErrorHints tells the client what to do about each known error class.
+var toolErrorHints = map[string]string{
+	errorClassQuota:      "The YouTube API quota is used up; retrying will fail until it resets at midnight Pacific Time.",
+	errorClassAuth:       "The account is not authorized for this; re-authenticate or use a resource the account can access.",
+	errorClassTransient:  "Temporary failure; retrying shortly should work.",
+	errorClassValidation: "The request was invalid or referenced something that doesn't exist; fix the arguments instead of retrying.",
+}
+
+// toolErrorMiddleware attaches a structured error code, message, and retryable
+// flag to failed tool calls whose error is quota, auth, transient, or
+// validation. Unexpected errors keep the SDK's plain error result.
+func (s *Server) toolErrorMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		if method != "tools/call" || err != nil {
+			return result, err
+		}
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok || toolResult == nil || !toolResult.IsError || toolResult.GetError() == nil {
+			return result, err
+		}
+
+		class := classifyToolError(toolResult.GetError())
+		hint, known := toolErrorHints[class]
+		if !known {
+			return result, err
+		}
+		toolResult.StructuredContent = toolErrorOutput{
+			ErrorCode: class,
+			Message:   toolResult.GetError().Error(),
+			Retryable: class == errorClassTransient,
+			Hint:      hint,
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestToolErrorMiddlewareMarksRetryable(t *testing.T) {
+	tests := []struct {
+		reason        string
+		wantCode      string
+		wantRetryable bool
+	}{
+		{"quotaExceeded", errorClassQuota, false},
+		{"rateLimitExceeded", errorClassTransient, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			api := http.NewServeMux()
+			api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+				writeAPIError(w, http.StatusForbidden, tt.reason)
+			})
+			s := newTestServer(t, testConfig(t, nil), api)
+
+			result := callTool(t, s, "ym:find-duplicates-in-playlist", map[string]any{"playlistId": "PLsome"})
+			if !result.IsError {
+				t.Fatalf("result %q, want an error", resultText(result))
+			}
+			data, err := json.Marshal(result.StructuredContent)
+			if err != nil {
+				t.Fatalf("encode output: %v", err)
+			}
+			var out toolErrorOutput
+			if err := json.Unmarshal(data, &out); err != nil {
+				t.Fatalf("decode output: %v", err)
+			}
+			if out.ErrorCode != tt.wantCode || out.Retryable != tt.wantRetryable || out.Hint != toolErrorHints[tt.wantCode] {
+				t.Errorf("output = %+v, want code %q, retryable %v and its hint", out, tt.wantCode, tt.wantRetryable)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// explainMiddleware records the YouTube API operations of each tool call and,
+// when explain mode is enabled, appends a "what I did and what it cost" trailer
+// to the tool's output.
+func (s *Server) explainMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+
+		// Always record, so tools can report the quota they actually spent
+		log := &youtube.OperationLog{}
+		result, err := next(youtube.WithOperationLog(ctx, log), method, req)
+		if err != nil || !s.cfg.ExplainMode {
+			return result, err
+		}
+
+		if toolResult, ok := result.(*mcp.CallToolResult); ok && toolResult != nil {
+			toolResult.Content = append(toolResult.Content, &mcp.TextContent{Text: explainTrailer(log)})
+		}
+		return result, err
+	}
+}
+
+// explainTrailer summarizes the operations, quota cost, and fallbacks in log.
+func explainTrailer(log *youtube.OperationLog) string {
+	type opSummary struct {
+		name  string
+		count int
+		cost  int
+	}
+	var summaries []*opSummary
+	byName := make(map[string]*opSummary)
+	total := 0
+	for _, op := range log.Operations() {
+		summary, ok := byName[op.Name]
+		if !ok {
+			summary = &opSummary{name: op.Name}
+			byName[op.Name] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.count++
+		summary.cost += op.Cost
+		total += op.Cost
+	}
+
+	var out strings.Builder
+	out.WriteString("\n---\n**What I did:**\n")
+	if len(summaries) == 0 {
+		out.WriteString("- No YouTube API calls\n")
+	}
+	for _, summary := range summaries {
+		fmt.Fprintf(&out, "- %s x%d (%d units)\n", summary.name, summary.count, summary.cost)
+	}
+	fmt.Fprintf(&out, "\n**Quota spent:** ~%d units\n", total)
+
+	if fallbacks := log.Fallbacks(); len(fallbacks) > 0 {
+		out.WriteString("\n**Fallbacks taken:**\n")
+		for _, fallback := range fallbacks {
+			fmt.Fprintf(&out, "- %s\n", fallback)
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// readinessOutput is the JSON body of the /ready endpoint.
+type readinessOutput struct {
+	Ready          bool     `json:"ready"`
+	Reason         string   `json:"reason,omitempty"`
+	Authenticated  bool     `json:"authenticated"`
+	Channels       []string `json:"channels,omitempty"`
+	QuotaUsed      int      `json:"quotaUsed"`
+	QuotaLimit     int      `json:"quotaLimit"`
+	QuotaRemaining int      `json:"quotaRemaining"`
+	// AuthChecks are the background auth validations of each connected user
+	AuthChecks []authCheckOutput `json:"authChecks,omitempty"`
+	// Disconnected are users dropped because their YouTube auth kept failing
+	// (e.g. a revoked refresh token); they must re-authenticate
+	Disconnected []authCheckOutput `json:"disconnected,omitempty"`
+}
+
+// authCheckOutput is one user's background auth validation state.
+type authCheckOutput struct {
+	Channel     string `json:"channel"`
+	LastSuccess string `json:"lastSuccess,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+	Wedged      bool   `json:"wedged,omitempty"`
+}
+
+// readyHandler returns a handler for GET /ready. Unlike the /health liveness
+// check it responds 503 when tools could not work: no Google token yet or the
+// daily quota is exhausted. Users disconnected because their YouTube auth kept
+// failing are listed without failing readiness for everybody else.
+func (s *Server) readyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out := s.readiness()
+
+		status := http.StatusOK
+		if !out.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(out)
+	}
+}
+
+// readiness reports whether tool calls can currently succeed. Quota usage is
+// that of all users together, since they share the Google Cloud project's
+// daily quota.
+func (s *Server) readiness() readinessOutput {
+	if !s.mcpOAuth.HasGoogleToken() {
+		return readinessOutput{Reason: "not authenticated with Google"}
+	}
+
+	out := readinessOutput{Authenticated: true}
+	out.QuotaUsed, out.QuotaLimit = s.projectQuota.Usage()
+	now := time.Now()
+	for _, t := range s.tenantList() {
+		out.Channels = append(out.Channels, t.channelName)
+		if s.cfg.AuthCheckInterval > 0 {
+			out.AuthChecks = append(out.AuthChecks, t.authCheckOutput(now, s.cfg.AuthCheckInterval))
+		}
+	}
+	s.mu.Lock()
+	out.Disconnected = slices.Collect(maps.Values(s.disconnected))
+	s.mu.Unlock()
+	slices.Sort(out.Channels)
+	byChannel := func(a, b authCheckOutput) int { return cmp.Compare(a.Channel, b.Channel) }
+	slices.SortFunc(out.AuthChecks, byChannel)
+	slices.SortFunc(out.Disconnected, byChannel)
+
+	out.QuotaRemaining = max(out.QuotaLimit-out.QuotaUsed, 0)
+	if out.QuotaRemaining == 0 {
+		out.Reason = "daily quota exhausted"
+		return out
+	}
+
+	out.Ready = true
+	return out
+}
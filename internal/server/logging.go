@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+// maxLoggedArguments caps the tool input logged per call, so large imports
+// don't flood the log.
+const maxLoggedArguments = 200
+
+// Error classes of failed tool calls, as logged and reported to clients.
+const (
+	errorClassQuota      = "quota"
+	errorClassAuth       = "auth"
+	errorClassTransient  = "transient"
+	errorClassValidation = "validation"
+	errorClassOther      = "other"
+)
+
+// toolLogMiddleware logs every tool call with its name, a summary of its input,
+// duration, measured quota, and outcome. Failed calls also log the error and
+// its class. It only logs; results pass through unchanged.
+func (s *Server) toolLogMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		callReq, ok := req.(*mcp.CallToolRequest)
+		if method != "tools/call" || !ok || callReq.Params == nil {
+			return next(ctx, method, req)
+		}
+
+		start := time.Now()
+		result, err := next(ctx, method, req)
+
+		attrs := []any{
+			"tool", callReq.Params.Name,
+			"input", summarizeArguments(callReq.Params.Arguments),
+			"duration", time.Since(start),
+			"quota", quotaSpent(ctx),
+		}
+		failure := err
+		if failure == nil {
+			if toolResult, ok := result.(*mcp.CallToolResult); ok && toolResult != nil && toolResult.IsError {
+				failure = toolResult.GetError()
+				if failure == nil {
+					failure = errors.New("tool returned an error result")
+				}
+			}
+		}
+		if failure != nil {
+			s.logger.Warn("tool call failed", append(attrs, "error_class", classifyToolError(failure), "error", failure)...)
+			return result, err
+		}
+
+		s.logger.Info("tool call", attrs...)
+		return result, err
+	}
+}
+
+// summarizeArguments returns the raw JSON arguments of a tool call, truncated
+// to maxLoggedArguments bytes.
+func summarizeArguments(arguments json.RawMessage) string {
+	if len(arguments) == 0 {
+		return "{}"
+	}
+	if len(arguments) > maxLoggedArguments {
+		return string(arguments[:maxLoggedArguments]) + "…"
+	}
+	return string(arguments)
+}
+
+// classifyToolError sorts a tool error into quota, auth, transient, validation,
+// or other, from the underlying YouTube API, OAuth, or protocol error.
+func classifyToolError(err error) string {
+	var wireErr *jsonrpc.Error
+	if errors.As(err, &wireErr) && wireErr.Code == jsonrpc.CodeInvalidParams {
+		return errorClassValidation
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return errorClassAuth
+	}
+
+	switch {
+	case errors.Is(err, youtube.ErrPlaylistNotAccessible):
+		return errorClassAuth
+	case errors.Is(err, youtube.ErrPlaylistNotFound):
+		return errorClassValidation
+	}
+
+	if errors.Is(err, youtube.ErrAPITimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return errorClassTransient
+	}
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return errorClassOther
+	}
+	// Rate limits come as 403s but clear within seconds, unlike the daily quota
+	for _, item := range apiErr.Errors {
+		switch item.Reason {
+		case "quotaExceeded", "dailyLimitExceeded":
+			return errorClassQuota
+		case "rateLimitExceeded", "userRateLimitExceeded":
+			return errorClassTransient
+		}
+	}
+	switch {
+	case apiErr.Code == http.StatusUnauthorized, apiErr.Code == http.StatusForbidden:
+		return errorClassAuth
+	case apiErr.Code == http.StatusTooManyRequests, apiErr.Code >= http.StatusInternalServerError:
+		return errorClassTransient
+	case apiErr.Code == http.StatusBadRequest, apiErr.Code == http.StatusNotFound:
+		return errorClassValidation
+	}
+	return errorClassOther
+}
+
+// stopsWrites reports whether err, a quota or rate-limit error, would fail
+// every later write of a batch too, so the batch should stop rather than
+// record a failure per item.
+func stopsWrites(err error) bool {
+	if classifyToolError(err) == errorClassQuota {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return slices.ContainsFunc(apiErr.Errors, func(item googleapi.ErrorItem) bool {
+		return item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded"
+	})
+}
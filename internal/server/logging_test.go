@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestClassifyToolErrorRateLimitsAreTransient(t *testing.T) {
+	tests := []struct {
+		reason    string
+		wantClass string
+		wantStop  bool
+	}{
+		{"quotaExceeded", errorClassQuota, true},
+		{"dailyLimitExceeded", errorClassQuota, true},
+		{"rateLimitExceeded", errorClassTransient, true},
+		{"userRateLimitExceeded", errorClassTransient, true},
+		{"forbidden", errorClassAuth, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			err := fmt.Errorf("failed to rate video: %w", &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: tt.reason}},
+			})
+			if class := classifyToolError(err); class != tt.wantClass {
+				t.Errorf("class = %q, want %q", class, tt.wantClass)
+			}
+			if stop := stopsWrites(err); stop != tt.wantStop {
+				t.Errorf("stopsWrites = %v, want %v", stop, tt.wantStop)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolStats are the counters of a single tool.
+type toolStats struct {
+	calls     int
+	errors    int
+	quota     int
+	durations time.Duration
+}
+
+// metrics counts tool calls for the /metrics endpoint. One instance is shared
+// by the per-user servers of SSE mode. It is safe for concurrent use.
+type metrics struct {
+	mu    sync.Mutex
+	tools map[string]*toolStats
+}
+
+// newMetrics creates an empty metrics registry.
+func newMetrics() *metrics {
+	return &metrics{tools: make(map[string]*toolStats)}
+}
+
+// recordToolCall adds one call of tool to the counters.
+func (m *metrics) recordToolCall(tool string, failed bool, quota int, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.tools[tool]
+	if !ok {
+		stats = &toolStats{}
+		m.tools[tool] = stats
+	}
+	stats.calls++
+	if failed {
+		stats.errors++
+	}
+	stats.quota += quota
+	stats.durations += elapsed
+}
+
+// metricsMiddleware counts every tool call, whether it failed, the quota it
+// measurably spent and how long it took. It must run inside explainMiddleware,
+// which attaches the operation log the quota is read from.
+func (s *Server) metricsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		callReq, ok := req.(*mcp.CallToolRequest)
+		if method != "tools/call" || !ok || callReq.Params == nil {
+			return next(ctx, method, req)
+		}
+
+		start := time.Now()
+		result, err := next(ctx, method, req)
+		toolResult, _ := result.(*mcp.CallToolResult)
+		failed := err != nil || (toolResult != nil && toolResult.IsError)
+		s.metrics.recordToolCall(callReq.Params.Name, failed, quotaSpent(ctx), time.Since(start))
+		return result, err
+	}
+}
+
+// metricsHandler returns a handler for GET /metrics serving the tool counters
+// and authentication state in the Prometheus text exposition format. When
+// token is set, requests must carry it as a bearer token.
+func (s *Server) metricsHandler(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		s.writeMetrics(w)
+	}
+}
+
+// writeMetrics writes all metrics in the Prometheus text exposition format.
+func (s *Server) writeMetrics(w io.Writer) {
+	s.metrics.mu.Lock()
+	names := slices.Sorted(maps.Keys(s.metrics.tools))
+	stats := make([]toolStats, len(names))
+	for i, name := range names {
+		stats[i] = *s.metrics.tools[name]
+	}
+	s.metrics.mu.Unlock()
+
+	series := []struct {
+		name, help, kind string
+		value            func(toolStats) string
+	}{
+		{"ytmcp_tool_calls_total", "Tool calls by tool name.", "counter", func(t toolStats) string { return fmt.Sprint(t.calls) }},
+		{"ytmcp_tool_errors_total", "Tool calls that failed, by tool name.", "counter", func(t toolStats) string { return fmt.Sprint(t.errors) }},
+		{"ytmcp_tool_quota_units_total", "YouTube API quota units spent by tool calls, by tool name.", "counter", func(t toolStats) string { return fmt.Sprint(t.quota) }},
+		{"ytmcp_tool_duration_seconds_total", "Total time spent in tool calls, by tool name.", "counter", func(t toolStats) string { return fmt.Sprint(t.durations.Seconds()) }},
+	}
+	for _, m := range series {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for i, name := range names {
+			fmt.Fprintf(w, "%s{tool=%q} %s\n", m.name, name, m.value(stats[i]))
+		}
+	}
+
+	authenticated := 0
+	if s.mcpOAuth != nil && s.mcpOAuth.HasGoogleToken() {
+		authenticated = 1
+	}
+	fmt.Fprintf(w, "# HELP ytmcp_authenticated Whether any user has authenticated with Google.\n# TYPE ytmcp_authenticated gauge\nytmcp_authenticated %d\n", authenticated)
+	fmt.Fprintf(w, "# HELP ytmcp_connected_users Users with a connected YouTube client.\n# TYPE ytmcp_connected_users gauge\nytmcp_connected_users %d\n", len(s.tenantList()))
+}
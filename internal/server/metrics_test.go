@@ -0,0 +1,66 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testMetricsToken is the METRICS_TOKEN the tests serve /metrics behind.
+const testMetricsToken = "secret"
+
+// scrapeMetrics fetches /metrics from s and returns the body.
+func scrapeMetrics(t *testing.T, s *Server) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer "+testMetricsToken)
+	rec := httptest.NewRecorder()
+	s.metricsHandler(testMetricsToken)(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape: status %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetricsCountToolCalls(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("playlistId") == "PLmissing" {
+			writeAPIError(w, http.StatusNotFound, "playlistNotFound")
+			return
+		}
+		writeJSON(w, playlistItemsResponse("aaaaaaaaaaa"))
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	// Without the token nothing is served
+	rec := httptest.NewRecorder()
+	s.metricsHandler(testMetricsToken)(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("scrape without token: status %d, want 401", rec.Code)
+	}
+
+	if body := scrapeMetrics(t, s); strings.Contains(body, `tool="ym:get-playlist-items"`) {
+		t.Fatalf("tool counted before it was called:\n%s", body)
+	}
+
+	callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome"})
+	callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome"})
+	callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLmissing"})
+
+	body := scrapeMetrics(t, s)
+	for _, want := range []string{
+		"# TYPE ytmcp_tool_calls_total counter",
+		`ytmcp_tool_calls_total{tool="ym:get-playlist-items"} 3`,
+		`ytmcp_tool_errors_total{tool="ym:get-playlist-items"} 1`,
+		`ytmcp_tool_quota_units_total{tool="ym:get-playlist-items"} 3`,
+		"ytmcp_connected_users 0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
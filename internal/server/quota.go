@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gxravel/youtube-music-mcp/internal/config"
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// quotaIdentityMiddleware accounts YouTube API calls made by a tool call to the
+// calling user (the authenticated MCP client in SSE mode), so each user's quota
+// usage is tracked separately.
+func (s *Server) quotaIdentityMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if identity := quotaIdentity(req); identity != "" {
+			ctx = youtube.WithQuotaIdentity(ctx, identity)
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// quotaSpent returns the quota units spent so far by the tool call in ctx,
+// measured from its operation log rather than estimated.
+func quotaSpent(ctx context.Context) int {
+	if log := youtube.OperationLogFromContext(ctx); log != nil {
+		return log.TotalCost()
+	}
+	return 0
+}
+
+// quotaCosts returns the quota costs set by QUOTA_COSTS, with YouTube's
+// published costs for operations it leaves out.
+func quotaCosts(cfg *config.Config) youtube.QuotaCosts {
+	return youtube.QuotaCosts{
+		List:   cfg.QuotaCosts["list"],
+		Search: cfg.QuotaCosts["search"],
+		Write:  cfg.QuotaCosts["write"],
+	}.WithDefaults()
+}
+
+// quotaIdentity returns the user ID from the request's bearer token, or "" if none.
+func quotaIdentity(req mcp.Request) string {
+	extra := req.GetExtra()
+	if extra == nil || extra.TokenInfo == nil {
+		return ""
+	}
+	return extra.TokenInfo.UserID
+}
+
+// quotaWarningMiddleware appends a quota warning to tool results once today's
+// estimated usage crosses the configured warning threshold, so the LLM can relay
+// it and avoid further expensive calls.
+func (s *Server) quotaWarningMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		if err != nil || method != "tools/call" || !s.cfg.QuotaWarnings {
+			return result, err
+		}
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok || toolResult == nil {
+			return result, err
+		}
+
+		if warning := s.quotaWarning(ctx); warning != "" {
+			toolResult.Content = append(toolResult.Content, &mcp.TextContent{Text: warning})
+		}
+		return result, err
+	}
+}
+
+// quotaWarning returns the warning text if today's usage of the Google Cloud
+// project, by all users together, is above the threshold, or "" otherwise.
+func (s *Server) quotaWarning(ctx context.Context) string {
+	s.mu.Lock()
+	ytClient := s.ytClient
+	s.mu.Unlock()
+
+	if ytClient == nil || s.cfg.QuotaWarningThreshold <= 0 {
+		return ""
+	}
+
+	used, limit := ytClient.TotalQuotaUsage()
+	if float64(used) < s.cfg.QuotaWarningThreshold*float64(limit) {
+		return ""
+	}
+
+	return fmt.Sprintf("\n---\n⚠️ **Quota warning:** ~%s/%s YouTube API quota units have been used today. Avoid further searches and playlist writes until the quota resets at midnight Pacific Time.\n",
+		formatThousands(used), formatThousands(limit))
+}
+
+// formatThousands formats n with comma thousands separators (e.g. 8200 -> "8,200").
+func formatThousands(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var out []byte
+	for i := range len(digits) {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, digits[i])
+	}
+	return sign + string(out)
+}
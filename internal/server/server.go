@@ -1,13 +1,22 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/gxravel/youtube-music-mcp/internal/auth"
+	"github.com/gxravel/youtube-music-mcp/internal/config"
 	"github.com/gxravel/youtube-music-mcp/internal/youtube"
 	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -17,81 +26,176 @@ import (
 type Server struct {
 	mcpServer *mcp.Server
 	logger    *slog.Logger
-	transport string
-	port      int
+	cfg       *config.Config
 
 	// MCP OAuth server (SSE mode only)
 	mcpOAuth *auth.MCPOAuthServer
 
-	mu         sync.Mutex
-	ytClient   *youtube.Client
-	toolsReady bool // true once tools are registered
+	// tokenStatus reports on the current Google OAuth token
+	tokenStatus auth.TokenStatusReporter
+
+	// metrics counts tool calls; shared with the per-user servers
+	metrics *metrics
+
+	// projectQuota accounts the API calls of every per-user server in SSE
+	// mode, which share the Google Cloud project's daily quota
+	projectQuota *youtube.QuotaTracker
+
+	ytClient    *youtube.Client
+	channelName string // authenticated YouTube channel of a per-user server in SSE mode
+
+	// deferredAuth is set while a stdio server started with STDIO_DEFER_AUTH
+	// waits for OAuth to complete; guarded by mu
+	deferredAuth *deferredAuth
+
+	// authCheck tracks the periodic auth validation of a per-user server in SSE mode
+	authCheck authCheck
+
+	// tenants holds a server per authenticated user in SSE mode, keyed by the
+	// user ID of their bearer token; each has its own YouTube client.
+	mu      sync.Mutex
+	tenants map[string]*Server
+	// disconnected holds the last auth check of users whose server was
+	// dropped because their YouTube auth kept failing, keyed by channel until
+	// they authenticate again; guarded by mu
+	disconnected map[string]authCheckOutput
 }
 
+// Server identity, reported to MCP clients and, as the User-Agent, to YouTube.
+const (
+	serverName    = "youtube-music-mcp"
+	serverVersion = "0.1.0"
+)
+
 // NewServer creates a new MCP server instance.
 //
-// For stdio mode: pass a non-nil ytClient; mcpOAuth may be nil.
-// For SSE mode: pass nil ytClient and a configured mcpOAuth; YouTube client is created lazily.
-func NewServer(logger *slog.Logger, ytClient *youtube.Client, transport string, port int, mcpOAuth *auth.MCPOAuthServer) *Server {
+// For stdio mode: pass a non-nil ytClient and the token source backing it; mcpOAuth may be nil.
+// For SSE mode: pass nil ytClient and tokenStatus and a configured mcpOAuth; a
+// server with its own YouTube client is created lazily for each user.
+// For deferred stdio mode use NewDeferredServer instead.
+func NewServer(logger *slog.Logger, ytClient *youtube.Client, tokenStatus auth.TokenStatusReporter, cfg *config.Config, mcpOAuth *auth.MCPOAuthServer) *Server {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
-		Name:    "youtube-music-mcp",
-		Version: "0.1.0",
+		Name:    serverName,
+		Version: serverVersion,
 	}, nil)
 
 	s := &Server{
 		mcpServer: mcpServer,
 		logger:    logger,
-		transport: transport,
-		port:      port,
+		cfg:       cfg,
 		mcpOAuth:  mcpOAuth,
+
+		tokenStatus:  tokenStatus,
+		metrics:      newMetrics(),
+		projectQuota: youtube.NewQuotaTracker(cfg.DailyQuota),
+		tenants:      make(map[string]*Server),
+		disconnected: make(map[string]authCheckOutput),
 	}
 
+	mcpServer.AddReceivingMiddleware(s.deferredAuthMiddleware, s.quotaIdentityMiddleware, s.quotaWarningMiddleware, s.explainMiddleware, s.toolErrorMiddleware, s.toolLogMiddleware, s.metricsMiddleware)
+
 	if ytClient != nil {
 		s.ytClient = ytClient
-		s.registerAnalyzeTools()
-		s.registerRecommendTools()
-		s.toolsReady = true
+		s.registerTools()
 	}
 
 	return s
 }
 
-// ensureYTClient lazily creates the YouTube client from the MCP OAuth server's Google token.
-func (s *Server) ensureYTClient(ctx context.Context) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// YouTubeOptions builds the YouTube client options from the application config.
+func YouTubeOptions(cfg *config.Config) youtube.Options {
+	return youtube.Options{
+		CacheTTL:       cfg.CacheTTL,
+		DailyQuota:     cfg.DailyQuota,
+		SearchRegion:   cfg.SearchRegion,
+		SearchLanguage: cfg.SearchLanguage,
+		APITimeout:     cfg.APITimeout,
+		DefaultPrivacy: cfg.DefaultPlaylistPrivacy,
+		QuotaCosts:     quotaCosts(cfg),
+		UserAgent:      cmp.Or(cfg.AppName, serverName) + "/" + serverVersion,
+		RateLimit:      cfg.APIRateLimit,
+	}
+}
 
-	if s.toolsReady {
-		return nil
+// tenant returns the server of the SSE user userID, lazily creating it with a
+// YouTube client for the user's Google token from the MCP OAuth server. The
+// client is built and validated without holding s.mu, so one user's first
+// request never stalls other users or the health endpoints; if two requests
+// race, the first server stored wins.
+func (s *Server) tenant(ctx context.Context, userID string) (*Server, error) {
+	s.mu.Lock()
+	t, ok := s.tenants[userID]
+	s.mu.Unlock()
+	if ok {
+		return t, nil
 	}
 
-	httpClient, err := s.mcpOAuth.GetGoogleHTTPClient(ctx)
+	// The client outlives this request, so its token refreshes must not use the request's context
+	clientCtx := context.WithoutCancel(ctx)
+	httpClient, tokenStatus, err := s.mcpOAuth.GetGoogleHTTPClient(clientCtx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get Google HTTP client: %w", err)
+		return nil, fmt.Errorf("failed to get Google HTTP client: %w", err)
 	}
 
-	ytClient, err := youtube.NewClient(ctx, httpClient)
+	opts := YouTubeOptions(s.cfg)
+	opts.ProjectQuota = s.projectQuota
+	ytClient, err := youtube.NewClient(clientCtx, httpClient, opts)
 	if err != nil {
-		return fmt.Errorf("failed to create youtube client: %w", err)
+		return nil, fmt.Errorf("failed to create youtube client: %w", err)
 	}
 
 	channelName, err := ytClient.ValidateAuth(ctx)
 	if err != nil {
-		return fmt.Errorf("auth validation failed: %w", err)
+		return nil, fmt.Errorf("auth validation failed: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.tenants[userID]; ok {
+		return t, nil
 	}
 	s.logger.Info("authenticated with youtube", "channel", channelName)
 
-	s.ytClient = ytClient
+	t = NewServer(s.logger, ytClient, tokenStatus, s.cfg, nil)
+	t.channelName = channelName
+	t.metrics = s.metrics
+	t.authCheck.record(time.Now(), nil)
+	s.tenants[userID] = t
+	delete(s.disconnected, channelName)
+	return t, nil
+}
+
+// dropTenant forgets the server of the SSE user userID, whose grant is gone.
+func (s *Server) dropTenant(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tenants, userID)
+}
+
+// tenantList returns the SSE per-user servers created so far.
+func (s *Server) tenantList() []*Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Collect(maps.Values(s.tenants))
+}
+
+// registerTools registers all MCP tools. Called once the YouTube client is ready.
+func (s *Server) registerTools() {
 	s.registerAnalyzeTools()
 	s.registerRecommendTools()
-	s.toolsReady = true
-	return nil
+	s.registerPlaylistTools()
+	s.registerLibraryTools()
+	s.registerChannelTools()
+	s.registerVideoTools()
+	s.registerManageTools()
+	s.registerAuthTools()
+	s.registerQuotaTools()
 }
 
 // Run starts the MCP server with the configured transport.
 // Use TRANSPORT=stdio (default) for local MCP clients or TRANSPORT=sse for Railway/HTTP deployments.
 func (s *Server) Run(ctx context.Context) error {
-	switch s.transport {
+	switch s.cfg.Transport {
 	case "sse":
 		return s.runSSE(ctx)
 	default:
@@ -109,15 +213,49 @@ func (s *Server) runStdio(ctx context.Context) error {
 // (for Railway/hosted deployments).
 // Implements MCP OAuth specification (RFC 9728 + RFC 8414 + DCR).
 func (s *Server) runSSE(ctx context.Context) error {
-	addr := fmt.Sprintf(":%d", s.port)
-	s.logger.Info("starting MCP server", "transport", "streamable-http", "addr", addr)
+	// Every HTTP route is served by the MCP OAuth server; there is no unauthenticated fallback
+	if s.mcpOAuth == nil {
+		return fmt.Errorf("SSE transport requires an MCP OAuth server")
+	}
+
+	// Load the TLS certificate up front so a bad one fails startup, not the first request
+	var tlsConfig *tls.Config
+	if s.cfg.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+
+	// Drop users' servers along with their expired or revoked grants
+	s.mcpOAuth.OnGrantRemoved(s.dropTenant)
 
+	// Tell automation about new authentications as they complete
+	if s.cfg.AuthWebhookURL != "" {
+		s.mcpOAuth.OnGrant(func(grantID string) { s.announceGrant(ctx, grantID) })
+	}
+
+	// Revalidate users' tokens in the background so /ready notices revoked ones
+	if s.cfg.AuthCheckInterval > 0 {
+		go s.runAuthChecks(ctx, s.cfg.AuthCheckInterval)
+	}
+
+	addr := net.JoinHostPort(s.cfg.BindAddress, strconv.Itoa(s.cfg.Port))
+	s.logger.Info("starting MCP server", "transport", "streamable-http", "addr", addr, "tls", tlsConfig != nil)
+
+	// Each user gets their own MCP server, bound to their own YouTube account
 	streamHandler := mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
-		if err := s.ensureYTClient(req.Context()); err != nil {
+		info := mcpauth.TokenInfoFromContext(req.Context())
+		if info == nil {
+			return nil
+		}
+		t, err := s.tenant(req.Context(), info.UserID)
+		if err != nil {
 			s.logger.Error("failed to initialize YouTube client", "error", err)
 			return nil
 		}
-		return s.mcpServer
+		return t.mcpServer
 	}, &mcp.StreamableHTTPOptions{
 		Logger: s.logger,
 	})
@@ -127,7 +265,8 @@ func (s *Server) runSSE(ctx context.Context) error {
 	bearerMiddleware := mcpauth.RequireBearerToken(s.mcpOAuth.TokenVerifier(), &mcpauth.RequireBearerTokenOptions{
 		ResourceMetadataURL: resourceMetadataURL,
 	})
-	protectedMCP := bearerMiddleware(streamHandler)
+	streams := newStreamTracker()
+	protectedMCP := streams.wrap(bearerMiddleware(streamHandler))
 
 	mux := http.NewServeMux()
 
@@ -137,6 +276,12 @@ func (s *Server) runSSE(ctx context.Context) error {
 		fmt.Fprint(w, "ok")
 	})
 
+	// Readiness check: 503 while unauthenticated or out of quota
+	mux.HandleFunc("GET /ready", s.readyHandler())
+
+	// Prometheus metrics, optionally behind METRICS_TOKEN
+	mux.HandleFunc("GET /metrics", s.metricsHandler(s.cfg.MetricsToken))
+
 	// MCP OAuth discovery endpoints
 	mux.Handle("GET /.well-known/oauth-protected-resource", s.mcpOAuth.ProtectedResourceMetadataHandler())
 	mux.HandleFunc("GET /.well-known/oauth-authorization-server", s.mcpOAuth.AuthServerMetadataHandler())
@@ -152,13 +297,20 @@ func (s *Server) runSSE(ctx context.Context) error {
 	mux.Handle("/mcp", protectedMCP)
 
 	httpServer := &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Addr:      addr,
+		Handler:   corsMiddleware(s.cfg.AllowedOrigins, mux),
+		TLSConfig: tlsConfig,
 	}
 
 	errCh := make(chan error, 1)
 	go func() {
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			errCh <- fmt.Errorf("SSE HTTP server failed: %w", err)
 		}
 		close(errCh)
@@ -166,11 +318,22 @@ func (s *Server) runSSE(ctx context.Context) error {
 
 	select {
 	case <-ctx.Done():
-		s.logger.Info("shutting down SSE server")
-		if err := httpServer.Shutdown(context.Background()); err != nil {
-			s.logger.Error("failed to shut down SSE server", "error", err)
+		inFlight, openStreams := streams.closeStreams()
+		s.logger.Info("shutting down SSE server", "in_flight", inFlight, "open_streams", openStreams, "timeout", s.cfg.ShutdownTimeout)
+
+		// Drain in-flight requests, but never longer than the shutdown timeout
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			remaining := streams.active()
+			httpServer.Close()
+			if errors.Is(err, context.DeadlineExceeded) {
+				return fmt.Errorf("SSE shutdown timed out after %s, forced close of %d requests: %w", s.cfg.ShutdownTimeout, remaining, err)
+			}
+			return fmt.Errorf("failed to shut down SSE server: %w", err)
 		}
-		return ctx.Err()
+		s.logger.Info("SSE server drained cleanly")
+		return nil
 	case err := <-errCh:
 		return err
 	}
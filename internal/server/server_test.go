@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/gxravel/youtube-music-mcp/internal/config"
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// redirectTransport sends every request to target, keeping its path and query.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// testConfig returns the default configuration, changed by set.
+func testConfig(t *testing.T, set map[string]string) *config.Config {
+	t.Helper()
+	environment := map[string]string{"GOOGLE_CLIENT_ID": "client", "GOOGLE_CLIENT_SECRET": "secret"}
+	for k, v := range set {
+		environment[k] = v
+	}
+	var cfg config.Config
+	if err := env.ParseWithOptions(&cfg, env.Options{Environment: environment}); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	return &cfg
+}
+
+// newTestServer returns a stdio-mode server whose YouTube client calls api
+// instead of the YouTube Data API.
+func newTestServer(t *testing.T, cfg *config.Config, api http.Handler) *Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewServer(logger, newTestYouTubeClient(t, cfg, api), nil, cfg, nil)
+}
+
+// newTestYouTubeClient returns a YouTube client configured by cfg that calls
+// api instead of the YouTube Data API.
+func newTestYouTubeClient(t *testing.T, cfg *config.Config, api http.Handler) *youtube.Client {
+	t.Helper()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	httpClient := &http.Client{Transport: redirectTransport{target: target}}
+	ytClient, err := youtube.NewClient(context.Background(), httpClient, YouTubeOptions(cfg))
+	if err != nil {
+		t.Fatalf("youtube client: %v", err)
+	}
+	return ytClient
+}
+
+// writeAPIError writes a YouTube Data API error response with one error reason.
+func writeAPIError(w http.ResponseWriter, code int, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": reason,
+			"errors":  []map[string]string{{"reason": reason, "message": reason}},
+		},
+	})
+}
+
+// callTool calls the tool name on s through an in-memory MCP session.
+func callTool(t *testing.T, s *Server, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := s.mcpServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() })
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect client: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("call %s: %v", name, err)
+	}
+	return result
+}
+
+// resultText returns the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var text []string
+	for _, content := range result.Content {
+		if c, ok := content.(*mcp.TextContent); ok {
+			text = append(text, c.Text)
+		}
+	}
+	return strings.Join(text, "\n")
+}
+
+// decodeOutput decodes the structured output of a tool result into out.
+func decodeOutput(t *testing.T, result *mcp.CallToolResult, out any) {
+	t.Helper()
+	if result.IsError {
+		t.Fatalf("tool failed: %s", resultText(result))
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("encode output: %v", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// playlistItemsResponse is a one-page playlistItems.list response with an
+// entry "item-<videoID>" for each of videoIDs.
+func playlistItemsResponse(videoIDs ...string) map[string]any {
+	items := make([]map[string]any, len(videoIDs))
+	for i, videoID := range videoIDs {
+		items[i] = map[string]any{
+			"id": "item-" + videoID,
+			"snippet": map[string]any{
+				"title":      "Song " + videoID,
+				"position":   i,
+				"resourceId": map[string]any{"kind": "youtube#video", "videoId": videoID},
+			},
+		}
+	}
+	return map[string]any{"items": items}
+}
+
+func TestYouTubeRequestsCarryUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	var userAgents []string
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents = append(userAgents, r.UserAgent())
+		mu.Unlock()
+		writeJSON(w, playlistItemsResponse())
+	})
+
+	for _, tt := range []struct {
+		appName string
+		want    string
+	}{
+		{"", "youtube-music-mcp/" + serverVersion},
+		{"my-deployment", "my-deployment/" + serverVersion},
+	} {
+		userAgents = nil
+		set := map[string]string{}
+		if tt.appName != "" {
+			set["APP_NAME"] = tt.appName
+		}
+		s := newTestServer(t, testConfig(t, set), api)
+		callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome"})
+		if len(userAgents) != 1 || !strings.HasSuffix(userAgents[0], " "+tt.want) {
+			t.Errorf("APP_NAME %q: User-Agent %q, want it to end in %q", tt.appName, userAgents, tt.want)
+		}
+	}
+}
+
+func TestRunSSEFailsFastOnBadTLSFiles(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct{ name, cert, key string }{
+		{"missing files", filepath.Join(dir, "missing-cert.pem"), filepath.Join(dir, "missing-key.pem")},
+		{"unparseable files", garbage, garbage},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t, map[string]string{"TLS_CERT_FILE": tt.cert, "TLS_KEY_FILE": tt.key, "PORT": "0"})
+			root, ctx := newTestSSEServer(t, cfg, &fakeGoogleAPI{revoked: make(map[string]bool)})
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			if err := root.runSSE(ctx); err == nil || !strings.Contains(err.Error(), "failed to load TLS certificate") {
+				t.Errorf("runSSE err = %v, want the TLS certificate rejected at startup", err)
+			}
+		})
+	}
+}
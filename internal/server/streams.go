@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// streamTracker tracks in-flight MCP HTTP requests so shutdown can report and
+// close them. Long-lived GET streams never finish on their own, so their
+// contexts are cancellable; POSTs (tool calls) are left to complete.
+type streamTracker struct {
+	mu       sync.Mutex
+	inFlight int
+	nextID   int
+	streams  map[int]context.CancelFunc
+}
+
+// newStreamTracker creates an empty tracker.
+func newStreamTracker() *streamTracker {
+	return &streamTracker{streams: make(map[int]context.CancelFunc)}
+}
+
+// wrap tracks every request served by next.
+func (t *streamTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.mu.Lock()
+		t.inFlight++
+		id := t.nextID
+		t.nextID++
+		if r.Method == http.MethodGet {
+			ctx, cancel := context.WithCancel(r.Context())
+			t.streams[id] = cancel
+			r = r.WithContext(ctx)
+		}
+		t.mu.Unlock()
+
+		defer func() {
+			t.mu.Lock()
+			t.inFlight--
+			if cancel, ok := t.streams[id]; ok {
+				cancel()
+				delete(t.streams, id)
+			}
+			t.mu.Unlock()
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// closeStreams signals every open GET stream to close and returns how many
+// requests were in flight and how many of them were streams.
+func (t *streamTracker) closeStreams() (inFlight, streams int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, cancel := range t.streams {
+		cancel()
+	}
+	return t.inFlight, len(t.streams)
+}
+
+// active returns the number of requests still in flight.
+func (t *streamTracker) active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inFlight
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+)
+
+// likeRecencyHalfLife is how long it takes a like's weight to halve when
+// weighting by recency with real timestamps.
+const likeRecencyHalfLife = 180 * 24 * time.Hour
+
+// likeIndexHalfLife is how many positions into the likes playlist it takes a
+// like's weight to halve when no timestamp is available. The likes playlist
+// is returned newest first, so position approximates recency.
+const likeIndexHalfLife = 100
+
+// artistScore is an artist/channel with its taste score.
+type artistScore struct {
+	name  string
+	score float64
+}
+
+// likeWeight returns the weight of the liked video at position index.
+// Prefers the real like timestamp and falls back to the playlist position.
+func likeWeight(v youtube.Video, index int, now time.Time) float64 {
+	if !v.AddedAt.IsZero() {
+		age := max(now.Sub(v.AddedAt), 0)
+		return math.Pow(0.5, float64(age)/float64(likeRecencyHalfLife))
+	}
+	return math.Pow(0.5, float64(index)/likeIndexHalfLife)
+}
+
+// rankArtists scores artists by how often they appear in liked videos and
+// subscriptions, highest score first. Each like counts once, or by its recency
+// weight when weightByRecency is set; each subscription counts once.
+func rankArtists(likedVideos []youtube.Video, subscriptions []youtube.Subscription, weightByRecency bool) []artistScore {
+	now := time.Now()
+	artistMap := make(map[string]float64)
+	for i, v := range likedVideos {
+		if v.ChannelTitle == "" {
+			continue
+		}
+		if weightByRecency {
+			artistMap[v.ChannelTitle] += likeWeight(v, i, now)
+		} else {
+			artistMap[v.ChannelTitle]++
+		}
+	}
+	for _, sub := range subscriptions {
+		if sub.Title != "" {
+			artistMap[sub.Title]++
+		}
+	}
+
+	var artists []artistScore
+	for name, score := range artistMap {
+		artists = append(artists, artistScore{name, score})
+	}
+
+	sortRanked(artists, func(a artistScore) (float64, string) {
+		return a.score, a.name
+	})
+
+	return artists
+}
+
+// sortRanked sorts items by score descending, breaking ties alphabetically by
+// name so rankings built from map iteration are reproducible across runs.
+func sortRanked[T any](items []T, key func(T) (score float64, name string)) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		scoreA, nameA := key(a)
+		scoreB, nameB := key(b)
+		return cmp.Or(cmp.Compare(scoreB, scoreA), cmp.Compare(nameA, nameB))
+	})
+}
+
+// genericMusicTopic is the topic nearly every song carries; it says nothing
+// about genre, so rankGenres leaves it out.
+const genericMusicTopic = "Music"
+
+// genreCount is a genre with the number of liked songs tagged with it.
+type genreCount struct {
+	name  string
+	count int
+}
+
+// rankGenres builds a genre histogram from video topics, most common first.
+func rankGenres(topics map[string][]string) []genreCount {
+	counts := make(map[string]int)
+	for _, names := range topics {
+		for _, name := range names {
+			if name != genericMusicTopic {
+				counts[name]++
+			}
+		}
+	}
+
+	genres := make([]genreCount, 0, len(counts))
+	for name, count := range counts {
+		genres = append(genres, genreCount{name, count})
+	}
+	sortRanked(genres, func(g genreCount) (float64, string) {
+		return float64(g.count), g.name
+	})
+
+	return genres
+}
+
+// tasteLikedVideos fetches the liked videos the taste tools work from: the
+// maxLiked most recent ones, else the server's MAX_TASTE_VIDEOS, else all of
+// them (also when maxLiked is negative). It also returns the limit applied, 0
+// for none.
+func (s *Server) tasteLikedVideos(ctx context.Context, maxLiked int) ([]youtube.Video, int, error) {
+	limit := max(cmp.Or(maxLiked, s.cfg.MaxTasteVideos), 0)
+	videos, err := s.ytClient.GetRecentLikedVideos(ctx, limit)
+	return videos, limit, err
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+)
+
+func TestSortRankedBreaksTiesByName(t *testing.T) {
+	artists := []artistScore{{"Justice", 2}, {"Air", 1}, {"Daft Punk", 3}, {"Cassius", 2}, {"Bon Iver", 1}}
+	sortRanked(artists, func(a artistScore) (float64, string) {
+		return a.score, a.name
+	})
+
+	want := []artistScore{{"Daft Punk", 3}, {"Cassius", 2}, {"Justice", 2}, {"Air", 1}, {"Bon Iver", 1}}
+	if !slices.Equal(artists, want) {
+		t.Errorf("sortRanked = %v, want %v", artists, want)
+	}
+}
+
+func TestRankArtistsIsDeterministic(t *testing.T) {
+	var liked []youtube.Video
+	for _, channel := range []string{"Justice", "Air", "Daft Punk", "Cassius", "Daft Punk", "Bon Iver", "Justice"} {
+		liked = append(liked, youtube.Video{ChannelTitle: channel})
+	}
+	subscriptions := []youtube.Subscription{{Title: "Air"}, {Title: "Phoenix"}}
+
+	want := []artistScore{{"Air", 2}, {"Daft Punk", 2}, {"Justice", 2}, {"Bon Iver", 1}, {"Cassius", 1}, {"Phoenix", 1}}
+	// Map iteration order varies between runs, the ranking must not
+	for range 20 {
+		if got := rankArtists(liked, subscriptions, false); !slices.Equal(got, want) {
+			t.Fatalf("rankArtists = %v, want %v", got, want)
+		}
+	}
+}
@@ -1,35 +1,87 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// Input type for analyze tool
+// Input and output types for analyze tools
 
 type analyzeTastesInput struct {
 	IncludePreviousRecommendations bool `json:"includePreviousRecommendations" jsonschema:"If true also fetch songs from playlists previously created by this tool to adjust analysis"`
+	WeightByRecency                bool `json:"weightByRecency,omitempty" jsonschema:"If true recently liked artists count more in the top artists ranking"`
+	MaxLiked                       int  `json:"maxLiked,omitempty" jsonschema:"Only analyze this many most recently liked videos: faster and cheaper on big libraries but less complete. -1 analyzes all. Defaults to the server's MAX_TASTE_VIDEOS (all unless set)"`
 }
 
-// registerAnalyzeTools registers the analyze-my-tastes MCP tool
+type exportTasteProfileInput struct {
+	MaxLiked int `json:"maxLiked,omitempty" jsonschema:"Only analyze this many most recently liked videos: faster and cheaper on big libraries but less complete. -1 analyzes all. Defaults to the server's MAX_TASTE_VIDEOS (all unless set)"`
+}
+
+type tasteProfileOutput struct {
+	LikedVideos   int                     `json:"likedVideos" jsonschema:"Total number of liked videos (all categories)"`
+	LikedSongs    int                     `json:"likedSongs" jsonschema:"Number of liked videos in the Music category"`
+	Artists       []tasteArtistOutput     `json:"artists" jsonschema:"Unique artists from liked songs and subscriptions, most liked first"`
+	GenreHints    []genreHintOutput       `json:"genreHints" jsonschema:"Video categories of all liked videos, most common first"`
+	TopGenres     []genreOutput           `json:"topGenres" jsonschema:"Music genres from the topics of liked songs, most common first"`
+	Playlists     []playlistSummaryOutput `json:"playlists" jsonschema:"The user's playlists"`
+	Subscriptions []subscriptionOutput    `json:"subscriptions" jsonschema:"The user's channel subscriptions"`
+	QuotaUsed     int                     `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type tasteArtistOutput struct {
+	Name       string `json:"name" jsonschema:"Artist or channel name"`
+	LikedSongs int    `json:"likedSongs" jsonschema:"Number of liked songs by this artist"`
+	Subscribed bool   `json:"subscribed" jsonschema:"Whether the user is subscribed to this artist's channel"`
+}
+
+type genreHintOutput struct {
+	Category   string `json:"category" jsonschema:"Video category name"`
+	CategoryID string `json:"categoryId" jsonschema:"YouTube video category ID"`
+	Count      int    `json:"count" jsonschema:"Number of liked videos in this category"`
+}
+
+type genreOutput struct {
+	Genre string `json:"genre" jsonschema:"Genre name from YouTube topic categories"`
+	Count int    `json:"count" jsonschema:"Number of liked songs tagged with this genre"`
+}
+
+type playlistSummaryOutput struct {
+	ID        string `json:"id" jsonschema:"Playlist ID"`
+	Title     string `json:"title" jsonschema:"Playlist title"`
+	ItemCount int64  `json:"itemCount" jsonschema:"Number of items in the playlist"`
+}
+
+type subscriptionOutput struct {
+	ChannelID string `json:"channelId" jsonschema:"Subscribed channel ID"`
+	Title     string `json:"title" jsonschema:"Subscribed channel name"`
+}
+
+// registerAnalyzeTools registers the taste analysis MCP tools
 func (s *Server) registerAnalyzeTools() {
 	// Tool: ym:analyze-my-tastes
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "ym:analyze-my-tastes",
-		Description: "Analyzes the user's YouTube Music taste by gathering liked videos (music only), subscriptions, playlists, and optionally previously recommended songs. Returns structured text analysis for the LLM to interpret. Quota cost: ~5-10 units plus ~1 unit per 50 liked videos for music filtering.",
+		Description: "Analyzes the user's YouTube Music taste by gathering liked videos (music only), subscriptions, playlists, and optionally previously recommended songs. Ranks top artists, optionally weighting recent likes more, and top genres from YouTube topic categories. Returns structured text analysis for the LLM to interpret. Quota cost: ~5-10 units plus ~1 unit per 50 liked videos for music filtering and ~1 unit per 50 liked songs for genres.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeTastesInput) (*mcp.CallToolResult, any, error) {
 		var output strings.Builder
 
 		output.WriteString("# YouTube Music Taste Analysis\n\n")
 
-		// 1. Fetch ALL liked videos (no cap)
-		likedVideos, err := s.ytClient.GetLikedVideos(ctx)
+		// 1. Fetch liked videos (all, unless capped)
+		likedVideos, limit, err := s.tasteLikedVideos(ctx, input.MaxLiked)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
 		}
+		capped := limit > 0 && len(likedVideos) == limit
+
+		// Deleted and private videos say nothing about taste
+		likedVideos, unavailable := youtube.SkipUnavailable(likedVideos)
 
 		// Filter to music-only (categoryId=10)
 		likedVideos, err = s.ytClient.FilterMusicVideos(ctx, likedVideos)
@@ -38,6 +90,12 @@ func (s *Server) registerAnalyzeTools() {
 		}
 
 		fmt.Fprintf(&output, "## Liked Songs - music only (%d songs)\n\n", len(likedVideos))
+		if capped {
+			fmt.Fprintf(&output, "_Only the %d most recent likes were analyzed; older ones were left out._\n\n", limit)
+		}
+		if unavailable > 0 {
+			fmt.Fprintf(&output, "_%d deleted or private liked videos skipped._\n\n", unavailable)
+		}
 		for _, v := range likedVideos {
 			fmt.Fprintf(&output, "- %s - %s\n", v.Title, v.ChannelTitle)
 		}
@@ -55,6 +113,32 @@ func (s *Server) registerAnalyzeTools() {
 		}
 		output.WriteString("\n")
 
+		// Rank top artists from likes and subscriptions
+		artists := rankArtists(likedVideos, subscriptions, input.WeightByRecency)
+		if input.WeightByRecency {
+			output.WriteString("## Top Artists - weighted by recency of likes\n\n")
+		} else {
+			output.WriteString("## Top Artists\n\n")
+		}
+		for i := 0; i < len(artists) && i < 10; i++ {
+			fmt.Fprintf(&output, "- %s (score %.1f)\n", artists[i].name, artists[i].score)
+		}
+		output.WriteString("\n")
+
+		// Infer genres from the topics YouTube assigns to liked songs
+		genres, err := s.likedGenres(ctx, likedVideos)
+		if err != nil {
+			return nil, nil, err
+		}
+		output.WriteString("## Top Genres\n\n")
+		if len(genres) == 0 {
+			output.WriteString("No genre data available for liked songs.\n")
+		}
+		for i := 0; i < len(genres) && i < 10; i++ {
+			fmt.Fprintf(&output, "- %s (%d songs)\n", genres[i].name, genres[i].count)
+		}
+		output.WriteString("\n")
+
 		// 3. Fetch ALL user's playlists (no cap)
 		playlists, err := s.ytClient.ListPlaylists(ctx)
 		if err != nil {
@@ -74,14 +158,16 @@ func (s *Server) registerAnalyzeTools() {
 			recommendedSongs := 0
 			for _, pl := range playlists {
 				// Check if playlist was created by this tool
-				if strings.HasPrefix(pl.Title, "[YM-MCP]") {
+				if s.isOwnPlaylist(pl.Title) {
 					// Fetch all playlist items (no cap)
 					items, err := s.ytClient.GetPlaylistItems(ctx, pl.ID)
 					if err != nil {
 						// Log error but continue
 						s.logger.Warn("failed to fetch items for playlist", "playlist", pl.Title, "error", err)
+						youtube.NoteFallback(ctx, "could not read playlist '%s'; skipped it", pl.Title)
 						continue
 					}
+					items, _ = youtube.SkipUnavailable(items)
 
 					if len(items) > 0 {
 						fmt.Fprintf(&output, "\nFrom playlist '%s':\n", pl.Title)
@@ -106,4 +192,135 @@ func (s *Server) registerAnalyzeTools() {
 			},
 		}, nil, nil
 	})
+
+	// Tool: ym:export-taste-profile
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:export-taste-profile",
+		Description: "Exports the user's YouTube Music taste profile as structured JSON for integrations: unique artists with like counts, genre hints from liked video categories, top genres from liked song topics, playlist summaries, and subscriptions. Use ym:analyze-my-tastes for a text analysis instead. Quota cost: ~5-10 units plus ~1 unit per 50 liked videos and ~1 unit per 50 liked songs for genres.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input exportTasteProfileInput) (*mcp.CallToolResult, *tasteProfileOutput, error) {
+		likedVideos, _, err := s.tasteLikedVideos(ctx, input.MaxLiked)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
+		}
+
+		ids := make([]string, 0, len(likedVideos))
+		for _, v := range likedVideos {
+			ids = append(ids, v.ID)
+		}
+		categories, err := s.ytClient.GetVideoCategories(ctx, ids)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get video categories: %w", err)
+		}
+
+		subscriptions, err := s.ytClient.GetSubscriptions(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get subscriptions: %w", err)
+		}
+
+		playlists, err := s.ytClient.ListPlaylists(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list playlists: %w", err)
+		}
+
+		out := &tasteProfileOutput{
+			LikedVideos:   len(likedVideos),
+			Artists:       []tasteArtistOutput{},
+			GenreHints:    []genreHintOutput{},
+			TopGenres:     []genreOutput{},
+			Playlists:     make([]playlistSummaryOutput, 0, len(playlists)),
+			Subscriptions: make([]subscriptionOutput, 0, len(subscriptions)),
+		}
+
+		// Count liked songs per artist and liked videos per category
+		artistIndex := make(map[string]int)
+		categoryCounts := make(map[string]int)
+		var likedSongs []youtube.Video
+		musicID := s.ytClient.ResolveMusicCategoryID(ctx)
+		for _, v := range likedVideos {
+			categoryID, ok := categories[v.ID]
+			if !ok {
+				continue
+			}
+			categoryCounts[categoryID]++
+
+			if categoryID != musicID || v.ChannelTitle == "" {
+				continue
+			}
+			out.LikedSongs++
+			likedSongs = append(likedSongs, v)
+			i, ok := artistIndex[v.ChannelTitle]
+			if !ok {
+				i = len(out.Artists)
+				artistIndex[v.ChannelTitle] = i
+				out.Artists = append(out.Artists, tasteArtistOutput{Name: v.ChannelTitle})
+			}
+			out.Artists[i].LikedSongs++
+		}
+
+		for _, sub := range subscriptions {
+			out.Subscriptions = append(out.Subscriptions, subscriptionOutput{
+				ChannelID: sub.ChannelID,
+				Title:     sub.Title,
+			})
+			if sub.Title == "" {
+				continue
+			}
+			i, ok := artistIndex[sub.Title]
+			if !ok {
+				i = len(out.Artists)
+				artistIndex[sub.Title] = i
+				out.Artists = append(out.Artists, tasteArtistOutput{Name: sub.Title})
+			}
+			out.Artists[i].Subscribed = true
+		}
+
+		sortRanked(out.Artists, func(a tasteArtistOutput) (float64, string) {
+			return float64(a.LikedSongs), a.Name
+		})
+
+		for categoryID, count := range categoryCounts {
+			out.GenreHints = append(out.GenreHints, genreHintOutput{
+				Category:   youtube.CategoryName(categoryID),
+				CategoryID: categoryID,
+				Count:      count,
+			})
+		}
+		slices.SortFunc(out.GenreHints, func(a, b genreHintOutput) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Category, b.Category))
+		})
+
+		genres, err := s.likedGenres(ctx, likedSongs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, g := range genres {
+			out.TopGenres = append(out.TopGenres, genreOutput{Genre: g.name, Count: g.count})
+		}
+
+		for _, pl := range playlists {
+			out.Playlists = append(out.Playlists, playlistSummaryOutput{
+				ID:        pl.ID,
+				Title:     pl.Title,
+				ItemCount: pl.ItemCount,
+			})
+		}
+
+		// Structured output only; the SDK also serializes it as the text content
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+}
+
+// likedGenres ranks the genres of the given liked songs by their YouTube
+// topic categories. Songs without topic data are simply not counted.
+func (s *Server) likedGenres(ctx context.Context, songs []youtube.Video) ([]genreCount, error) {
+	ids := make([]string, 0, len(songs))
+	for _, v := range songs {
+		ids = append(ids, v.ID)
+	}
+	topics, err := s.ytClient.FetchVideoTopics(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video topics: %w", err)
+	}
+	return rankGenres(topics), nil
 }
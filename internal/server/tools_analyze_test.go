@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// libraryAPI fakes a YouTube library with no likes or subscriptions whose
+// playlists can be created, listed, filled and read back.
+type libraryAPI struct {
+	mu        sync.Mutex
+	titles    map[string]string   // playlist ID -> title
+	order     []string            // playlist IDs in creation order
+	playlists map[string][]string // playlist ID -> video IDs
+}
+
+func newLibraryAPI() *libraryAPI {
+	return &libraryAPI{titles: make(map[string]string), playlists: make(map[string][]string)}
+}
+
+func (l *libraryAPI) handler() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":             "UCme",
+			"contentDetails": map[string]any{"relatedPlaylists": map[string]any{"likes": "LLme"}},
+		}}})
+	})
+	api.HandleFunc("GET /youtube/v3/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		items := []any{}
+		for _, id := range l.order {
+			items = append(items, map[string]any{
+				"id":             id,
+				"snippet":        map[string]any{"title": l.titles[id]},
+				"status":         map[string]any{"privacyStatus": "private"},
+				"contentDetails": map[string]any{"itemCount": len(l.playlists[id])},
+			})
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	api.HandleFunc("POST /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		var playlist struct {
+			Snippet struct {
+				Title string `json:"title"`
+			} `json:"snippet"`
+		}
+		json.NewDecoder(r.Body).Decode(&playlist)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		id := fmt.Sprintf("PLcreated%d", len(l.order)+1)
+		l.titles[id] = playlist.Snippet.Title
+		l.order = append(l.order, id)
+		writeJSON(w, map[string]any{"id": id, "snippet": map[string]any{"title": playlist.Snippet.Title}, "status": map[string]any{"privacyStatus": "private"}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		writeJSON(w, playlistItemsResponse(l.playlists[r.URL.Query().Get("playlistId")]...))
+	})
+	api.HandleFunc("POST /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		var item struct {
+			Snippet struct {
+				PlaylistID string `json:"playlistId"`
+				ResourceID struct {
+					VideoID string `json:"videoId"`
+				} `json:"resourceId"`
+			} `json:"snippet"`
+		}
+		json.NewDecoder(r.Body).Decode(&item)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.playlists[item.Snippet.PlaylistID] = append(l.playlists[item.Snippet.PlaylistID], item.Snippet.ResourceID.VideoID)
+		writeJSON(w, map[string]any{"id": "item-" + item.Snippet.ResourceID.VideoID})
+	})
+	return api
+}
+
+func TestCustomPlaylistPrefixRoundTrips(t *testing.T) {
+	library := newLibraryAPI()
+	// A playlist another server created under the default prefix
+	library.titles["PLother"] = defaultPlaylistPrefix + " Someone else's"
+	library.order = append(library.order, "PLother")
+	library.playlists["PLother"] = []string{"other000001"}
+	s := newTestServer(t, testConfig(t, map[string]string{"PLAYLIST_PREFIX": "[Mine]"}), library.handler())
+
+	var imported importPlaylistOutput
+	decodeOutput(t, callTool(t, s, "ym:import-playlist", map[string]any{
+		"title":  "Road trip",
+		"videos": []string{"song0000001", "song0000002"},
+	}), &imported)
+	if got := library.titles[imported.PlaylistID]; got != "[Mine] Road trip" {
+		t.Fatalf("created playlist %q, want the custom prefix", got)
+	}
+
+	result := callTool(t, s, "ym:analyze-my-tastes", map[string]any{"includePreviousRecommendations": true})
+	text := resultText(result)
+	if result.IsError {
+		t.Fatalf("analyze-my-tastes failed: %s", text)
+	}
+	if !strings.Contains(text, "From playlist '[Mine] Road trip'") || !strings.Contains(text, "Song song0000002") {
+		t.Errorf("analysis does not list the songs of the custom-prefixed playlist:\n%s", text)
+	}
+	if strings.Contains(text, "From playlist '"+defaultPlaylistPrefix) {
+		t.Errorf("analysis lists a playlist under the default prefix as its own:\n%s", text)
+	}
+}
+
+func TestAnalyzeMyTastesMaxLikedCapsFetchAndOutput(t *testing.T) {
+	// 150 likes over three pages of 50, newest first
+	var liked []string
+	for i := range 150 {
+		liked = append(liked, fmt.Sprintf("liked%06d", i))
+	}
+	var (
+		mu         sync.Mutex
+		likedPages int
+	)
+	api := http.NewServeMux()
+	api.Handle("/", newLibraryAPI().handler())
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("playlistId") != "LLme" {
+			writeJSON(w, playlistItemsResponse())
+			return
+		}
+		mu.Lock()
+		likedPages++
+		mu.Unlock()
+		start := 0
+		fmt.Sscanf(r.URL.Query().Get("pageToken"), "page%d", &start)
+		page := playlistItemsResponse(liked[start : start+50]...)
+		if start+50 < len(liked) {
+			page["nextPageToken"] = fmt.Sprintf("page%d", start+50)
+		}
+		writeJSON(w, page)
+	})
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, id := range r.URL.Query()["id"] {
+			for id := range strings.SplitSeq(id, ",") {
+				items = append(items, map[string]any{"id": id, "snippet": map[string]any{"categoryId": "10"}})
+			}
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		maxLiked  int
+		wantSongs int
+		wantPages int
+	}{
+		{"uncapped", nil, 0, 150, 3},
+		{"maxLiked", nil, 60, 60, 2},
+		{"MAX_TASTE_VIDEOS", map[string]string{"MAX_TASTE_VIDEOS": "40"}, 0, 40, 1},
+		{"maxLiked overrides MAX_TASTE_VIDEOS", map[string]string{"MAX_TASTE_VIDEOS": "40"}, 120, 120, 3},
+		{"maxLiked -1 analyzes all", map[string]string{"MAX_TASTE_VIDEOS": "40"}, -1, 150, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			likedPages = 0
+			mu.Unlock()
+			s := newTestServer(t, testConfig(t, tt.env), api)
+
+			result := callTool(t, s, "ym:analyze-my-tastes", map[string]any{"includePreviousRecommendations": false, "maxLiked": tt.maxLiked})
+			text := resultText(result)
+			if result.IsError {
+				t.Fatalf("analyze-my-tastes failed: %s", text)
+			}
+			if want := fmt.Sprintf("## Liked Songs - music only (%d songs)", tt.wantSongs); !strings.Contains(text, want) {
+				t.Errorf("output does not contain %q", want)
+			}
+			if listed := strings.Count(text, "- Song liked"); listed != tt.wantSongs {
+				t.Errorf("output lists %d liked songs, want %d", listed, tt.wantSongs)
+			}
+			capped := strings.Contains(text, "most recent likes were analyzed")
+			if capped != (tt.wantSongs < len(liked)) {
+				t.Errorf("cap note shown = %v, want %v", capped, tt.wantSongs < len(liked))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if likedPages != tt.wantPages {
+				t.Errorf("fetched %d pages of likes, want %d", likedPages, tt.wantPages)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Input and output types for auth tools
+
+type tokenStatusInput struct{}
+
+type tokenStatusOutput struct {
+	AccessTokenExpiry string `json:"accessTokenExpiry" jsonschema:"When the current access token expires (RFC 3339)"`
+	ExpiresInSeconds  int64  `json:"expiresInSeconds" jsonschema:"Seconds until the current access token expires"`
+	HasRefreshToken   bool   `json:"hasRefreshToken" jsonschema:"Whether a refresh token is available to renew the access token"`
+	RefreshPersisted  bool   `json:"refreshPersisted" jsonschema:"Whether refreshed tokens survive a server restart"`
+	Storage           string `json:"storage" jsonschema:"Where the token is kept: file, env, or memory"`
+	ReauthEstimate    string `json:"reauthEstimate,omitempty" jsonschema:"Estimated time re-authentication will be needed (RFC 3339); empty if none is expected"`
+	ReauthReason      string `json:"reauthReason,omitempty" jsonschema:"Why re-authentication will be needed"`
+}
+
+// registerAuthTools registers the token-status MCP tool
+func (s *Server) registerAuthTools() {
+	// Tool: ym:token-status
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:token-status",
+		Description: "Reports the Google OAuth token's remaining validity: access token expiry, whether a refresh token is present, and when re-authentication will likely be needed. Refreshes the access token if it has expired. Does not call the YouTube API. Quota cost: 0 units.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input tokenStatusInput) (*mcp.CallToolResult, *tokenStatusOutput, error) {
+		if s.tokenStatus == nil {
+			return nil, nil, fmt.Errorf("token status is not available for this server")
+		}
+
+		status, err := s.tokenStatus.TokenStatus(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get token status: %w", err)
+		}
+
+		out := &tokenStatusOutput{
+			HasRefreshToken:  status.HasRefreshToken,
+			RefreshPersisted: status.RefreshPersisted,
+			Storage:          status.Storage,
+			ReauthReason:     status.ReauthReason,
+		}
+		if !status.Expiry.IsZero() {
+			out.AccessTokenExpiry = status.Expiry.Format(time.RFC3339)
+			out.ExpiresInSeconds = int64(time.Until(status.Expiry).Seconds())
+		}
+		if !status.ReauthEstimate.IsZero() {
+			out.ReauthEstimate = status.ReauthEstimate.Format(time.RFC3339)
+		}
+
+		// Build human-readable summary
+		var output strings.Builder
+		output.WriteString("# OAuth Token Status\n\n")
+		if out.AccessTokenExpiry != "" {
+			fmt.Fprintf(&output, "**Access token expires:** %s (in %s)\n\n", out.AccessTokenExpiry, time.Until(status.Expiry).Round(time.Second))
+		} else {
+			output.WriteString("**Access token expires:** unknown (no expiry set)\n\n")
+		}
+		fmt.Fprintf(&output, "**Refresh token present:** %t\n\n", out.HasRefreshToken)
+		fmt.Fprintf(&output, "**Token storage:** %s (refreshes persisted: %t)\n\n", out.Storage, out.RefreshPersisted)
+		switch {
+		case out.ReauthEstimate != "":
+			fmt.Fprintf(&output, "**Re-authentication needed by:** ~%s — %s\n", out.ReauthEstimate, out.ReauthReason)
+		case out.ReauthReason != "":
+			fmt.Fprintf(&output, "**Re-authentication:** %s\n", out.ReauthReason)
+		default:
+			output.WriteString("**Re-authentication:** not expected while the refresh token remains valid\n")
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: output.String()},
+			},
+		}, out, nil
+	})
+}
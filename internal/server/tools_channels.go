@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Input and output types for channel tools
+
+type getChannelInput struct {
+	ChannelID string `json:"channelId" jsonschema:"YouTube channel ID (starts with UC)"`
+}
+
+type channelOutput struct {
+	Found                 bool   `json:"found" jsonschema:"Whether the channel exists"`
+	ID                    string `json:"id,omitempty" jsonschema:"Channel ID"`
+	Title                 string `json:"title,omitempty" jsonschema:"Channel name"`
+	Description           string `json:"description,omitempty" jsonschema:"Channel description"`
+	SubscriberCount       uint64 `json:"subscriberCount,omitempty" jsonschema:"Number of subscribers (omitted when hidden)"`
+	HiddenSubscriberCount bool   `json:"hiddenSubscriberCount,omitempty" jsonschema:"Whether the channel hides its subscriber count"`
+	VideoCount            uint64 `json:"videoCount,omitempty" jsonschema:"Number of public videos"`
+	UploadsPlaylistID     string `json:"uploadsPlaylistId,omitempty" jsonschema:"ID of the playlist holding all of the channel's uploads"`
+	QuotaUsed             int    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type getChannelUploadsInput struct {
+	ChannelID  string `json:"channelId" jsonschema:"YouTube channel ID (starts with UC)"`
+	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of most recent uploads to return (default 50)"`
+}
+
+type getChannelUploadsOutput struct {
+	Videos    []videoOutput `json:"videos" jsonschema:"The channel's uploads, most recent first"`
+	QuotaUsed int           `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type whoAmIInput struct{}
+
+// newChannelOutput converts a domain channel into tool output.
+func newChannelOutput(channel youtube.Channel) channelOutput {
+	return channelOutput{
+		Found:                 true,
+		ID:                    channel.ID,
+		Title:                 channel.Title,
+		Description:           channel.Description,
+		SubscriberCount:       channel.SubscriberCount,
+		HiddenSubscriberCount: channel.HiddenSubscriberCount,
+		VideoCount:            channel.VideoCount,
+		UploadsPlaylistID:     channel.UploadsPlaylistID,
+	}
+}
+
+// registerChannelTools registers the channel MCP tools
+func (s *Server) registerChannelTools() {
+	// Tool: ym:get-channel
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-channel",
+		Description: "Gets a YouTube channel's metadata and statistics: name, description, subscriber count (unless hidden), video count, and uploads playlist ID. Useful for reasoning about an artist before recommending or subscribing. Quota cost: 1 unit.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getChannelInput) (*mcp.CallToolResult, *channelOutput, error) {
+		channel, err := s.ytClient.GetChannel(ctx, input.ChannelID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get channel: %w", err)
+		}
+		if channel == nil {
+			return nil, &channelOutput{Found: false, QuotaUsed: quotaSpent(ctx)}, nil
+		}
+
+		out := newChannelOutput(*channel)
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, &out, nil
+	})
+
+	// Tool: ym:get-channel-uploads
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-channel-uploads",
+		Description: "Lists a channel's most recent uploads so an agent can explore an artist's catalog. Much cheaper than searching: quota cost is 1 unit for the channel lookup + 1 unit per 50 videos.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getChannelUploadsInput) (*mcp.CallToolResult, *getChannelUploadsOutput, error) {
+		videos, err := s.ytClient.GetChannelUploads(ctx, input.ChannelID, input.MaxResults)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get channel uploads: %w", err)
+		}
+
+		out := &getChannelUploadsOutput{
+			Videos: make([]videoOutput, 0, len(videos)),
+		}
+		for _, v := range videos {
+			out.Videos = append(out.Videos, newVideoOutput(v))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+	// Tool: ym:who-am-i
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:who-am-i",
+		Description: "Returns the YouTube channel this server is authenticated as: name, channel ID, subscriber and video counts. Call it before mutating operations to confirm the right account is being changed. Quota cost: 1 unit.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input whoAmIInput) (*mcp.CallToolResult, *channelOutput, error) {
+		channel, err := s.ytClient.GetMyChannel(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get authenticated channel: %w", err)
+		}
+
+		out := newChannelOutput(*channel)
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, &out, nil
+	})
+}
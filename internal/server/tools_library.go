@@ -0,0 +1,424 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// allResults is the maxResults sentinel that asks a paginated tool for every
+// result in one response instead of a page.
+const allResults = -1
+
+// largeResultWarning is the result count above which fetching everything is
+// logged, since the whole listing is held in memory and returned at once.
+const largeResultWarning = 1000
+
+// Input and output types for library browsing tools
+
+type listPlaylistsInput struct {
+	PageToken  string `json:"pageToken,omitempty" jsonschema:"Page token from a previous call's nextPageToken. Omit for the first page."`
+	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Playlists per page (1-50, default 50), or -1 for all playlists in one response"`
+}
+
+type listPlaylistsOutput struct {
+	Playlists     []playlistOutput `json:"playlists" jsonschema:"Playlists on this page"`
+	NextPageToken string           `json:"nextPageToken,omitempty" jsonschema:"Token for the next page; empty on the last page"`
+	QuotaUsed     int              `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type getPlaylistItemsInput struct {
+	PlaylistID      string `json:"playlistId" jsonschema:"ID or URL of the playlist to read"`
+	PageToken       string `json:"pageToken,omitempty" jsonschema:"Page token from a previous call's nextPageToken. Omit for the first page."`
+	MaxResults      int64  `json:"maxResults,omitempty" jsonschema:"Items per page (1-50, default 50), or -1 for the whole playlist in one response"`
+	SkipUnavailable bool   `json:"skipUnavailable,omitempty" jsonschema:"If true leave out deleted and private videos. By default they are listed so gaps in the playlist are visible"`
+}
+
+type getPlaylistItemsOutput struct {
+	Items         []videoOutput `json:"items" jsonschema:"Videos on this page"`
+	Skipped       int           `json:"skipped,omitempty" jsonschema:"Number of deleted or private videos left out of this page"`
+	NextPageToken string        `json:"nextPageToken,omitempty" jsonschema:"Token for the next page; empty on the last page"`
+	QuotaUsed     int           `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type findPlaylistInput struct {
+	Name  string `json:"name" jsonschema:"Playlist name to look for"`
+	Exact bool   `json:"exact,omitempty" jsonschema:"If true only return playlists whose title equals the name, ignoring case and the server's playlist prefix"`
+}
+
+type findPlaylistOutput struct {
+	Matches   []playlistMatchOutput `json:"matches" jsonschema:"Matching playlists, closest first"`
+	QuotaUsed int                   `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type listGeneratedPlaylistsInput struct {
+	OlderThanDays int `json:"olderThanDays,omitempty" jsonschema:"Only list playlists created more than this many days ago, as cleanup candidates. 0 (default) lists all"`
+}
+
+type listGeneratedPlaylistsOutput struct {
+	Prefix      string           `json:"prefix" jsonschema:"Title prefix identifying playlists this server created"`
+	Playlists   []playlistOutput `json:"playlists" jsonschema:"Generated playlists, newest first"`
+	TotalItems  int64            `json:"totalItems" jsonschema:"Number of items across the listed playlists"`
+	CleanupHint string           `json:"cleanupHint,omitempty" jsonschema:"How to clean up the stale playlists, when olderThanDays found some"`
+	QuotaUsed   int              `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type playlistMatchOutput struct {
+	playlistOutput
+	Match string `json:"match" jsonschema:"How the title matched: exact, prefix, contains or fuzzy (all words present)"`
+}
+
+type getPlaylistInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"Playlist ID or URL"`
+}
+
+type getPlaylistOutput struct {
+	Found     bool            `json:"found" jsonschema:"Whether the playlist exists and is visible to this account"`
+	Playlist  *playlistOutput `json:"playlist,omitempty" jsonschema:"The playlist's metadata, if found"`
+	QuotaUsed int             `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type getSpecialPlaylistInput struct {
+	Kind      string `json:"kind" jsonschema:"Which special playlist: likes, uploads, favorites, watchLater or watchHistory"`
+	MusicOnly bool   `json:"musicOnly,omitempty" jsonschema:"If true only return videos in the Music category, e.g. a clean list of liked songs. Costs 1 more quota unit per 50 videos"`
+}
+
+type getSpecialPlaylistOutput struct {
+	Kind      string        `json:"kind" jsonschema:"The special playlist read"`
+	Available bool          `json:"available" jsonschema:"Whether the YouTube API exposes this playlist for the account; watch history and watch later usually are not"`
+	Message   string        `json:"message,omitempty" jsonschema:"Why the playlist is unavailable or empty"`
+	Videos    []videoOutput `json:"videos" jsonschema:"Videos in the playlist"`
+	NonMusic  int           `json:"nonMusic,omitempty" jsonschema:"Videos left out by musicOnly because they are not in the Music category"`
+	QuotaUsed int           `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type playlistOutput struct {
+	ID            string `json:"id" jsonschema:"Playlist ID"`
+	Title         string `json:"title" jsonschema:"Playlist title"`
+	Description   string `json:"description,omitempty" jsonschema:"Playlist description"`
+	ItemCount     int64  `json:"itemCount" jsonschema:"Number of items in the playlist"`
+	PrivacyStatus string `json:"privacyStatus,omitempty" jsonschema:"Playlist privacy: public, private or unlisted (when known)"`
+	CreatedAt     string `json:"createdAt,omitempty" jsonschema:"When the playlist was created (RFC 3339, when known)"`
+}
+
+type videoOutput struct {
+	ID             string `json:"id" jsonschema:"Video ID"`
+	Title          string `json:"title" jsonschema:"Video title"`
+	ChannelTitle   string `json:"channelTitle,omitempty" jsonschema:"Channel (artist) that uploaded the video"`
+	AddedAt        string `json:"addedAt,omitempty" jsonschema:"When the video was added to the playlist (RFC 3339)"`
+	PlaylistItemID string `json:"playlistItemId,omitempty" jsonschema:"ID of this entry within the playlist, used to remove it"`
+	ThumbnailURL   string `json:"thumbnailUrl,omitempty" jsonschema:"URL of the video's thumbnail image"`
+}
+
+// Playlist name match kinds, closest first.
+var playlistMatchKinds = []string{"exact", "prefix", "contains", "fuzzy"}
+
+// matchPlaylistName reports how closely a playlist title matches name, as an
+// index into playlistMatchKinds, ignoring case and the server's playlist
+// prefix. Fuzzy means every word of name appears somewhere in the title.
+func (s *Server) matchPlaylistName(name, title string) (kind int, ok bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	lowerTitle := strings.ToLower(strings.TrimSpace(title))
+	bareTitle := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(title, s.playlistPrefix())))
+
+	switch {
+	case lowerTitle == name || bareTitle == name:
+		return 0, true
+	case strings.HasPrefix(lowerTitle, name) || strings.HasPrefix(bareTitle, name):
+		return 1, true
+	case strings.Contains(lowerTitle, name):
+		return 2, true
+	}
+
+	words := strings.Fields(nonWordRe.ReplaceAllString(name, " "))
+	if len(words) == 0 {
+		return 0, false
+	}
+	for _, word := range words {
+		if !strings.Contains(lowerTitle, word) {
+			return 0, false
+		}
+	}
+	return 3, true
+}
+
+// newPlaylistOutput converts a domain playlist into tool output.
+func newPlaylistOutput(pl youtube.Playlist) playlistOutput {
+	out := playlistOutput{
+		ID:            pl.ID,
+		Title:         pl.Title,
+		Description:   pl.Description,
+		ItemCount:     pl.ItemCount,
+		PrivacyStatus: pl.PrivacyStatus,
+	}
+	if !pl.CreatedAt.IsZero() {
+		out.CreatedAt = pl.CreatedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// newVideoOutput converts a domain video into tool output.
+func newVideoOutput(v youtube.Video) videoOutput {
+	out := videoOutput{
+		ID:             v.ID,
+		Title:          v.Title,
+		ChannelTitle:   v.ChannelTitle,
+		PlaylistItemID: v.PlaylistItemID,
+		ThumbnailURL:   v.ThumbnailURL,
+	}
+	if !v.AddedAt.IsZero() {
+		out.AddedAt = v.AddedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// playlistReadError returns a targeted error for a playlist the user asked
+// for that is private or missing, or wraps err as "failed to <action>".
+func playlistReadError(playlistID, action string, err error) error {
+	switch {
+	case errors.Is(err, youtube.ErrPlaylistNotAccessible):
+		return fmt.Errorf("%w: %s is private or its items are hidden from this account; only its owner can read it", youtube.ErrPlaylistNotAccessible, playlistID)
+	case errors.Is(err, youtube.ErrPlaylistNotFound):
+		return fmt.Errorf("%w: %s does not exist; check the ID or look it up with ym:find-playlist", youtube.ErrPlaylistNotFound, playlistID)
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// findPlaylistByTitle returns the user's playlist whose title is exactly
+// title, or nil if there is none.
+func (s *Server) findPlaylistByTitle(ctx context.Context, title string) (*youtube.Playlist, error) {
+	playlists, err := s.ytClient.ListPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+	for _, pl := range playlists {
+		if pl.Title == title {
+			return &pl, nil
+		}
+	}
+	return nil, nil
+}
+
+// registerLibraryTools registers the paginated library browsing MCP tools
+func (s *Server) registerLibraryTools() {
+	// Tool: ym:list-playlists
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:list-playlists",
+		Description: "Lists one page of the user's playlists with their privacy status (public, private or unlisted). Pass nextPageToken back as pageToken to fetch more, so large libraries don't flood the context. Set maxResults to -1 to get all playlists at once. Quota cost: 1 unit per page (of 50 playlists).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input listPlaylistsInput) (*mcp.CallToolResult, *listPlaylistsOutput, error) {
+		var playlists []youtube.Playlist
+		var nextPageToken string
+		var err error
+		if input.MaxResults == allResults {
+			if input.PageToken != "" {
+				return nil, nil, fmt.Errorf("pageToken cannot be combined with maxResults -1, which returns all playlists")
+			}
+			playlists, err = s.ytClient.ListPlaylists(ctx)
+			if len(playlists) > largeResultWarning {
+				s.logger.Warn("returning a large listing", "tool", req.Params.Name, "results", len(playlists))
+			}
+		} else {
+			playlists, nextPageToken, err = s.ytClient.ListPlaylistsPage(ctx, input.PageToken, input.MaxResults)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list playlists: %w", err)
+		}
+
+		out := &listPlaylistsOutput{
+			Playlists:     make([]playlistOutput, 0, len(playlists)),
+			NextPageToken: nextPageToken,
+		}
+		for _, pl := range playlists {
+			out.Playlists = append(out.Playlists, newPlaylistOutput(pl))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:find-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:find-playlist",
+		Description: "Finds the user's playlists by name, to get the ID other tools need. Returns every match, closest first (exact, prefix, contains, then fuzzy: all words present), so ambiguous names can be resolved instead of guessed. Set exact to only return exact title matches. Quota cost: 1 unit per 50 playlists (cached).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input findPlaylistInput) (*mcp.CallToolResult, *findPlaylistOutput, error) {
+		if strings.TrimSpace(input.Name) == "" {
+			return nil, nil, fmt.Errorf("name cannot be empty")
+		}
+
+		playlists, err := s.ytClient.ListPlaylists(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list playlists: %w", err)
+		}
+
+		type match struct {
+			playlist youtube.Playlist
+			kind     int
+		}
+		var matches []match
+		for _, pl := range playlists {
+			kind, ok := s.matchPlaylistName(input.Name, pl.Title)
+			if !ok || (input.Exact && kind != 0) {
+				continue
+			}
+			matches = append(matches, match{pl, kind})
+		}
+		sortRanked(matches, func(m match) (float64, string) {
+			return -float64(m.kind), m.playlist.Title
+		})
+
+		out := &findPlaylistOutput{Matches: make([]playlistMatchOutput, 0, len(matches))}
+		for _, m := range matches {
+			out.Matches = append(out.Matches, playlistMatchOutput{
+				playlistOutput: newPlaylistOutput(m.playlist),
+				Match:          playlistMatchKinds[m.kind],
+			})
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:list-generated-playlists
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:list-generated-playlists",
+		Description: fmt.Sprintf("Lists the playlists this server created (titles starting with %s), newest first, with their item counts and creation dates. Set olderThanDays to only list stale ones as cleanup candidates for ym:delete-playlist. Quota cost: 1 unit per 50 playlists (cached).", s.playlistPrefix()),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input listGeneratedPlaylistsInput) (*mcp.CallToolResult, *listGeneratedPlaylistsOutput, error) {
+		if input.OlderThanDays < 0 {
+			return nil, nil, fmt.Errorf("olderThanDays must be 0 or more, got %d", input.OlderThanDays)
+		}
+		playlists, err := s.ytClient.ListPlaylists(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list playlists: %w", err)
+		}
+
+		var cutoff time.Time
+		if input.OlderThanDays > 0 {
+			cutoff = time.Now().AddDate(0, 0, -input.OlderThanDays)
+		}
+		var generated []youtube.Playlist
+		for _, pl := range playlists {
+			if !s.isOwnPlaylist(pl.Title) {
+				continue
+			}
+			// Playlists of unknown age are never reported as stale
+			if !cutoff.IsZero() && (pl.CreatedAt.IsZero() || !pl.CreatedAt.Before(cutoff)) {
+				continue
+			}
+			generated = append(generated, pl)
+		}
+		// Newest first; unknown creation dates keep their listing order at the end
+		slices.SortStableFunc(generated, func(a, b youtube.Playlist) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+
+		out := &listGeneratedPlaylistsOutput{
+			Prefix:    s.playlistPrefix(),
+			Playlists: make([]playlistOutput, 0, len(generated)),
+		}
+		for _, pl := range generated {
+			out.Playlists = append(out.Playlists, newPlaylistOutput(pl))
+			out.TotalItems += pl.ItemCount
+		}
+		if input.OlderThanDays > 0 && len(generated) > 0 && !s.cfg.ReadOnly {
+			out.CleanupHint = fmt.Sprintf("%d generated playlists are over %d days old; delete the ones the user no longer wants with ym:delete-playlist (preview first, then confirm)", len(generated), input.OlderThanDays)
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:get-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-playlist",
+		Description: "Gets one playlist's metadata by ID or URL: title, description, privacy status and item count, without listing every playlist or reading its items. Returns found: false if it doesn't exist or isn't visible to this account. Quota cost: 1 unit.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getPlaylistInput) (*mcp.CallToolResult, *getPlaylistOutput, error) {
+		playlist, err := s.ytClient.GetPlaylist(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist", err)
+		}
+
+		out := &getPlaylistOutput{Found: playlist != nil}
+		if playlist != nil {
+			pl := newPlaylistOutput(*playlist)
+			out.Playlist = &pl
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:get-special-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-special-playlist",
+		Description: "Reads one of the user's special playlists: likes, uploads, favorites, watchLater or watchHistory. YouTube stopped exposing watch history and watch later through its API for most accounts; those then come back with available: false instead of an error. Set musicOnly to keep only songs (Music category), e.g. for a music-only liked list without running the taste analysis. Quota cost: 1 unit for the channel lookup (first call only) + 1 unit per 50 videos (likes are cached), and with musicOnly another 1 unit per 50 videos.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getSpecialPlaylistInput) (*mcp.CallToolResult, *getSpecialPlaylistOutput, error) {
+		out := &getSpecialPlaylistOutput{Kind: input.Kind, Available: true, Videos: []videoOutput{}}
+		videos, err := s.ytClient.GetSpecialPlaylist(ctx, input.Kind)
+		switch {
+		case errors.Is(err, youtube.ErrSpecialPlaylistUnavailable):
+			out.Available = false
+			out.Message = fmt.Sprintf("The YouTube API does not expose the %s playlist for this account.", input.Kind)
+		case err != nil:
+			return nil, nil, fmt.Errorf("failed to get %s playlist: %w", input.Kind, err)
+		case len(videos) == 0:
+			out.Message = fmt.Sprintf("The %s playlist is empty.", input.Kind)
+		case input.MusicOnly:
+			music, err := s.ytClient.FilterMusicVideos(ctx, videos)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to filter music videos: %w", err)
+			}
+			out.NonMusic = len(videos) - len(music)
+			videos = music
+		}
+		for _, v := range videos {
+			out.Videos = append(out.Videos, newVideoOutput(v))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:get-playlist-items
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-playlist-items",
+		Description: "Lists one page of videos in a playlist. Pass nextPageToken back as pageToken to fetch more. Set maxResults to -1 to get the whole playlist at once. Quota cost: 1 unit per page (of 50 items).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getPlaylistItemsInput) (*mcp.CallToolResult, *getPlaylistItemsOutput, error) {
+		var items []youtube.Video
+		var nextPageToken string
+		var err error
+		if input.MaxResults == allResults {
+			if input.PageToken != "" {
+				return nil, nil, fmt.Errorf("pageToken cannot be combined with maxResults -1, which returns the whole playlist")
+			}
+			items, err = s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+			if len(items) > largeResultWarning {
+				s.logger.Warn("returning a large listing", "tool", req.Params.Name, "results", len(items))
+			}
+		} else {
+			items, nextPageToken, err = s.ytClient.GetPlaylistItemsPage(ctx, input.PlaylistID, input.PageToken, input.MaxResults)
+		}
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+
+		out := &getPlaylistItemsOutput{
+			Items:         make([]videoOutput, 0, len(items)),
+			NextPageToken: nextPageToken,
+		}
+		if input.SkipUnavailable {
+			items, out.Skipped = youtube.SkipUnavailable(items)
+		}
+		for _, item := range items {
+			out.Items = append(out.Items, newVideoOutput(item))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+}
@@ -0,0 +1,366 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+)
+
+func TestPlaylistReadError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantIs    error
+		wantClass string
+		wantText  string
+	}{
+		{"private", fmt.Errorf("%w: forbidden", youtube.ErrPlaylistNotAccessible), youtube.ErrPlaylistNotAccessible, errorClassAuth, "is private"},
+		{"missing", youtube.ErrPlaylistNotFound, youtube.ErrPlaylistNotFound, errorClassValidation, "does not exist"},
+		{"other", errors.New("boom"), nil, errorClassOther, "failed to get playlist items: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := playlistReadError("PLsome", "get playlist items", tt.err)
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("error %v does not wrap %v", err, tt.wantIs)
+			}
+			if class := classifyToolError(err); class != tt.wantClass {
+				t.Errorf("class %q, want %q", class, tt.wantClass)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("error %q does not mention %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestGetPlaylistItemsReportsPrivatePlaylist(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusForbidden, "playlistItemsNotAccessible")
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	result := callTool(t, s, "ym:find-duplicates-in-playlist", map[string]any{"playlistId": "PLprivate"})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "PLprivate is private") {
+		t.Errorf("result %q, want the playlist reported as private", text)
+	}
+}
+
+func TestGetSpecialPlaylistMusicOnly(t *testing.T) {
+	var categoryLookups atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":             "UCme",
+			"contentDetails": map[string]any{"relatedPlaylists": map[string]any{"uploads": "UUme"}},
+		}}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "vlog0000001"))
+	})
+	api.HandleFunc("GET /youtube/v3/videoCategories", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{"id": "10", "snippet": map[string]any{"title": "Music"}}}})
+	})
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		categoryLookups.Add(1)
+		writeJSON(w, map[string]any{"items": []any{
+			map[string]any{"id": "song0000001", "snippet": map[string]any{"categoryId": "10"}},
+			map[string]any{"id": "vlog0000001", "snippet": map[string]any{"categoryId": "22"}},
+		}})
+	})
+
+	tests := []struct {
+		musicOnly    bool
+		wantVideos   []string
+		wantNonMusic int
+		wantLookups  int32
+	}{
+		{false, []string{"song0000001", "vlog0000001"}, 0, 0},
+		{true, []string{"song0000001"}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("musicOnly=%t", tt.musicOnly), func(t *testing.T) {
+			categoryLookups.Store(0)
+			s := newTestServer(t, testConfig(t, nil), api)
+
+			var out getSpecialPlaylistOutput
+			decodeOutput(t, callTool(t, s, "ym:get-special-playlist", map[string]any{"kind": "uploads", "musicOnly": tt.musicOnly}), &out)
+			var got []string
+			for _, v := range out.Videos {
+				got = append(got, v.ID)
+			}
+			if !slices.Equal(got, tt.wantVideos) || out.NonMusic != tt.wantNonMusic {
+				t.Errorf("videos %v, nonMusic %d; want %v, %d", got, out.NonMusic, tt.wantVideos, tt.wantNonMusic)
+			}
+			if n := categoryLookups.Load(); n != tt.wantLookups {
+				t.Errorf("%d category lookups, want %d", n, tt.wantLookups)
+			}
+		})
+	}
+}
+
+func TestGetPlaylistItemsSkipUnavailable(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		item := func(videoID, title string) map[string]any {
+			return map[string]any{"id": "item-" + videoID, "snippet": map[string]any{
+				"title":      title,
+				"resourceId": map[string]any{"kind": "youtube#video", "videoId": videoID},
+			}}
+		}
+		writeJSON(w, map[string]any{"items": []any{
+			item("available01", "One More Time"),
+			item("deleted0001", "Deleted video"),
+			item("private0001", "Private video"),
+			item("", "Gone"),
+			item("available02", "Genesis"),
+		}})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	ids := func(out getPlaylistItemsOutput) []string {
+		var ids []string
+		for _, item := range out.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	// By default the gaps stay visible
+	var out getPlaylistItemsOutput
+	decodeOutput(t, callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLmixed"}), &out)
+	if len(out.Items) != 5 || out.Skipped != 0 {
+		t.Errorf("default: items %q, skipped %d; want all 5 and none skipped", ids(out), out.Skipped)
+	}
+
+	out = getPlaylistItemsOutput{}
+	decodeOutput(t, callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLmixed", "skipUnavailable": true}), &out)
+	if got := ids(out); !slices.Equal(got, []string{"available01", "available02"}) || out.Skipped != 3 {
+		t.Errorf("skipUnavailable: items %q, skipped %d; want the 2 available and 3 skipped", got, out.Skipped)
+	}
+}
+
+// pagedAPI serves playlists.list and playlistItems.list as two pages, the
+// first of two entries and the second of one.
+func pagedAPI(pageTokens *[]string) http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		*pageTokens = append(*pageTokens, r.URL.Query().Get("pageToken"))
+		playlist := func(id string) map[string]any {
+			return map[string]any{"id": id, "snippet": map[string]any{"title": id}, "contentDetails": map[string]any{}}
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			writeJSON(w, map[string]any{"items": []any{playlist("PL1"), playlist("PL2")}, "nextPageToken": "page2"})
+			return
+		}
+		writeJSON(w, map[string]any{"items": []any{playlist("PL3")}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		*pageTokens = append(*pageTokens, r.URL.Query().Get("pageToken"))
+		if r.URL.Query().Get("pageToken") == "" {
+			page := playlistItemsResponse("aaaaaaaaaaa", "bbbbbbbbbbb")
+			page["nextPageToken"] = "page2"
+			writeJSON(w, page)
+			return
+		}
+		writeJSON(w, playlistItemsResponse("ccccccccccc"))
+	})
+	return api
+}
+
+func TestListPlaylistsAllResults(t *testing.T) {
+	var pageTokens []string
+	s := newTestServer(t, testConfig(t, nil), pagedAPI(&pageTokens))
+
+	var out listPlaylistsOutput
+	decodeOutput(t, callTool(t, s, "ym:list-playlists", map[string]any{"maxResults": -1}), &out)
+	var ids []string
+	for _, pl := range out.Playlists {
+		ids = append(ids, pl.ID)
+	}
+	if !slices.Equal(ids, []string{"PL1", "PL2", "PL3"}) || out.NextPageToken != "" {
+		t.Errorf("playlists %q, next page %q; want all three and no next page", ids, out.NextPageToken)
+	}
+	if !slices.Equal(pageTokens, []string{"", "page2"}) || out.QuotaUsed != 2 {
+		t.Errorf("fetched pages %q for %d quota, want both pages for 2", pageTokens, out.QuotaUsed)
+	}
+
+	result := callTool(t, s, "ym:list-playlists", map[string]any{"maxResults": -1, "pageToken": "page2"})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "pageToken cannot be combined") {
+		t.Errorf("maxResults -1 with a pageToken: %q, want it rejected", text)
+	}
+}
+
+func TestGetPlaylistItemsAllResults(t *testing.T) {
+	var pageTokens []string
+	s := newTestServer(t, testConfig(t, nil), pagedAPI(&pageTokens))
+
+	var out getPlaylistItemsOutput
+	decodeOutput(t, callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome", "maxResults": -1}), &out)
+	var ids []string
+	for _, item := range out.Items {
+		ids = append(ids, item.ID)
+	}
+	if !slices.Equal(ids, []string{"aaaaaaaaaaa", "bbbbbbbbbbb", "ccccccccccc"}) || out.NextPageToken != "" {
+		t.Errorf("items %q, next page %q; want all three and no next page", ids, out.NextPageToken)
+	}
+
+	// Without the sentinel only the first page is returned
+	out = getPlaylistItemsOutput{}
+	decodeOutput(t, callTool(t, s, "ym:get-playlist-items", map[string]any{"playlistId": "PLsome"}), &out)
+	if len(out.Items) != 2 || out.NextPageToken != "page2" {
+		t.Errorf("first page: %d items, next page %q; want 2 and page2", len(out.Items), out.NextPageToken)
+	}
+}
+
+func TestFindPlaylistExactAndFuzzy(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for i, title := range []string{
+			"Chill Evening Mix",
+			defaultPlaylistPrefix + " Chill Mix",
+			"Chill Mix Vol. 2",
+			"Workout",
+			"My Chill Mix",
+			"chill mix",
+		} {
+			items = append(items, map[string]any{"id": fmt.Sprintf("PL%d", i), "snippet": map[string]any{"title": title}, "contentDetails": map[string]any{}})
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	find := func(args map[string]any) []string {
+		t.Helper()
+		var out findPlaylistOutput
+		decodeOutput(t, callTool(t, s, "ym:find-playlist", args), &out)
+		var got []string
+		for _, m := range out.Matches {
+			got = append(got, m.Match+": "+m.Title)
+		}
+		return got
+	}
+
+	want := []string{
+		"exact: " + defaultPlaylistPrefix + " Chill Mix",
+		"exact: chill mix",
+		"prefix: Chill Mix Vol. 2",
+		"contains: My Chill Mix",
+		"fuzzy: Chill Evening Mix",
+	}
+	if got := find(map[string]any{"name": "Chill Mix"}); !slices.Equal(got, want) {
+		t.Errorf("fuzzy find = %q, want %q", got, want)
+	}
+	if got := find(map[string]any{"name": "chill MIX", "exact": true}); !slices.Equal(got, want[:2]) {
+		t.Errorf("exact find = %q, want %q", got, want[:2])
+	}
+	if got := find(map[string]any{"name": "Jazz"}); len(got) != 0 {
+		t.Errorf("find with no match = %q, want none", got)
+	}
+}
+
+func TestGetPlaylistFoundAndNotFound(t *testing.T) {
+	var requested []string
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("id"))
+		if r.URL.Query().Get("id") != "PLfound" {
+			writeJSON(w, map[string]any{"items": []any{}})
+			return
+		}
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":             "PLfound",
+			"snippet":        map[string]any{"title": "Road trip", "description": "Songs for the car"},
+			"status":         map[string]any{"privacyStatus": "unlisted"},
+			"contentDetails": map[string]any{"itemCount": 12},
+		}}})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	var out getPlaylistOutput
+	decodeOutput(t, callTool(t, s, "ym:get-playlist", map[string]any{"playlistId": "https://music.youtube.com/playlist?list=PLfound"}), &out)
+	want := playlistOutput{ID: "PLfound", Title: "Road trip", Description: "Songs for the car", ItemCount: 12, PrivacyStatus: "unlisted"}
+	if !out.Found || out.Playlist == nil || *out.Playlist != want {
+		t.Errorf("found playlist = %+v (found %v), want %+v", out.Playlist, out.Found, want)
+	}
+
+	out = getPlaylistOutput{}
+	decodeOutput(t, callTool(t, s, "ym:get-playlist", map[string]any{"playlistId": "PLmissing"}), &out)
+	if out.Found || out.Playlist != nil {
+		t.Errorf("missing playlist = %+v (found %v), want found: false", out.Playlist, out.Found)
+	}
+	if !slices.Equal(requested, []string{"PLfound", "PLmissing"}) {
+		t.Errorf("looked up %q, want one lookup by ID each", requested)
+	}
+}
+
+func TestListGeneratedPlaylistsFiltersAndSorts(t *testing.T) {
+	daysAgo := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	}
+	type stub struct {
+		id, title, publishedAt string
+		items                  int
+	}
+	stubs := []stub{
+		{"PLold", defaultPlaylistPrefix + " Old mix", daysAgo(90), 10},
+		{"PLmine", "Road trip", daysAgo(1), 7},
+		{"PLnew", defaultPlaylistPrefix + " New mix", daysAgo(2), 3},
+		{"PLundated", defaultPlaylistPrefix + " Undated", "", 5},
+		{"PLmid", defaultPlaylistPrefix + " Mid mix", daysAgo(30), 4},
+		{"PLinside", "Not " + defaultPlaylistPrefix + " at the start", daysAgo(3), 2},
+	}
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, pl := range stubs {
+			snippet := map[string]any{"title": pl.title}
+			if pl.publishedAt != "" {
+				snippet["publishedAt"] = pl.publishedAt
+			}
+			items = append(items, map[string]any{"id": pl.id, "snippet": snippet, "contentDetails": map[string]any{"itemCount": pl.items}})
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	tests := []struct {
+		name          string
+		olderThanDays int
+		wantIDs       []string
+		wantItems     int64
+	}{
+		// Newest first, the undated one last
+		{"all", 0, []string{"PLnew", "PLmid", "PLold", "PLundated"}, 22},
+		// Only those created before the cutoff; unknown ages are never stale
+		{"older than 7 days", 7, []string{"PLmid", "PLold"}, 14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out listGeneratedPlaylistsOutput
+			decodeOutput(t, callTool(t, s, "ym:list-generated-playlists", map[string]any{"olderThanDays": tt.olderThanDays}), &out)
+			var ids []string
+			for _, pl := range out.Playlists {
+				ids = append(ids, pl.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || out.TotalItems != tt.wantItems {
+				t.Errorf("playlists %q with %d items, want %q with %d", ids, out.TotalItems, tt.wantIDs, tt.wantItems)
+			}
+			if out.Prefix != defaultPlaylistPrefix {
+				t.Errorf("prefix = %q, want %q", out.Prefix, defaultPlaylistPrefix)
+			}
+			if hinted := out.CleanupHint != ""; hinted != (tt.olderThanDays > 0) {
+				t.Errorf("cleanup hint %q, want one only for stale playlists", out.CleanupHint)
+			}
+		})
+	}
+}
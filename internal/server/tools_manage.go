@@ -0,0 +1,1209 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Input and output types for playlist management tools
+
+type findDuplicatesInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist to check for duplicates"`
+}
+
+type findDuplicatesOutput struct {
+	TotalItems     int                    `json:"totalItems" jsonschema:"Number of items in the playlist"`
+	Duplicates     []duplicateVideoOutput `json:"duplicates" jsonschema:"Videos that appear more than once"`
+	RemovableIDs   []string               `json:"removableItemIds" jsonschema:"Playlist item IDs of all extra copies (first occurrences kept)"`
+	RemovableCount int                    `json:"removableCount" jsonschema:"Number of extra copies that can be removed"`
+	QuotaUsed      int                    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type duplicateVideoOutput struct {
+	VideoID       string   `json:"videoId" jsonschema:"Duplicated video ID"`
+	Title         string   `json:"title" jsonschema:"Video title"`
+	Occurrences   int      `json:"occurrences" jsonschema:"Number of times the video appears"`
+	Positions     []int64  `json:"positions" jsonschema:"Zero-based positions of every occurrence"`
+	KeepItemID    string   `json:"keepItemId" jsonschema:"Playlist item ID of the first occurrence, which is kept"`
+	RemoveItemIDs []string `json:"removeItemIds" jsonschema:"Playlist item IDs of the later occurrences to remove"`
+}
+
+type diffPlaylistsInput struct {
+	PlaylistA  string `json:"playlistA" jsonschema:"ID or URL of the first playlist"`
+	PlaylistB  string `json:"playlistB" jsonschema:"ID or URL of the second playlist"`
+	MaxResults int    `json:"maxResults,omitempty" jsonschema:"Most videos listed per set (default 100), or -1 to list every one; the counts always cover all of them"`
+}
+
+type diffPlaylistsOutput struct {
+	OnlyInA      []diffVideoOutput `json:"onlyInA" jsonschema:"Videos in playlist A but not in B, in A's order"`
+	OnlyInB      []diffVideoOutput `json:"onlyInB" jsonschema:"Videos in playlist B but not in A, in B's order"`
+	Common       []diffVideoOutput `json:"common" jsonschema:"Videos in both playlists, in A's order"`
+	OnlyInACount int               `json:"onlyInACount" jsonschema:"Number of videos only in A"`
+	OnlyInBCount int               `json:"onlyInBCount" jsonschema:"Number of videos only in B"`
+	CommonCount  int               `json:"commonCount" jsonschema:"Number of videos in both"`
+	Truncated    bool              `json:"truncated,omitempty" jsonschema:"Whether a set lists fewer videos than its count because of maxResults"`
+	UnavailableA int               `json:"unavailableA,omitempty" jsonschema:"Deleted or private items in A, left out of the comparison"`
+	UnavailableB int               `json:"unavailableB,omitempty" jsonschema:"Deleted or private items in B, left out of the comparison"`
+	QuotaUsed    int               `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type diffVideoOutput struct {
+	VideoID      string `json:"videoId" jsonschema:"Video ID"`
+	Title        string `json:"title" jsonschema:"Video title"`
+	ChannelTitle string `json:"channelTitle,omitempty" jsonschema:"Channel (artist) that uploaded the video"`
+}
+
+// defaultDiffResults is how many videos diff-playlists lists per set by default.
+const defaultDiffResults = 100
+
+type playlistStatsInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist to summarize"`
+	TopArtists int    `json:"topArtists,omitempty" jsonschema:"How many of the most-represented artists to list (1-50, default 5)"`
+}
+
+type playlistStatsOutput struct {
+	TotalItems             int                 `json:"totalItems" jsonschema:"Number of items in the playlist"`
+	Unavailable            int                 `json:"unavailable" jsonschema:"Deleted or private items, left out of every statistic"`
+	UnknownDuration        int                 `json:"unknownDuration" jsonschema:"Available items without a known duration (e.g. livestreams), left out of the durations"`
+	TotalDurationSeconds   int64               `json:"totalDurationSeconds" jsonschema:"Combined length of the items with a known duration"`
+	TotalDurationHuman     string              `json:"totalDurationHuman" jsonschema:"Combined length in human-readable form, e.g. 1:02:03"`
+	AverageDurationSeconds int64               `json:"averageDurationSeconds" jsonschema:"Average length of the items with a known duration"`
+	AverageDurationHuman   string              `json:"averageDurationHuman" jsonschema:"Average length in human-readable form, e.g. 3:45"`
+	UniqueArtists          int                 `json:"uniqueArtists" jsonschema:"Number of distinct artists (channels) among the available items"`
+	TopArtists             []artistCountOutput `json:"topArtists" jsonschema:"Most-represented artists, most songs first"`
+	QuotaUsed              int                 `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type artistCountOutput struct {
+	Artist string `json:"artist" jsonschema:"Artist or channel name"`
+	Songs  int    `json:"songs" jsonschema:"Number of playlist items by this artist"`
+}
+
+// defaultStatsArtists is how many top artists playlist-stats lists by default.
+const defaultStatsArtists = 5
+
+type copyPlaylistInput struct {
+	SourcePlaylistID string `json:"sourcePlaylistId" jsonschema:"ID or URL of the playlist to copy"`
+	Title            string `json:"title" jsonschema:"Title for the new playlist (prefixed with the server's playlist prefix, [YM-MCP] by default)"`
+	PrivacyStatus    string `json:"privacyStatus,omitempty" jsonschema:"Playlist privacy: public/private/unlisted (default private, or the server's DEFAULT_PLAYLIST_PRIVACY)"`
+}
+
+type copyPlaylistOutput struct {
+	PlaylistID  string `json:"playlistId" jsonschema:"ID of the new playlist"`
+	URL         string `json:"url" jsonschema:"YouTube Music URL of the new playlist"`
+	SourceItems int    `json:"sourceItems" jsonschema:"Number of items in the source playlist"`
+	Copied      int    `json:"copied" jsonschema:"Number of items copied into the new playlist"`
+	Skipped     int    `json:"skipped" jsonschema:"Number of deleted or private source items that could not be copied"`
+	QuotaUsed   int    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type savePlaylistInput struct {
+	SourcePlaylistID string `json:"sourcePlaylistId" jsonschema:"ID or URL of the playlist to save, e.g. someone else's public playlist"`
+	Title            string `json:"title,omitempty" jsonschema:"Title for the saved copy (prefixed with the server's playlist prefix, [YM-MCP] by default). Defaults to the source playlist's title."`
+	PrivacyStatus    string `json:"privacyStatus,omitempty" jsonschema:"Playlist privacy: public/private/unlisted (default private, or the server's DEFAULT_PLAYLIST_PRIVACY)"`
+}
+
+type exportPlaylistInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist to export"`
+	Format     string `json:"format,omitempty" jsonschema:"Export format: json (default) or m3u"`
+}
+
+type exportPlaylistOutput struct {
+	Format    string `json:"format" jsonschema:"Format of content: json or m3u"`
+	Content   string `json:"content" jsonschema:"The exported playlist, ready to save as a .json or .m3u8 file"`
+	Exported  int    `json:"exported" jsonschema:"Number of songs exported"`
+	Skipped   int    `json:"skipped" jsonschema:"Number of deleted or private items left out"`
+	QuotaUsed int    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+// exportEntry is one song of an exported playlist.
+type exportEntry struct {
+	VideoID string `json:"videoId"`
+	Title   string `json:"title"`
+	Channel string `json:"channel"`
+	URL     string `json:"url"`
+}
+
+type addToPlaylistInput struct {
+	PlaylistID string   `json:"playlistId" jsonschema:"ID or URL of the playlist to add the songs to"`
+	VideoIDs   []string `json:"videoIds" jsonschema:"Songs to add, in order: video IDs or YouTube/YouTube Music URLs"`
+	Position   *int64   `json:"position,omitempty" jsonschema:"Zero-based index to insert the songs at, in order; the items from there on shift down. Omit to append to the end"`
+}
+
+type addToPlaylistOutput struct {
+	Added     int `json:"added" jsonschema:"Number of songs added"`
+	Skipped   int `json:"skipped" jsonschema:"Songs skipped because they were already in the playlist or listed twice"`
+	QuotaUsed int `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type importPlaylistInput struct {
+	Title         string   `json:"title" jsonschema:"Title for the new playlist (prefixed with the server's playlist prefix, [YM-MCP] by default)"`
+	PrivacyStatus string   `json:"privacyStatus,omitempty" jsonschema:"Playlist privacy: public/private/unlisted (default private, or the server's DEFAULT_PLAYLIST_PRIVACY)"`
+	Videos        []string `json:"videos" jsonschema:"Songs to import, in order: YouTube or YouTube Music URLs (watch?v=, youtu.be/, shorts) or bare video IDs"`
+}
+
+type importPlaylistOutput struct {
+	PlaylistID string   `json:"playlistId" jsonschema:"ID of the new playlist"`
+	URL        string   `json:"url" jsonschema:"YouTube Music URL of the new playlist"`
+	Added      int      `json:"added" jsonschema:"Number of songs added to the playlist"`
+	Invalid    []string `json:"invalid" jsonschema:"Entries that are not a video URL or ID and were skipped"`
+	QuotaUsed  int      `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type shufflePlaylistInput struct {
+	PlaylistID string  `json:"playlistId" jsonschema:"ID or URL of the playlist to shuffle"`
+	Seed       *uint64 `json:"seed,omitempty" jsonschema:"Random seed; the same seed shuffles the same playlist the same way. Random when omitted"`
+	Confirm    bool    `json:"confirm,omitempty" jsonschema:"Set to true to shuffle. Without it only the number of moves and the estimated cost are returned"`
+}
+
+type shufflePlaylistOutput struct {
+	Confirmed bool   `json:"confirmed" jsonschema:"Whether the playlist was shuffled"`
+	Message   string `json:"message" jsonschema:"What happened, or what would happen with confirm: true"`
+	Items     int    `json:"items" jsonschema:"Number of items in the playlist"`
+	Moves     int    `json:"moves" jsonschema:"Number of items moved (or that would be moved) to reach the new order"`
+	Seed      uint64 `json:"seed" jsonschema:"Seed of this shuffle; pass it back with confirm: true to apply the previewed order"`
+	QuotaUsed int    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type setPlaylistThumbnailInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist"`
+	VideoID    string `json:"videoId" jsonschema:"ID or URL of the video, already in the playlist, whose thumbnail to show"`
+}
+
+type setPlaylistThumbnailOutput struct {
+	Moved            bool   `json:"moved" jsonschema:"Whether the video was moved to the top; false if it was already first"`
+	PreviousPosition int64  `json:"previousPosition" jsonschema:"Zero-based position the video had before"`
+	ThumbnailURL     string `json:"thumbnailUrl,omitempty" jsonschema:"URL of the thumbnail the playlist now shows"`
+	QuotaUsed        int    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+// rateWorkers is how many ratings ym:rate-playlist keeps in flight at once;
+// more mostly trips YouTube's per-user rate limit.
+const rateWorkers = 4
+
+// playlistMove moves a playlist entry to a zero-based position.
+type playlistMove struct {
+	item     youtube.Video
+	position int64
+}
+
+type ratePlaylistInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist whose songs to rate"`
+	Rating     string `json:"rating,omitempty" jsonschema:"Rating to give every song: like (default), dislike, or none to clear a rating"`
+	Confirm    bool   `json:"confirm,omitempty" jsonschema:"Must be true to actually rate. When false (default) nothing changes and the number of songs and quota cost are returned"`
+}
+
+type ratePlaylistOutput struct {
+	Confirmed bool     `json:"confirmed" jsonschema:"Whether the songs were rated"`
+	Message   string   `json:"message" jsonschema:"What happened, or what would happen with confirm: true"`
+	Videos    int      `json:"videos" jsonschema:"Number of unique available songs in the playlist"`
+	Rated     int      `json:"rated" jsonschema:"Number of songs rated"`
+	Failed    []string `json:"failed" jsonschema:"Songs that could not be rated, with the reason"`
+	QuotaUsed int      `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type moveVideosInput struct {
+	SourcePlaylistID string   `json:"sourcePlaylistId" jsonschema:"ID or URL of the playlist to move songs out of"`
+	TargetPlaylistID string   `json:"targetPlaylistId" jsonschema:"ID or URL of the playlist to move songs into"`
+	VideoIDs         []string `json:"videoIds" jsonschema:"Video IDs or URLs of the songs to move; every occurrence in the source is removed once the song is added"`
+	Confirm          bool     `json:"confirm,omitempty" jsonschema:"Must be true to actually move. When false (default) nothing changes and the songs that would be moved are returned"`
+}
+
+type moveVideosOutput struct {
+	Confirmed bool     `json:"confirmed" jsonschema:"Whether the move was carried out"`
+	Message   string   `json:"message" jsonschema:"What happened, or what would happen with confirm: true"`
+	ToMove    []string `json:"toMove" jsonschema:"Video IDs that are in the source and not yet in the target"`
+	Moved     int      `json:"moved" jsonschema:"Number of songs added to the target and removed from the source"`
+	Skipped   []string `json:"skipped" jsonschema:"Songs left alone, with the reason: not in the source, or already in the target"`
+	Failed    []string `json:"failed" jsonschema:"Songs that could not be added to the target, with the reason; they stay in the source"`
+	QuotaUsed int      `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type removeFromPlaylistInput struct {
+	PlaylistID      string   `json:"playlistId" jsonschema:"ID or URL of the playlist to remove songs from"`
+	PlaylistItemIDs []string `json:"playlistItemIds,omitempty" jsonschema:"Playlist item IDs to remove (from ym:get-playlist-items or ym:find-duplicates-in-playlist)"`
+	VideoIDs        []string `json:"videoIds,omitempty" jsonschema:"Video IDs or URLs to remove; every occurrence in the playlist is removed"`
+	Positions       []int64  `json:"positions,omitempty" jsonschema:"Zero-based positions of entries to remove, e.g. to drop one copy of a duplicate. Positions shift whenever the playlist changes; prefer playlistItemIds from the preview for the confirming call"`
+	Confirm         bool     `json:"confirm,omitempty" jsonschema:"Must be true to actually remove. When false (default) nothing changes and the songs that would be removed are returned"`
+}
+
+type removeFromPlaylistOutput struct {
+	Confirmed bool          `json:"confirmed" jsonschema:"Whether the removal was carried out"`
+	Message   string        `json:"message" jsonschema:"What happened, or what would happen with confirm: true"`
+	Items     []videoOutput `json:"items" jsonschema:"Playlist entries matched for removal"`
+	Removed   int           `json:"removed" jsonschema:"Number of entries removed"`
+	QuotaUsed int           `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type removeDuplicatesInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist to remove duplicates from"`
+	Confirm    bool   `json:"confirm,omitempty" jsonschema:"Must be true to actually remove. When false (default) nothing changes and the duplicates that would be removed are returned"`
+}
+
+type removeDuplicatesOutput struct {
+	Confirmed  bool                   `json:"confirmed" jsonschema:"Whether the removal was carried out"`
+	Message    string                 `json:"message" jsonschema:"What happened, or what would happen with confirm: true"`
+	Duplicates []duplicateVideoOutput `json:"duplicates" jsonschema:"Videos that appear more than once; only their later occurrences are removed"`
+	Removed    int                    `json:"removed" jsonschema:"Number of extra copies removed"`
+	QuotaUsed  int                    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type deletePlaylistInput struct {
+	PlaylistID string `json:"playlistId" jsonschema:"ID or URL of the playlist to delete"`
+	Confirm    bool   `json:"confirm,omitempty" jsonschema:"Must be true to actually delete. When false (default) nothing changes and the playlist that would be deleted is returned"`
+}
+
+type deletePlaylistOutput struct {
+	Confirmed bool            `json:"confirmed" jsonschema:"Whether the playlist was deleted"`
+	Message   string          `json:"message" jsonschema:"What happened, or what would happen with confirm: true"`
+	Playlist  *playlistOutput `json:"playlist,omitempty" jsonschema:"The playlist targeted for deletion"`
+	QuotaUsed int             `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+// confirmHint tells agents how to complete the two-phase pattern of destructive
+// tools: the first call previews, a second call with confirm: true acts.
+const confirmHint = "Nothing was changed. Show this to the user and call again with confirm: true to proceed."
+
+// checkQuotaBudget refuses work estimated to cost more than MaxQuotaPerCall or
+// than the Google Cloud project's quota remaining today, which all users share.
+// what describes the work, e.g. "copying 3 songs".
+func (s *Server) checkQuotaBudget(ctx context.Context, what string, estimate int) error {
+	if budget := s.cfg.MaxQuotaPerCall; budget > 0 && estimate > budget {
+		return fmt.Errorf("%s would cost ~%d quota units, over the per-call budget of %d (MAX_QUOTA_PER_CALL)", what, estimate, budget)
+	}
+	if used, limit := s.ytClient.TotalQuotaUsage(); estimate > limit-used {
+		return fmt.Errorf("%s would cost ~%d quota units, but only ~%d remain today; try again after the quota resets at midnight Pacific Time", what, estimate, max(limit-used, 0))
+	}
+	return nil
+}
+
+// rateVideos rates videoIDs with up to rateWorkers calls in flight, and
+// returns how many were rated and why the others failed. It starts no more
+// ratings after a quota or rate-limit error, which every later call would hit
+// too, or after ctx ends, and returns that error.
+func (s *Server) rateVideos(ctx context.Context, videoIDs []string, rating string) (rated int, failed []string, err error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		stop error
+	)
+	sem := make(chan struct{}, rateWorkers)
+	for _, videoID := range videoIDs {
+		sem <- struct{}{}
+		mu.Lock()
+		if stop == nil {
+			stop = ctx.Err()
+		}
+		stopped := stop != nil
+		mu.Unlock()
+		if stopped {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := s.ytClient.RateVideo(ctx, videoID, rating)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				rated++
+			case stopsWrites(err):
+				if stop == nil {
+					stop = err
+				}
+			default:
+				failed = append(failed, fmt.Sprintf("%s: %v", videoID, err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(failed)
+	return rated, failed, stop
+}
+
+// copyPlaylist copies the available items of a source playlist into a new
+// playlist. It refuses, before creating anything, copies estimated to cost more
+// than MaxQuotaPerCall or than the quota remaining today.
+func (s *Server) copyPlaylist(ctx context.Context, sourceID, title, description, privacyStatus string) (*copyPlaylistOutput, error) {
+	items, err := s.ytClient.GetPlaylistItems(ctx, sourceID)
+	if err != nil {
+		return nil, playlistReadError(sourceID, "get source playlist items", err)
+	}
+
+	var videoIDs []string
+	for _, item := range items {
+		if item.Unavailable() {
+			continue
+		}
+		videoIDs = append(videoIDs, item.ID)
+	}
+	if len(videoIDs) == 0 {
+		return nil, fmt.Errorf("source playlist has no copyable items")
+	}
+
+	costs := quotaCosts(s.cfg)
+	estimate := costs.Write + len(videoIDs)*costs.Write
+	if err := s.checkQuotaBudget(ctx, fmt.Sprintf("copying %d songs", len(videoIDs)), estimate); err != nil {
+		return nil, err
+	}
+
+	playlist, err := s.ytClient.CreatePlaylist(ctx, title, description, privacyStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create playlist: %w", err)
+	}
+
+	copied, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy videos into playlist %s (copied %d of %d): %w", playlist.ID, copied, len(videoIDs), err)
+	}
+
+	return &copyPlaylistOutput{
+		PlaylistID:  playlist.ID,
+		URL:         playlistURL(playlist.ID),
+		SourceItems: len(items),
+		Copied:      copied,
+		Skipped:     len(items) - len(videoIDs),
+		QuotaUsed:   quotaSpent(ctx),
+	}, nil
+}
+
+// diffVideos splits the available videos of a by whether b has them too, each
+// video once, in a's order, and counts a's deleted or private items.
+func diffVideos(a, b []youtube.Video) (only, common []youtube.Video, unavailable int) {
+	inB := make(map[string]struct{}, len(b))
+	for _, v := range b {
+		if !v.Unavailable() {
+			inB[v.ID] = struct{}{}
+		}
+	}
+
+	seen := make(map[string]struct{}, len(a))
+	for _, v := range a {
+		if v.Unavailable() {
+			unavailable++
+			continue
+		}
+		if _, dup := seen[v.ID]; dup {
+			continue
+		}
+		seen[v.ID] = struct{}{}
+		if _, ok := inB[v.ID]; ok {
+			common = append(common, v)
+		} else {
+			only = append(only, v)
+		}
+	}
+	return only, common, unavailable
+}
+
+// registerManageTools registers the playlist management MCP tools
+func (s *Server) registerManageTools() {
+	costs := quotaCosts(s.cfg)
+
+	// Tool: ym:find-duplicates-in-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:find-duplicates-in-playlist",
+		Description: "Finds videos that appear more than once in a playlist and lists the playlist item IDs of the extra copies (keeping the first occurrence) so they can be removed. Read-only. Quota cost: 1 unit per 50 items.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input findDuplicatesInput) (*mcp.CallToolResult, *findDuplicatesOutput, error) {
+		items, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+
+		out := &findDuplicatesOutput{
+			TotalItems:   len(items),
+			Duplicates:   findDuplicateVideos(items),
+			RemovableIDs: []string{},
+		}
+		for _, d := range out.Duplicates {
+			out.RemovableIDs = append(out.RemovableIDs, d.RemoveItemIDs...)
+		}
+		out.RemovableCount = len(out.RemovableIDs)
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:diff-playlists
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:diff-playlists",
+		Description: fmt.Sprintf("Compares two playlists by video ID: which videos are only in A, only in B, and in both, with titles. Deleted and private items are counted but left out. A building block for syncing or merging playlists. Read-only. Quota cost: %d per 50 items of each playlist.", costs.List),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input diffPlaylistsInput) (*mcp.CallToolResult, *diffPlaylistsOutput, error) {
+		maxResults := cmp.Or(input.MaxResults, defaultDiffResults)
+		if maxResults < 1 && maxResults != allResults {
+			return nil, nil, fmt.Errorf("maxResults must be positive or -1 for all, got %d", maxResults)
+		}
+
+		itemsA, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistA)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistA, "get playlist A items", err)
+		}
+		itemsB, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistB)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistB, "get playlist B items", err)
+		}
+
+		onlyInA, common, unavailableA := diffVideos(itemsA, itemsB)
+		onlyInB, _, unavailableB := diffVideos(itemsB, itemsA)
+		out := &diffPlaylistsOutput{
+			OnlyInACount: len(onlyInA),
+			OnlyInBCount: len(onlyInB),
+			CommonCount:  len(common),
+			UnavailableA: unavailableA,
+			UnavailableB: unavailableB,
+		}
+		list := func(videos []youtube.Video) []diffVideoOutput {
+			if maxResults != allResults && len(videos) > maxResults {
+				videos = videos[:maxResults]
+				out.Truncated = true
+			}
+			listed := make([]diffVideoOutput, 0, len(videos))
+			for _, v := range videos {
+				listed = append(listed, diffVideoOutput{VideoID: v.ID, Title: v.Title, ChannelTitle: v.ChannelTitle})
+			}
+			return listed
+		}
+		out.OnlyInA, out.OnlyInB, out.Common = list(onlyInA), list(onlyInB), list(common)
+		if listed := len(out.OnlyInA) + len(out.OnlyInB) + len(out.Common); listed > largeResultWarning {
+			s.logger.Warn("returning a large listing", "tool", req.Params.Name, "results", listed)
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:playlist-stats
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:playlist-stats",
+		Description: fmt.Sprintf("Summarizes a playlist: total and average song length, number of unique artists, and the most-represented artists. Deleted and private items are left out. Read-only. Quota cost: %d per 50 items to read the playlist plus %d per 50 items to look up durations.", costs.List, costs.List),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input playlistStatsInput) (*mcp.CallToolResult, *playlistStatsOutput, error) {
+		topN := cmp.Or(input.TopArtists, defaultStatsArtists)
+		if topN < 1 || topN > 50 {
+			return nil, nil, fmt.Errorf("topArtists must be between 1 and 50, got %d", topN)
+		}
+
+		items, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+
+		out := &playlistStatsOutput{TotalItems: len(items), TopArtists: []artistCountOutput{}}
+		var videoIDs []string
+		artistSongs := make(map[string]int)
+		for _, item := range items {
+			if item.Unavailable() {
+				out.Unavailable++
+				continue
+			}
+			videoIDs = append(videoIDs, item.ID)
+			if item.ChannelTitle != "" {
+				artistSongs[item.ChannelTitle]++
+			}
+		}
+
+		var details map[string]youtube.VideoDetail
+		if len(videoIDs) > 0 {
+			details, err = s.videoDurations(ctx, videoIDs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get video durations: %w", err)
+			}
+		}
+
+		// Count every entry, so a song added twice counts twice
+		known := 0
+		for _, id := range videoIDs {
+			seconds := details[id].DurationSeconds
+			if seconds <= 0 {
+				out.UnknownDuration++
+				continue
+			}
+			out.TotalDurationSeconds += seconds
+			known++
+		}
+		if known > 0 {
+			out.AverageDurationSeconds = out.TotalDurationSeconds / int64(known)
+		}
+		out.TotalDurationHuman = youtube.FormatDuration(time.Duration(out.TotalDurationSeconds) * time.Second)
+		out.AverageDurationHuman = youtube.FormatDuration(time.Duration(out.AverageDurationSeconds) * time.Second)
+
+		out.UniqueArtists = len(artistSongs)
+		for artist, songs := range artistSongs {
+			out.TopArtists = append(out.TopArtists, artistCountOutput{Artist: artist, Songs: songs})
+		}
+		sortRanked(out.TopArtists, func(a artistCountOutput) (float64, string) {
+			return float64(a.Songs), a.Artist
+		})
+		out.TopArtists = out.TopArtists[:min(topN, len(out.TopArtists))]
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:export-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:export-playlist",
+		Description: "Exports a playlist for backup or moving it off YouTube, as JSON (videoId, title, channel, url per song) or as an M3U playlist of YouTube Music links. Deleted and private items are left out. Read-only. Quota cost: 1 unit per 50 items.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input exportPlaylistInput) (*mcp.CallToolResult, *exportPlaylistOutput, error) {
+		format := cmp.Or(strings.ToLower(input.Format), "json")
+		if format != "json" && format != "m3u" {
+			return nil, nil, fmt.Errorf("invalid format %q: must be json or m3u", input.Format)
+		}
+
+		items, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+
+		entries := []exportEntry{}
+		for _, item := range items {
+			if item.Unavailable() {
+				continue
+			}
+			entries = append(entries, exportEntry{
+				VideoID: item.ID,
+				Title:   item.Title,
+				Channel: item.ChannelTitle,
+				URL:     songURL(item.ID),
+			})
+		}
+
+		out := &exportPlaylistOutput{
+			Format:   format,
+			Exported: len(entries),
+			Skipped:  len(items) - len(entries),
+		}
+		if format == "m3u" {
+			out.Content = formatM3U(entries)
+		} else {
+			data, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to encode playlist: %w", err)
+			}
+			out.Content = string(data)
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+	// Every other tool changes playlists or ratings, so skip them in read-only mode
+	if !s.cfg.ReadOnly {
+		s.registerManageWriteTools()
+	}
+}
+
+// registerManageWriteTools registers the playlist management MCP tools that need write access
+func (s *Server) registerManageWriteTools() {
+	costs := quotaCosts(s.cfg)
+
+	// Tool: ym:copy-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:copy-playlist",
+		Description: fmt.Sprintf("Copies a playlist into a new one, preserving order. Useful for snapshotting or forking a playlist before editing it. Deleted and private items are skipped. Expensive for large playlists: quota cost is %d per 50 source items + %d (playlist creation) + %d per song copied, e.g. ~%d units for 100 songs.", costs.List, costs.Write, costs.Write, costs.Write*101),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input copyPlaylistInput) (*mcp.CallToolResult, *copyPlaylistOutput, error) {
+		if strings.TrimSpace(input.Title) == "" {
+			return nil, nil, fmt.Errorf("title cannot be empty")
+		}
+
+		description := fmt.Sprintf("Copy of playlist %s", input.SourcePlaylistID)
+		out, err := s.copyPlaylist(ctx, input.SourcePlaylistID, s.prefixedTitle(input.Title), description, input.PrivacyStatus)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, out, nil
+	})
+
+	// Tool: ym:save-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:save-playlist",
+		Description: fmt.Sprintf("Saves a playlist, typically someone else's public one, to the user's library. The YouTube API cannot add another user's playlist to the library, so this creates an owned COPY named after the source: it does not follow later changes to the source. Deleted and private items are skipped. Refused when the copy would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Quota cost: %d to look up + %d per 50 source items + %d (playlist creation) + %d per song copied, e.g. ~%d units for 100 songs.", costs.List, costs.List, costs.Write, costs.Write, costs.Write*101),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input savePlaylistInput) (*mcp.CallToolResult, *copyPlaylistOutput, error) {
+		source, err := s.ytClient.GetPlaylist(ctx, input.SourcePlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.SourcePlaylistID, "get source playlist", err)
+		}
+		if source == nil {
+			return nil, nil, playlistReadError(input.SourcePlaylistID, "", youtube.ErrPlaylistNotFound)
+		}
+
+		title := s.prefixedTitle(cmp.Or(strings.TrimSpace(input.Title), source.Title))
+		description := fmt.Sprintf("Saved copy of %s (%s)", source.Title, playlistURL(source.ID))
+		out, err := s.copyPlaylist(ctx, source.ID, title, description, input.PrivacyStatus)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, out, nil
+	})
+
+	// Tool: ym:add-to-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:add-to-playlist",
+		Description: fmt.Sprintf("Adds songs to an existing playlist, appending them or, with position, inserting them in order starting at that zero-based index (the items from there on shift down), so an ordered playlist needs no reorder pass. Songs already in the playlist are skipped. Refused when the adds would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Quota cost: %d per song added.", costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input addToPlaylistInput) (*mcp.CallToolResult, *addToPlaylistOutput, error) {
+		if err := s.checkQuotaBudget(ctx, fmt.Sprintf("adding %d songs", len(input.VideoIDs)), len(input.VideoIDs)*costs.Write); err != nil {
+			return nil, nil, err
+		}
+		added, err := s.ytClient.AddVideosToPlaylistAt(ctx, input.PlaylistID, input.VideoIDs, input.Position)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add videos to playlist (added %d of %d): %w", added, len(input.VideoIDs), err)
+		}
+
+		return nil, &addToPlaylistOutput{
+			Added:     added,
+			Skipped:   len(input.VideoIDs) - added,
+			QuotaUsed: quotaSpent(ctx),
+		}, nil
+	})
+
+	// Tool: ym:import-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:import-playlist",
+		Description: fmt.Sprintf("Creates a playlist from a list of YouTube or YouTube Music URLs or video IDs, e.g. one exported with ym:export-playlist or shared by a friend. Entries that are not a video URL or ID are skipped and reported. Refused when the import would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Quota cost: %d (playlist creation) + %d per song added, e.g. ~%d units for 100 songs.", costs.Write, costs.Write, costs.Write*101),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input importPlaylistInput) (*mcp.CallToolResult, *importPlaylistOutput, error) {
+		if strings.TrimSpace(input.Title) == "" {
+			return nil, nil, fmt.Errorf("title cannot be empty")
+		}
+
+		out := &importPlaylistOutput{Invalid: []string{}}
+		var videoIDs []string
+		for _, entry := range input.Videos {
+			id, err := youtube.ParseVideoID(entry)
+			if err != nil {
+				out.Invalid = append(out.Invalid, entry)
+				continue
+			}
+			videoIDs = append(videoIDs, id)
+		}
+		if len(videoIDs) == 0 {
+			return nil, nil, fmt.Errorf("no valid video URLs or IDs to import")
+		}
+		if err := s.checkQuotaBudget(ctx, fmt.Sprintf("importing %d songs", len(videoIDs)), costs.Write+len(videoIDs)*costs.Write); err != nil {
+			return nil, nil, err
+		}
+
+		playlist, err := s.ytClient.CreatePlaylist(ctx, s.prefixedTitle(input.Title), "Imported playlist", input.PrivacyStatus)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
+		}
+
+		added, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to import videos into playlist %s (added %d of %d): %w", playlist.ID, added, len(videoIDs), err)
+		}
+
+		out.PlaylistID = playlist.ID
+		out.URL = playlistURL(playlist.ID)
+		out.Added = added
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:shuffle-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:shuffle-playlist",
+		Description: fmt.Sprintf("Shuffles the order of a playlist in place. Moves as few items as possible, but each move costs %d quota units, and a shuffle moves most items: ~%d-%d units for 100 songs. Two-phase: call first without confirm to see the number of moves and quota cost, then again with the returned seed and confirm: true. Refused when the moves would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Quota cost: %d per 50 items + %d per item moved.", costs.Write, costs.Write*80, costs.Write*100, costs.List, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input shufflePlaylistInput) (*mcp.CallToolResult, *shufflePlaylistOutput, error) {
+		playlistID, err := youtube.ParsePlaylistID(input.PlaylistID)
+		if err != nil {
+			return nil, nil, err
+		}
+		items, err := s.ytClient.GetPlaylistItems(ctx, playlistID)
+		if err != nil {
+			return nil, nil, playlistReadError(playlistID, "get playlist items", err)
+		}
+
+		// 53 bits, so the seed survives a round trip through a JSON float
+		seed := rand.Uint64() >> 11
+		if input.Seed != nil {
+			seed = *input.Seed
+		}
+		rng := rand.New(rand.NewPCG(seed, 0))
+		moves := planReorder(items, rng.Perm(len(items)))
+
+		out := &shufflePlaylistOutput{Items: len(items), Moves: len(moves), Seed: seed}
+		switch {
+		case len(moves) == 0:
+			out.Message = "The playlist is too short to shuffle; nothing to move."
+		case !input.Confirm:
+			out.Message = fmt.Sprintf("Shuffling %d items would move %d of them (~%d quota units). %s", len(items), len(moves), len(moves)*costs.Write, confirmHint)
+		default:
+			if err := s.checkQuotaBudget(ctx, fmt.Sprintf("shuffling %d items", len(items)), len(moves)*costs.Write); err != nil {
+				return nil, nil, err
+			}
+			for i, move := range moves {
+				if err := s.ytClient.ReorderPlaylistItem(ctx, playlistID, move.item.PlaylistItemID, move.item.ID, move.position); err != nil {
+					return nil, nil, fmt.Errorf("failed to shuffle playlist (moved %d of %d items): %w", i, len(moves), err)
+				}
+			}
+			out.Confirmed = true
+			out.Message = fmt.Sprintf("Shuffled %d items with %d moves.", len(items), len(moves))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:set-playlist-thumbnail
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:set-playlist-thumbnail",
+		Description: fmt.Sprintf("Makes a playlist show the thumbnail of one of its videos. The API can only set a playlist image by uploading one, so this moves the video to the top instead: YouTube shows the first item's thumbnail. The other items shift down by one. Quota cost: %d per 50 items + %d for the move (nothing if the video is already first).", costs.List, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input setPlaylistThumbnailInput) (*mcp.CallToolResult, *setPlaylistThumbnailOutput, error) {
+		playlistID, err := youtube.ParsePlaylistID(input.PlaylistID)
+		if err != nil {
+			return nil, nil, err
+		}
+		videoID, err := youtube.ParseVideoID(input.VideoID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items, err := s.ytClient.GetPlaylistItems(ctx, playlistID)
+		if err != nil {
+			return nil, nil, playlistReadError(playlistID, "get playlist items", err)
+		}
+		i := slices.IndexFunc(items, func(v youtube.Video) bool { return v.ID == videoID })
+		if i < 0 {
+			return nil, nil, fmt.Errorf("video %s is not in playlist %s; add it first with ym:add-to-playlist", videoID, playlistID)
+		}
+		item := items[i]
+
+		out := &setPlaylistThumbnailOutput{PreviousPosition: item.Position, ThumbnailURL: item.ThumbnailURL}
+		if item.Position != 0 {
+			if err := s.ytClient.ReorderPlaylistItem(ctx, playlistID, item.PlaylistItemID, item.ID, 0); err != nil {
+				return nil, nil, fmt.Errorf("failed to move video to the top: %w", err)
+			}
+			out.Moved = true
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:rate-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:rate-playlist",
+		Description: fmt.Sprintf("Likes (or dislikes, or clears the rating of) every song in a playlist, e.g. after the user approves a generated playlist. Each song is rated once even if it appears more often; deleted and private items are skipped. Two-phase: call first without confirm to see the song count and quota cost, then again with confirm: true. Each rating costs %d quota units; refused when the ratings would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows, and stopped at the first quota or rate-limit error. Quota cost: %d per 50 items + %d per song rated.", costs.Write, costs.List, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ratePlaylistInput) (*mcp.CallToolResult, *ratePlaylistOutput, error) {
+		rating := cmp.Or(strings.ToLower(strings.TrimSpace(input.Rating)), "like")
+		if !slices.Contains(youtube.Ratings, rating) {
+			return nil, nil, fmt.Errorf("invalid rating %q: must be one of %s", input.Rating, strings.Join(youtube.Ratings, ", "))
+		}
+
+		items, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+		items, _ = youtube.SkipUnavailable(items)
+
+		var videoIDs []string
+		seen := make(map[string]struct{}, len(items))
+		for _, item := range items {
+			if _, dup := seen[item.ID]; dup {
+				continue
+			}
+			seen[item.ID] = struct{}{}
+			videoIDs = append(videoIDs, item.ID)
+		}
+
+		out := &ratePlaylistOutput{Videos: len(videoIDs), Failed: []string{}}
+		switch {
+		case len(videoIDs) == 0:
+			out.Message = "The playlist has no songs to rate."
+		case !input.Confirm:
+			out.Message = fmt.Sprintf("Would rate %d songs as %q (~%d quota units). %s", len(videoIDs), rating, len(videoIDs)*costs.Write, confirmHint)
+		default:
+			if err := s.checkQuotaBudget(ctx, fmt.Sprintf("rating %d songs", len(videoIDs)), len(videoIDs)*costs.Write); err != nil {
+				return nil, nil, err
+			}
+			rated, failed, err := s.rateVideos(ctx, videoIDs, rating)
+			if err != nil {
+				return nil, nil, fmt.Errorf("rating stopped after %d of %d songs: %w", rated, len(videoIDs), err)
+			}
+			out.Rated = rated
+			out.Failed = append(out.Failed, failed...)
+			out.Confirmed = true
+			out.Message = fmt.Sprintf("Rated %d of %d songs as %q; %d failed.", out.Rated, len(videoIDs), rating, len(out.Failed))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Destructive tools. They follow a two-phase pattern: without confirm: true
+	// they only describe what they would do, so an autonomous call can't destroy data.
+
+	// Tool: ym:move-videos
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:move-videos",
+		Description: fmt.Sprintf("Moves songs from one playlist to another: adds them to the target, then removes them from the source, but only the ones that were added, so a failure never loses a song; a quota or rate-limit error stops the move. Refused when it would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Songs already in the target or missing from the source are skipped. Changes two playlists and is two-phase: call first without confirm to preview, then again with confirm: true. Quota cost: %d per 50 items of each playlist + %d per song added + %d per entry removed.", costs.List, costs.Write, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input moveVideosInput) (*mcp.CallToolResult, *moveVideosOutput, error) {
+		if len(input.VideoIDs) == 0 {
+			return nil, nil, fmt.Errorf("videoIds cannot be empty")
+		}
+		sourceID, err := youtube.ParsePlaylistID(input.SourcePlaylistID)
+		if err != nil {
+			return nil, nil, err
+		}
+		targetID, err := youtube.ParsePlaylistID(input.TargetPlaylistID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sourceID == targetID {
+			return nil, nil, fmt.Errorf("source and target playlists must differ")
+		}
+
+		sourceItems, err := s.ytClient.GetPlaylistItems(ctx, sourceID)
+		if err != nil {
+			return nil, nil, playlistReadError(sourceID, "get source playlist items", err)
+		}
+		targetItems, err := s.ytClient.GetPlaylistItems(ctx, targetID)
+		if err != nil {
+			return nil, nil, playlistReadError(targetID, "get target playlist items", err)
+		}
+
+		inSource := make(map[string][]string, len(sourceItems))
+		for _, item := range sourceItems {
+			inSource[item.ID] = append(inSource[item.ID], item.PlaylistItemID)
+		}
+		inTarget := make(map[string]struct{}, len(targetItems))
+		for _, item := range targetItems {
+			inTarget[item.ID] = struct{}{}
+		}
+
+		out := &moveVideosOutput{ToMove: []string{}, Skipped: []string{}, Failed: []string{}}
+		seen := make(map[string]struct{}, len(input.VideoIDs))
+		removals := 0
+		for _, v := range input.VideoIDs {
+			videoID, err := youtube.ParseVideoID(v)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, dup := seen[videoID]; dup {
+				continue
+			}
+			seen[videoID] = struct{}{}
+
+			switch _, already := inTarget[videoID]; {
+			case len(inSource[videoID]) == 0:
+				out.Skipped = append(out.Skipped, videoID+": not in the source playlist")
+			case already:
+				out.Skipped = append(out.Skipped, videoID+": already in the target playlist")
+			default:
+				out.ToMove = append(out.ToMove, videoID)
+				removals += len(inSource[videoID])
+			}
+		}
+
+		switch {
+		case len(out.ToMove) == 0:
+			out.Message = "None of the songs can be moved; nothing changed."
+		case !input.Confirm:
+			out.Message = fmt.Sprintf("Would move %d songs from %s to %s, removing %d entries from the source (~%d quota units). %s", len(out.ToMove), sourceID, targetID, removals, (len(out.ToMove)+removals)*costs.Write, confirmHint)
+		default:
+			if err := s.checkQuotaBudget(ctx, fmt.Sprintf("moving %d songs", len(out.ToMove)), (len(out.ToMove)+removals)*costs.Write); err != nil {
+				return nil, nil, err
+			}
+
+			// Add first and only remove what was added, so nothing is lost.
+			// A quota or rate-limit error would fail every later add too.
+			var itemIDs []string
+			var stopErr error
+			for _, videoID := range out.ToMove {
+				if err := ctx.Err(); err != nil {
+					return nil, nil, fmt.Errorf("move interrupted before removing anything from the source: %w", err)
+				}
+				if _, err := s.ytClient.AddVideosToPlaylist(ctx, targetID, []string{videoID}); err != nil {
+					if stopsWrites(err) {
+						stopErr = err
+						break
+					}
+					out.Failed = append(out.Failed, fmt.Sprintf("%s: %v", videoID, err))
+					continue
+				}
+				itemIDs = append(itemIDs, inSource[videoID]...)
+				out.Moved++
+			}
+
+			if len(itemIDs) > 0 {
+				removed, err := s.ytClient.RemovePlaylistItems(ctx, itemIDs)
+				if err != nil {
+					return nil, nil, fmt.Errorf("added %d songs to the target but failed to remove them from the source (removed %d of %d entries), so they are in both playlists: %w", out.Moved, removed, len(itemIDs), err)
+				}
+			}
+			if stopErr != nil {
+				return nil, nil, fmt.Errorf("move stopped after %d of %d songs, which were removed from the source; the rest were not moved: %w", out.Moved, len(out.ToMove), stopErr)
+			}
+			out.Confirmed = true
+			out.Message = fmt.Sprintf("Moved %d of %d songs from %s to %s; %d skipped, %d failed.", out.Moved, len(out.ToMove), sourceID, targetID, len(out.Skipped), len(out.Failed))
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:remove-from-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:remove-from-playlist",
+		Description: fmt.Sprintf("Removes songs from a playlist by playlist item ID, video ID, or zero-based position. Positions are resolved against the playlist as it is at the time of the call, so they can point at different songs after any change; to be safe, confirm with the playlistItemIds the preview returns. Destructive and two-phase: call first without confirm to preview the matched entries, then again with confirm: true to remove them. Refused when the removals would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Quota cost: %d per 50 playlist items to match + %d per song removed.", costs.List, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input removeFromPlaylistInput) (*mcp.CallToolResult, *removeFromPlaylistOutput, error) {
+		if len(input.PlaylistItemIDs) == 0 && len(input.VideoIDs) == 0 && len(input.Positions) == 0 {
+			return nil, nil, fmt.Errorf("playlistItemIds, videoIds or positions is required")
+		}
+
+		// Positions are resolved and removed against this one snapshot
+		items, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+		wantPositions := make(map[int64]struct{}, len(input.Positions))
+		for _, position := range input.Positions {
+			if position < 0 || position >= int64(len(items)) {
+				return nil, nil, fmt.Errorf("position %d is out of range: the playlist has %d entries", position, len(items))
+			}
+			wantPositions[position] = struct{}{}
+		}
+
+		// Match the requested entries against the playlist
+		wantItems := make(map[string]struct{}, len(input.PlaylistItemIDs))
+		for _, id := range input.PlaylistItemIDs {
+			wantItems[id] = struct{}{}
+		}
+		wantVideos := make(map[string]struct{}, len(input.VideoIDs))
+		for _, v := range input.VideoIDs {
+			id, err := youtube.ParseVideoID(v)
+			if err != nil {
+				return nil, nil, err
+			}
+			wantVideos[id] = struct{}{}
+		}
+		out := &removeFromPlaylistOutput{Items: []videoOutput{}}
+		var itemIDs []string
+		for _, item := range items {
+			_, byItem := wantItems[item.PlaylistItemID]
+			_, byVideo := wantVideos[item.ID]
+			_, byPosition := wantPositions[item.Position]
+			if byItem || byVideo || byPosition {
+				out.Items = append(out.Items, newVideoOutput(item))
+				itemIDs = append(itemIDs, item.PlaylistItemID)
+			}
+		}
+
+		switch {
+		case len(itemIDs) == 0:
+			out.Message = "No matching songs found in the playlist; nothing to remove."
+		case !input.Confirm:
+			out.Message = fmt.Sprintf("Would remove %d entries from playlist %s (~%d quota units). %s", len(itemIDs), input.PlaylistID, len(itemIDs)*costs.Write, confirmHint)
+		default:
+			if err := s.checkQuotaBudget(ctx, fmt.Sprintf("removing %d entries", len(itemIDs)), len(itemIDs)*costs.Write); err != nil {
+				return nil, nil, err
+			}
+			removed, err := s.ytClient.RemovePlaylistItems(ctx, itemIDs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to remove songs (removed %d of %d): %w", removed, len(itemIDs), err)
+			}
+			out.Confirmed = true
+			out.Removed = removed
+			out.Message = fmt.Sprintf("Removed %d entries from playlist %s.", removed, input.PlaylistID)
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:remove-duplicates-from-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:remove-duplicates-from-playlist",
+		Description: fmt.Sprintf("Removes every extra copy of videos that appear more than once in a playlist, keeping the first occurrence. Destructive and two-phase: call first without confirm to preview the duplicates, then again with confirm: true to remove them. Refused when the removals would cost more quota than remains today or than MAX_QUOTA_PER_CALL allows. Quota cost: %d per 50 playlist items + %d per copy removed.", costs.List, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input removeDuplicatesInput) (*mcp.CallToolResult, *removeDuplicatesOutput, error) {
+		items, err := s.ytClient.GetPlaylistItems(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist items", err)
+		}
+
+		out := &removeDuplicatesOutput{Duplicates: findDuplicateVideos(items)}
+		var itemIDs []string
+		for _, d := range out.Duplicates {
+			itemIDs = append(itemIDs, d.RemoveItemIDs...)
+		}
+
+		switch {
+		case len(itemIDs) == 0:
+			out.Message = "No duplicates found in the playlist; nothing to remove."
+		case !input.Confirm:
+			out.Message = fmt.Sprintf("Would remove %d extra copies of %d videos from playlist %s (~%d quota units). %s", len(itemIDs), len(out.Duplicates), input.PlaylistID, len(itemIDs)*costs.Write, confirmHint)
+		default:
+			if err := s.checkQuotaBudget(ctx, fmt.Sprintf("removing %d duplicates", len(itemIDs)), len(itemIDs)*costs.Write); err != nil {
+				return nil, nil, err
+			}
+			removed, err := s.ytClient.RemovePlaylistItems(ctx, itemIDs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to remove duplicates (removed %d of %d): %w", removed, len(itemIDs), err)
+			}
+			out.Confirmed = true
+			out.Removed = removed
+			out.Message = fmt.Sprintf("Removed %d extra copies from playlist %s.", removed, input.PlaylistID)
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+
+	// Tool: ym:delete-playlist
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:delete-playlist",
+		Description: fmt.Sprintf("Permanently deletes one of the user's playlists. Destructive and two-phase: call first without confirm to preview which playlist would be deleted, then again with confirm: true to delete it. Quota cost: %d to look up + %d to delete.", costs.List, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input deletePlaylistInput) (*mcp.CallToolResult, *deletePlaylistOutput, error) {
+		playlist, err := s.ytClient.GetPlaylist(ctx, input.PlaylistID)
+		if err != nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist", err)
+		}
+		if playlist == nil {
+			return nil, nil, playlistReadError(input.PlaylistID, "get playlist", youtube.ErrPlaylistNotFound)
+		}
+
+		target := newPlaylistOutput(*playlist)
+		out := &deletePlaylistOutput{Playlist: &target}
+		if !input.Confirm {
+			out.Message = fmt.Sprintf("Would permanently delete playlist '%s' with %d items. %s", playlist.Title, playlist.ItemCount, confirmHint)
+			out.QuotaUsed = quotaSpent(ctx)
+			return nil, out, nil
+		}
+
+		if err := s.ytClient.DeletePlaylist(ctx, playlist.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete playlist: %w", err)
+		}
+		out.Confirmed = true
+		out.Message = fmt.Sprintf("Deleted playlist '%s'.", playlist.Title)
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+}
+
+// findDuplicateVideos groups playlist items by video ID and returns the videos
+// that occur more than once, in playlist order. The first occurrence of each
+// is kept; the later ones are listed for removal.
+func findDuplicateVideos(items []youtube.Video) []duplicateVideoOutput {
+	index := make(map[string]int)
+	var videos []duplicateVideoOutput
+	for _, item := range items {
+		i, seen := index[item.ID]
+		if !seen {
+			index[item.ID] = len(videos)
+			videos = append(videos, duplicateVideoOutput{
+				VideoID:    item.ID,
+				Title:      item.Title,
+				KeepItemID: item.PlaylistItemID,
+			})
+			i = len(videos) - 1
+		} else {
+			videos[i].RemoveItemIDs = append(videos[i].RemoveItemIDs, item.PlaylistItemID)
+		}
+		videos[i].Occurrences++
+		videos[i].Positions = append(videos[i].Positions, item.Position)
+	}
+
+	duplicates := []duplicateVideoOutput{}
+	for _, v := range videos {
+		if v.Occurrences > 1 {
+			duplicates = append(duplicates, v)
+		}
+	}
+	return duplicates
+}
+
+// formatM3U renders entries as an extended M3U playlist: each song's
+// YouTube Music URL preceded by an #EXTINF line with its artist and title.
+// Durations are unknown, so they are written as -1.
+func formatM3U(entries []exportEntry) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	for _, e := range entries {
+		// Line breaks would end the #EXTINF line early
+		title := strings.Join(strings.Fields(e.Title), " ")
+		if e.Channel != "" {
+			title = strings.Join(strings.Fields(e.Channel), " ") + " - " + title
+		}
+		fmt.Fprintf(&b, "#EXTINF:-1,%s\n%s\n", title, e.URL)
+	}
+	return b.String()
+}
+
+// planReorder returns the fewest moves that rearrange items so that items[i]
+// ends up at position target[i]. Items on a longest increasing subsequence of
+// targets already are in order relative to each other and stay put; every
+// other item is moved once, right behind its predecessor among the items
+// already in place.
+func planReorder(items []youtube.Video, target []int) []playlistMove {
+	settled := longestIncreasing(target)
+
+	// order simulates the playlist as indexes into items
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	byTarget := make([]int, len(items))
+	for i, t := range target {
+		byTarget[t] = i
+	}
+
+	var moves []playlistMove
+	for _, i := range byTarget {
+		if settled[i] {
+			continue
+		}
+		order = slices.Delete(order, slices.Index(order, i), slices.Index(order, i)+1)
+		position := 0
+		for j, k := range order {
+			if settled[k] && target[k] < target[i] {
+				position = j + 1
+			}
+		}
+		order = slices.Insert(order, position, i)
+		settled[i] = true
+		moves = append(moves, playlistMove{item: items[i], position: int64(position)})
+	}
+	return moves
+}
+
+// longestIncreasing marks the indexes of one longest strictly increasing
+// subsequence of values.
+func longestIncreasing(values []int) []bool {
+	// tails[k] is the index of the smallest tail of an increasing run of length k+1
+	var tails []int
+	prev := make([]int, len(values))
+	for i, v := range values {
+		k, _ := slices.BinarySearchFunc(tails, v, func(idx, v int) int { return cmp.Compare(values[idx], v) })
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	marked := make([]bool, len(values))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			marked[i] = true
+		}
+	}
+	return marked
+}
@@ -0,0 +1,604 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+)
+
+func TestRateVideosStopsAtQuotaError(t *testing.T) {
+	var calls atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeAPIError(w, http.StatusForbidden, "quotaExceeded")
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	videoIDs := make([]string, 40)
+	for i := range videoIDs {
+		videoIDs[i] = fmt.Sprintf("video%06d", i)
+	}
+	rated, failed, err := s.rateVideos(context.Background(), videoIDs, "like")
+	if err == nil || classifyToolError(err) != errorClassQuota {
+		t.Fatalf("err = %v, want a quota error", err)
+	}
+	if rated != 0 || len(failed) != 0 {
+		t.Errorf("rated %d, failed %v; want none of either", rated, failed)
+	}
+	// Only the ratings already in flight when the first one failed are sent
+	if n := int(calls.Load()); n > rateWorkers {
+		t.Errorf("%d ratings were sent, want at most %d", n, rateWorkers)
+	}
+}
+
+func TestRateVideosContinuesPastOtherErrors(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "bbbbbbbbbbb" {
+			writeAPIError(w, http.StatusNotFound, "videoNotFound")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	rated, failed, err := s.rateVideos(context.Background(), []string{"aaaaaaaaaaa", "bbbbbbbbbbb", "ccccccccccc"}, "like")
+	if err != nil {
+		t.Fatalf("rateVideos: %v", err)
+	}
+	if rated != 2 || len(failed) != 1 || !strings.HasPrefix(failed[0], "bbbbbbbbbbb: ") {
+		t.Errorf("rated %d, failed %v; want 2 rated and bbbbbbbbbbb failed", rated, failed)
+	}
+}
+
+func TestMoveVideosStopsAtQuotaErrorAndRemovesOnlyAdded(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		inserts int
+		deleted []string
+	)
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("playlistId") == "PLsource" {
+			writeJSON(w, playlistItemsResponse("song0000001", "song0000002", "song0000003"))
+			return
+		}
+		writeJSON(w, playlistItemsResponse())
+	})
+	api.HandleFunc("POST /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		inserts++
+		if inserts > 1 {
+			writeAPIError(w, http.StatusForbidden, "rateLimitExceeded")
+			return
+		}
+		writeJSON(w, map[string]any{"id": "new-item"})
+	})
+	api.HandleFunc("DELETE /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		deleted = append(deleted, r.URL.Query().Get("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	result := callTool(t, s, "ym:move-videos", map[string]any{
+		"sourcePlaylistId": "PLsource",
+		"targetPlaylistId": "PLtarget",
+		"videoIds":         []string{"song0000001", "song0000002", "song0000003"},
+		"confirm":          true,
+	})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "move stopped after 1 of 3 songs") {
+		t.Fatalf("result %q, want the move stopped by the rate-limit error", text)
+	}
+	if inserts != 2 {
+		t.Errorf("%d inserts, want 2: none after the rate-limit error", inserts)
+	}
+	if !slices.Equal(deleted, []string{"item-song0000001"}) {
+		t.Errorf("removed %v from the source, want only the added item-song0000001", deleted)
+	}
+}
+
+func TestMoveVideosRefusesOverBudget(t *testing.T) {
+	var writes atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("playlistId") == "PLsource" {
+			writeJSON(w, playlistItemsResponse("song0000001", "song0000002"))
+			return
+		}
+		writeJSON(w, playlistItemsResponse())
+	})
+	api.HandleFunc("/youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	// Moving two songs costs 4 writes, ~200 units
+	s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": "150"}), api)
+
+	result := callTool(t, s, "ym:move-videos", map[string]any{
+		"sourcePlaylistId": "PLsource",
+		"targetPlaylistId": "PLtarget",
+		"videoIds":         []string{"song0000001", "song0000002"},
+		"confirm":          true,
+	})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "MAX_QUOTA_PER_CALL") {
+		t.Fatalf("result %q, want the move refused over MAX_QUOTA_PER_CALL", text)
+	}
+	if n := writes.Load(); n != 0 {
+		t.Errorf("%d writes were sent, want none", n)
+	}
+}
+
+func TestDeletePlaylistReportsMissingPlaylist(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{}})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	result := callTool(t, s, "ym:delete-playlist", map[string]any{"playlistId": "PLgone"})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "PLgone does not exist") {
+		t.Errorf("result %q, want the playlist reported missing", text)
+	}
+}
+
+func TestCheckQuotaBudgetCountsEveryUser(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := newTestServer(t, testConfig(t, map[string]string{"YOUTUBE_DAILY_QUOTA": "200"}), api)
+
+	// Another user spends 150 of the project's 200 units
+	other := youtube.WithQuotaIdentity(context.Background(), "other")
+	for range 3 {
+		if err := s.ytClient.RateVideo(other, "song0000001", "like"); err != nil {
+			t.Fatalf("RateVideo: %v", err)
+		}
+	}
+
+	caller := youtube.WithQuotaIdentity(context.Background(), "caller")
+	if err := s.checkQuotaBudget(caller, "adding 1 song", 50); err != nil {
+		t.Errorf("50 of the remaining 50 units refused: %v", err)
+	}
+	if err := s.checkQuotaBudget(caller, "adding 2 songs", 100); err == nil || !strings.Contains(err.Error(), "only ~50 remain") {
+		t.Errorf("err = %v, want 100 units refused with ~50 remaining", err)
+	}
+}
+
+func TestImportPlaylistRefusesOverBudget(t *testing.T) {
+	var writes atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("/youtube/v3/", func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		writeJSON(w, map[string]any{"id": "PLnew"})
+	})
+	// Importing two songs costs 3 writes, ~150 units
+	s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": "100"}), api)
+
+	result := callTool(t, s, "ym:import-playlist", map[string]any{
+		"title":  "Imported",
+		"videos": []string{"https://youtu.be/song0000001", "song0000002"},
+	})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "importing 2 songs") || !strings.Contains(text, "MAX_QUOTA_PER_CALL") {
+		t.Fatalf("result %q, want the import refused over MAX_QUOTA_PER_CALL", text)
+	}
+	if n := writes.Load(); n != 0 {
+		t.Errorf("%d API calls were sent, want none", n)
+	}
+}
+
+func TestRemoveFromPlaylistRefusesOverBudget(t *testing.T) {
+	var deletes atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "song0000002", "song0000003"))
+	})
+	api.HandleFunc("DELETE /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		deletes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	// Removing three entries costs ~150 units
+	s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": "100"}), api)
+
+	result := callTool(t, s, "ym:remove-from-playlist", map[string]any{
+		"playlistId": "PLsome",
+		"positions":  []int{0, 1, 2},
+		"confirm":    true,
+	})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "removing 3 entries") {
+		t.Fatalf("result %q, want the removal refused over MAX_QUOTA_PER_CALL", text)
+	}
+	if n := deletes.Load(); n != 0 {
+		t.Errorf("%d deletes were sent, want none", n)
+	}
+}
+
+func TestRemoveFromPlaylistByPositions(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "song0000002", "song0000003", "song0000004"))
+	})
+	api.HandleFunc("DELETE /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		deleted = append(deleted, r.URL.Query().Get("id"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+	args := map[string]any{"playlistId": "PLsome", "positions": []int{0, 2}}
+
+	// The preview matches the entries at those positions and removes nothing
+	var out removeFromPlaylistOutput
+	decodeOutput(t, callTool(t, s, "ym:remove-from-playlist", args), &out)
+	var matched []string
+	for _, item := range out.Items {
+		matched = append(matched, item.ID)
+	}
+	if out.Confirmed || !slices.Equal(matched, []string{"song0000001", "song0000003"}) {
+		t.Errorf("preview: confirmed %v, matched %q; want songs 1 and 3 unconfirmed", out.Confirmed, matched)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("preview deleted %q, want nothing", deleted)
+	}
+
+	args["confirm"] = true
+	out = removeFromPlaylistOutput{}
+	decodeOutput(t, callTool(t, s, "ym:remove-from-playlist", args), &out)
+	slices.Sort(deleted)
+	if !out.Confirmed || out.Removed != 2 || !slices.Equal(deleted, []string{"item-song0000001", "item-song0000003"}) {
+		t.Errorf("confirm: removed %d, deleted %q; want the items at positions 0 and 2", out.Removed, deleted)
+	}
+
+	result := callTool(t, s, "ym:remove-from-playlist", map[string]any{"playlistId": "PLsome", "positions": []int{4}})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "position 4 is out of range") {
+		t.Errorf("position past the end: %q, want it rejected", text)
+	}
+}
+
+func TestRemoveDuplicatesRefusesOverQuotaLeft(t *testing.T) {
+	var deletes atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "song0000001", "song0000001"))
+	})
+	api.HandleFunc("DELETE /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		deletes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	// Removing the two extra copies costs ~100 units; reading the playlist spends 1
+	s := newTestServer(t, testConfig(t, map[string]string{"YOUTUBE_DAILY_QUOTA": "100"}), api)
+
+	result := callTool(t, s, "ym:remove-duplicates-from-playlist", map[string]any{"playlistId": "PLsome", "confirm": true})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "removing 2 duplicates") || !strings.Contains(text, "only ~99 remain today") {
+		t.Fatalf("result %q, want the removal refused over the quota left", text)
+	}
+	if n := deletes.Load(); n != 0 {
+		t.Errorf("%d deletes were sent, want none", n)
+	}
+}
+
+func TestAddToPlaylistRefusesOverBudget(t *testing.T) {
+	var calls atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("/youtube/v3/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, playlistItemsResponse())
+	})
+	s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": "100"}), api)
+
+	result := callTool(t, s, "ym:add-to-playlist", map[string]any{
+		"playlistId": "PLsome",
+		"videoIds":   []string{"song0000001", "song0000002", "song0000003"},
+	})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "adding 3 songs") {
+		t.Fatalf("result %q, want the adds refused over MAX_QUOTA_PER_CALL", text)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("%d API calls were sent, want none", n)
+	}
+}
+
+func TestPlaylistStats(t *testing.T) {
+	type entry struct{ videoID, artist, duration string }
+	entries := []entry{
+		{"song0000001", "Daft Punk", "PT3M"},
+		{"song0000002", "Daft Punk", "PT4M30S"},
+		{"song0000003", "Air", "PT5M"},
+		{"song0000001", "Daft Punk", "PT3M"}, // added twice, counts twice
+		{"live0000001", "Justice", "P0D"},    // livestream, no duration
+		{"gone0000001", "", ""},              // deleted
+	}
+	var videoIDs []string
+	durations := make(map[string]string)
+	for _, e := range entries {
+		videoIDs = append(videoIDs, e.videoID)
+		durations[e.videoID] = e.duration
+	}
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		page := playlistItemsResponse(videoIDs...)
+		for i, item := range page["items"].([]map[string]any) {
+			snippet := item["snippet"].(map[string]any)
+			snippet["videoOwnerChannelTitle"] = entries[i].artist
+			if entries[i].videoID == "gone0000001" {
+				snippet["title"] = "Deleted video"
+			}
+		}
+		writeJSON(w, page)
+	})
+	var lookedUp []string
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, id := range r.URL.Query()["id"] {
+			for id := range strings.SplitSeq(id, ",") {
+				lookedUp = append(lookedUp, id)
+				items = append(items, map[string]any{
+					"id":             id,
+					"snippet":        map[string]any{"title": "Song " + id},
+					"contentDetails": map[string]any{"duration": durations[id]},
+				})
+			}
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	var out playlistStatsOutput
+	decodeOutput(t, callTool(t, s, "ym:playlist-stats", map[string]any{"playlistId": "PLsome", "topArtists": 2}), &out)
+
+	if out.TotalItems != 6 || out.Unavailable != 1 || out.UnknownDuration != 1 {
+		t.Errorf("items %d, unavailable %d, unknown duration %d; want 6, 1 and 1", out.TotalItems, out.Unavailable, out.UnknownDuration)
+	}
+	// 3:00 + 4:30 + 5:00 + 3:00 over four songs with a known length
+	if out.TotalDurationSeconds != 930 || out.TotalDurationHuman != "15:30" {
+		t.Errorf("total duration %ds (%s), want 930s (15:30)", out.TotalDurationSeconds, out.TotalDurationHuman)
+	}
+	if out.AverageDurationSeconds != 232 || out.AverageDurationHuman != "3:52" {
+		t.Errorf("average duration %ds (%s), want 232s (3:52)", out.AverageDurationSeconds, out.AverageDurationHuman)
+	}
+	want := []artistCountOutput{{"Daft Punk", 3}, {"Air", 1}}
+	if out.UniqueArtists != 3 || !slices.Equal(out.TopArtists, want) {
+		t.Errorf("unique artists %d, top %v; want 3 and %v", out.UniqueArtists, out.TopArtists, want)
+	}
+	if slices.Contains(lookedUp, "gone0000001") {
+		t.Errorf("looked up durations of %q, want the deleted item left out", lookedUp)
+	}
+	if out.QuotaUsed != 2 {
+		t.Errorf("quota used = %d, want 2 (items page and duration lookup)", out.QuotaUsed)
+	}
+}
+
+func TestDiffPlaylists(t *testing.T) {
+	playlists := map[string][]string{
+		"PLa":        {"song0000001", "song0000002", "song0000003", "song0000002", "gone0000001"},
+		"PLb":        {"song0000003", "song0000004", "song0000002"},
+		"PLdisjoint": {"song0000005", "song0000006"},
+	}
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		page := playlistItemsResponse(playlists[r.URL.Query().Get("playlistId")]...)
+		for _, item := range page["items"].([]map[string]any) {
+			if strings.HasPrefix(item["id"].(string), "item-gone") {
+				item["snippet"].(map[string]any)["title"] = "Deleted video"
+			}
+		}
+		writeJSON(w, page)
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	ids := func(videos []diffVideoOutput) []string {
+		out := []string{}
+		for _, v := range videos {
+			out = append(out, v.VideoID)
+		}
+		return out
+	}
+	tests := []struct {
+		name               string
+		a, b               string
+		onlyA, onlyB, both []string
+		unavailableA       int
+	}{
+		{"overlapping", "PLa", "PLb", []string{"song0000001"}, []string{"song0000004"}, []string{"song0000002", "song0000003"}, 1},
+		{"disjoint", "PLb", "PLdisjoint", []string{"song0000003", "song0000004", "song0000002"}, []string{"song0000005", "song0000006"}, []string{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out diffPlaylistsOutput
+			decodeOutput(t, callTool(t, s, "ym:diff-playlists", map[string]any{"playlistA": tt.a, "playlistB": tt.b}), &out)
+			if got := ids(out.OnlyInA); !slices.Equal(got, tt.onlyA) || out.OnlyInACount != len(tt.onlyA) {
+				t.Errorf("only in A = %q (count %d), want %q", got, out.OnlyInACount, tt.onlyA)
+			}
+			if got := ids(out.OnlyInB); !slices.Equal(got, tt.onlyB) || out.OnlyInBCount != len(tt.onlyB) {
+				t.Errorf("only in B = %q (count %d), want %q", got, out.OnlyInBCount, tt.onlyB)
+			}
+			if got := ids(out.Common); !slices.Equal(got, tt.both) || out.CommonCount != len(tt.both) {
+				t.Errorf("common = %q (count %d), want %q", got, out.CommonCount, tt.both)
+			}
+			if out.UnavailableA != tt.unavailableA || out.Truncated {
+				t.Errorf("unavailable in A %d, truncated %v; want %d and false", out.UnavailableA, out.Truncated, tt.unavailableA)
+			}
+		})
+	}
+
+	// maxResults trims the lists but not the counts
+	var out diffPlaylistsOutput
+	decodeOutput(t, callTool(t, s, "ym:diff-playlists", map[string]any{"playlistA": "PLb", "playlistB": "PLdisjoint", "maxResults": 1}), &out)
+	if len(out.OnlyInA) != 1 || out.OnlyInACount != 3 || !out.Truncated {
+		t.Errorf("with maxResults 1: only in A lists %d of %d, truncated %v; want 1 of 3 and truncated", len(out.OnlyInA), out.OnlyInACount, out.Truncated)
+	}
+}
+
+func TestSetPlaylistThumbnailMovesVideoToTop(t *testing.T) {
+	var moves []map[string]any
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "song0000002", "song0000003"))
+	})
+	api.HandleFunc("PUT /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		var item map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		moves = append(moves, item)
+		writeJSON(w, item)
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	var out setPlaylistThumbnailOutput
+	decodeOutput(t, callTool(t, s, "ym:set-playlist-thumbnail", map[string]any{"playlistId": "PLsome", "videoId": "song0000003"}), &out)
+	if !out.Moved || out.PreviousPosition != 2 {
+		t.Errorf("output %+v, want moved from position 2", out)
+	}
+	if len(moves) != 1 {
+		t.Fatalf("%d moves, want 1", len(moves))
+	}
+	snippet, _ := moves[0]["snippet"].(map[string]any)
+	if position, ok := snippet["position"]; moves[0]["id"] != "item-song0000003" || !ok || position != float64(0) {
+		t.Errorf("move %v, want item-song0000003 sent to position 0", moves[0])
+	}
+}
+
+func TestSetPlaylistThumbnailWrapsMoveError(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "song0000002"))
+	})
+	api.HandleFunc("PUT /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusForbidden, "playlistOperationUnsupported")
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	result := callTool(t, s, "ym:set-playlist-thumbnail", map[string]any{"playlistId": "PLsome", "videoId": "song0000002"})
+	if text := resultText(result); !result.IsError || !strings.HasPrefix(text, "failed to move video to the top: ") {
+		t.Errorf("result %q, want the move error wrapped", text)
+	}
+}
+
+func TestExportPlaylistAsM3U(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		item := func(videoID, title, channel string) map[string]any {
+			return map[string]any{"snippet": map[string]any{
+				"title":                  title,
+				"videoOwnerChannelTitle": channel,
+				"resourceId":             map[string]any{"kind": "youtube#video", "videoId": videoID},
+			}}
+		}
+		writeJSON(w, map[string]any{"items": []any{
+			item("aaaaaaaaaaa", "One More Time", "Daft Punk"),
+			item("bbbbbbbbbbb", "Deleted video", ""),
+			item("ccccccccccc", "Genesis\n(Live)", "Justice"),
+			item("ddddddddddd", "Untitled", ""),
+		}})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	var out exportPlaylistOutput
+	decodeOutput(t, callTool(t, s, "ym:export-playlist", map[string]any{"playlistId": "PLtest", "format": "m3u"}), &out)
+
+	want := "#EXTM3U\n" +
+		"#EXTINF:-1,Daft Punk - One More Time\nhttps://music.youtube.com/watch?v=aaaaaaaaaaa\n" +
+		"#EXTINF:-1,Justice - Genesis (Live)\nhttps://music.youtube.com/watch?v=ccccccccccc\n" +
+		"#EXTINF:-1,Untitled\nhttps://music.youtube.com/watch?v=ddddddddddd\n"
+	if out.Content != want {
+		t.Errorf("content =\n%s\nwant\n%s", out.Content, want)
+	}
+	if out.Exported != 3 || out.Skipped != 1 {
+		t.Errorf("exported %d, skipped %d, want 3 and 1", out.Exported, out.Skipped)
+	}
+}
+
+func TestCreatedPlaylistsUseDefaultPlaylistPrivacy(t *testing.T) {
+	var privacy []string
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		var playlist struct {
+			Status struct {
+				PrivacyStatus string `json:"privacyStatus"`
+			} `json:"status"`
+		}
+		json.NewDecoder(r.Body).Decode(&playlist)
+		privacy = append(privacy, playlist.Status.PrivacyStatus)
+		writeJSON(w, map[string]any{"id": "PLnew", "snippet": map[string]any{"title": "Shared"}, "status": playlist.Status})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse())
+	})
+	api.HandleFunc("POST /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"id": "item-new"})
+	})
+	s := newTestServer(t, testConfig(t, map[string]string{"DEFAULT_PLAYLIST_PRIVACY": "unlisted"}), api)
+
+	for _, explicit := range []string{"", "public"} {
+		result := callTool(t, s, "ym:import-playlist", map[string]any{
+			"title":         "Shared",
+			"privacyStatus": explicit,
+			"videos":        []string{"dQw4w9WgXcQ"},
+		})
+		if result.IsError {
+			t.Fatalf("import-playlist: %s", resultText(result))
+		}
+	}
+	// The configured default applies only when no privacy is given
+	if want := []string{"unlisted", "public"}; !slices.Equal(privacy, want) {
+		t.Errorf("created playlists with privacy %q, want %q", privacy, want)
+	}
+}
+
+func TestRatePlaylistRatesEachUniqueVideoOnce(t *testing.T) {
+	var mu sync.Mutex
+	rated := make(map[string]int)
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		page := playlistItemsResponse("aaaaaaaaaaa", "bbbbbbbbbbb", "aaaaaaaaaaa", "ccccccccccc", "bbbbbbbbbbb", "deleted0001")
+		items := page["items"].([]map[string]any)
+		items[5]["snippet"].(map[string]any)["title"] = "Deleted video"
+		writeJSON(w, page)
+	})
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("rating") != "like" {
+			t.Errorf("rating = %q, want like", r.URL.Query().Get("rating"))
+		}
+		mu.Lock()
+		rated[r.URL.Query().Get("id")]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	// Without confirm nothing is rated
+	var out ratePlaylistOutput
+	decodeOutput(t, callTool(t, s, "ym:rate-playlist", map[string]any{"playlistId": "PLdupes"}), &out)
+	if out.Videos != 3 || out.Confirmed || len(rated) != 0 {
+		t.Fatalf("preview: %d videos, confirmed %v, rated %v; want 3 videos and nothing rated", out.Videos, out.Confirmed, rated)
+	}
+
+	out = ratePlaylistOutput{}
+	decodeOutput(t, callTool(t, s, "ym:rate-playlist", map[string]any{"playlistId": "PLdupes", "confirm": true}), &out)
+	want := map[string]int{"aaaaaaaaaaa": 1, "bbbbbbbbbbb": 1, "ccccccccccc": 1}
+	if !maps.Equal(rated, want) {
+		t.Errorf("rate calls per video = %v, want %v", rated, want)
+	}
+	if out.Rated != 3 || len(out.Failed) != 0 {
+		t.Errorf("rated %d, failed %q; want 3 and none", out.Rated, out.Failed)
+	}
+}
@@ -0,0 +1,323 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultPlaylistPrefix marks playlists created by this server when no
+// PLAYLIST_PREFIX is configured.
+const defaultPlaylistPrefix = "[YM-MCP]"
+
+// maxSearchResults is the most results a single search page returns.
+const maxSearchResults = 25
+
+// playlistURL returns the YouTube Music URL of a playlist.
+func playlistURL(playlistID string) string {
+	return fmt.Sprintf("https://music.youtube.com/playlist?list=%s", playlistID)
+}
+
+// songURL returns the YouTube Music URL of a song.
+func songURL(videoID string) string {
+	return fmt.Sprintf("https://music.youtube.com/watch?v=%s", videoID)
+}
+
+// playlistPrefix returns the title prefix that marks playlists created by this server.
+// Both the create side (prefixedTitle) and the detect side (isOwnPlaylist) use it,
+// so they can never drift apart.
+func (s *Server) playlistPrefix() string {
+	return cmp.Or(strings.TrimSpace(s.cfg.PlaylistPrefix), defaultPlaylistPrefix)
+}
+
+// prefixedTitle ensures a playlist title carries the playlist prefix.
+func (s *Server) prefixedTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if s.isOwnPlaylist(title) {
+		return title
+	}
+	return fmt.Sprintf("%s %s", s.playlistPrefix(), title)
+}
+
+// isOwnPlaylist reports whether a playlist title marks it as created by this server.
+func (s *Server) isOwnPlaylist(title string) bool {
+	return strings.HasPrefix(title, s.playlistPrefix())
+}
+
+// keywordStopwords are common words ignored when matching a description against song metadata.
+var keywordStopwords = map[string]struct{}{
+	"a": {}, "an": {}, "the": {}, "and": {}, "or": {}, "of": {}, "for": {}, "to": {},
+	"in": {}, "on": {}, "with": {}, "my": {}, "me": {}, "some": {}, "songs": {},
+	"song": {}, "music": {}, "playlist": {}, "mix": {}, "vibe": {}, "vibes": {},
+}
+
+// descriptionKeywords extracts lowercase matching keywords from a description.
+func descriptionKeywords(description string) []string {
+	seen := make(map[string]struct{})
+	var keywords []string
+	for _, word := range nonWordRe.Split(strings.ToLower(description), -1) {
+		if len(word) < 2 {
+			continue
+		}
+		if _, stop := keywordStopwords[word]; stop {
+			continue
+		}
+		if _, dup := seen[word]; dup {
+			continue
+		}
+		seen[word] = struct{}{}
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// keywordScore counts how many keywords appear in the given text fields.
+func keywordScore(keywords []string, fields ...string) int {
+	text := strings.ToLower(strings.Join(fields, " "))
+	score := 0
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			score++
+		}
+	}
+	return score
+}
+
+// Input types for playlist tools
+
+type createPlaylistFromSearchInput struct {
+	Query         string `json:"query" jsonschema:"Search query (artist/song/genre/mood)"`
+	NumberOfSongs int    `json:"numberOfSongs" jsonschema:"Number of top search results to add (1-25)"`
+	Title         string `json:"title,omitempty" jsonschema:"Playlist title (prefixed with the server's playlist prefix, [YM-MCP] by default). Defaults to the query."`
+	PrivacyStatus string `json:"privacyStatus,omitempty" jsonschema:"Playlist privacy: public/private/unlisted (default private, or the server's DEFAULT_PLAYLIST_PRIVACY)"`
+	SkipIfExists  bool   `json:"skipIfExists,omitempty" jsonschema:"If true and one of your playlists already has the same (prefixed) title, return it unchanged instead of creating another. Makes retries safe."`
+}
+
+type playlistFromLikesInput struct {
+	Description   string `json:"description" jsonschema:"Vibe to match against liked songs (keywords matched in titles/artists, e.g. 'jazz piano', 'daft punk remix')"`
+	NumberOfSongs int    `json:"numberOfSongs" jsonschema:"Maximum number of matching liked songs to add (1-50)"`
+	Title         string `json:"title,omitempty" jsonschema:"Playlist title (prefixed with the server's playlist prefix, [YM-MCP] by default). Defaults to the description."`
+	PrivacyStatus string `json:"privacyStatus,omitempty" jsonschema:"Playlist privacy: public/private/unlisted (default private, or the server's DEFAULT_PLAYLIST_PRIVACY)"`
+	MatchTags     bool   `json:"matchTags,omitempty" jsonschema:"If true also match keywords against each song's video tags (same quota as the music filter)"`
+}
+
+// registerPlaylistTools registers the playlist building MCP tools
+func (s *Server) registerPlaylistTools() {
+	costs := quotaCosts(s.cfg)
+
+	// Every playlist tool creates a playlist, so none are available in read-only mode
+	if s.cfg.ReadOnly {
+		return
+	}
+
+	// Tool: ym:create-playlist-from-search
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:create-playlist-from-search",
+		Description: fmt.Sprintf("Searches YouTube Music once and creates a playlist from the top results in one call. Capped at 25 songs since only one search is performed. Quota cost: %d (search) + %d (playlist creation) + %d per song added, e.g. ~%d units for 10 songs. Set skipIfExists to make retries safe: an existing playlist with the same title is returned instead (+%d per 50 playlists to check).", costs.Search, costs.Write, costs.Write, costs.Search+costs.Write*11, costs.List),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input createPlaylistFromSearchInput) (*mcp.CallToolResult, any, error) {
+		if strings.TrimSpace(input.Query) == "" {
+			return nil, nil, fmt.Errorf("query cannot be empty")
+		}
+		numberOfSongs := min(max(input.NumberOfSongs, 1), maxSearchResults)
+
+		title := input.Title
+		if strings.TrimSpace(title) == "" {
+			title = input.Query
+		}
+		title = s.prefixedTitle(title)
+
+		// Reuse a playlist left behind by an earlier attempt before spending search quota
+		if input.SkipIfExists {
+			existing, err := s.findPlaylistByTitle(ctx, title)
+			if err != nil {
+				return nil, nil, err
+			}
+			if existing != nil {
+				var output strings.Builder
+				fmt.Fprintf(&output, "# Playlist Already Exists: %s\n\n", existing.Title)
+				fmt.Fprintf(&output, "**YouTube Music URL:** %s\n\n", playlistURL(existing.ID))
+				fmt.Fprintf(&output, "Reused your existing playlist with this title (%d songs); nothing was searched or added.\n", existing.ItemCount)
+				fmt.Fprintf(&output, "\n**Quota used:** %d units (playlist lookup)\n", quotaSpent(ctx))
+
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: output.String()},
+					},
+				}, nil, nil
+			}
+		}
+
+		estimate := costs.Search + costs.Write + numberOfSongs*costs.Write
+		if err := s.checkQuotaBudget(ctx, fmt.Sprintf("a playlist of %d songs", numberOfSongs), estimate); err != nil {
+			return nil, nil, err
+		}
+
+		// Single search for the requested number of songs
+		results, err := s.ytClient.SearchVideos(ctx, input.Query, int64(numberOfSongs))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to search: %w", err)
+		}
+
+		// Deduplicate results
+		seen := make(map[string]struct{}, len(results))
+		var videoIDs []string
+		for _, result := range results {
+			if _, exists := seen[result.VideoID]; exists || result.VideoID == "" {
+				continue
+			}
+			seen[result.VideoID] = struct{}{}
+			videoIDs = append(videoIDs, result.VideoID)
+		}
+
+		if len(videoIDs) == 0 {
+			return nil, nil, fmt.Errorf("no videos found for query '%s'", input.Query)
+		}
+
+		// Create playlist
+		description := fmt.Sprintf("Top results for '%s'", input.Query)
+		playlist, err := s.ytClient.CreatePlaylist(ctx, title, description, input.PrivacyStatus)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
+		}
+
+		// Add videos to playlist
+		added, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add videos to playlist: %w", err)
+		}
+
+		// Build response
+		var output strings.Builder
+		fmt.Fprintf(&output, "# Playlist Created: %s\n\n", playlist.Title)
+		fmt.Fprintf(&output, "**YouTube Music URL:** %s\n\n", playlistURL(playlist.ID))
+		fmt.Fprintf(&output, "**Songs added:** %d of %d requested\n\n", added, numberOfSongs)
+		if input.SkipIfExists {
+			fmt.Fprintf(&output, "No playlist with this title existed, so a new one was created.\n")
+		}
+		fmt.Fprintf(&output, "Search query executed: '%s' (%d results, %d unique)\n", input.Query, len(results), len(videoIDs))
+		fmt.Fprintf(&output, "\n**Quota used:** %d units (1 search + playlist creation + %d adds)\n", quotaSpent(ctx), len(videoIDs))
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: output.String()},
+			},
+		}, nil, nil
+	})
+
+	// Tool: ym:playlist-from-likes
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:playlist-from-likes",
+		Description: fmt.Sprintf("Builds a playlist strictly from the user's own liked songs that match a description, by matching its keywords against song titles and artists (and optionally video tags). Does not search YouTube, so it is near-free on quota compared to ym:recommend-playlist. Quota cost: ~%d per 50 liked videos + %d (playlist creation) + %d per song added.", costs.List, costs.Write, costs.Write),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input playlistFromLikesInput) (*mcp.CallToolResult, any, error) {
+		keywords := descriptionKeywords(input.Description)
+		if len(keywords) == 0 {
+			return nil, nil, fmt.Errorf("description must contain at least one keyword to match")
+		}
+		numberOfSongs := min(max(input.NumberOfSongs, 1), 50)
+
+		likedVideos, err := s.ytClient.GetLikedVideos(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
+		}
+
+		// Fetch categories (and tags if requested) to keep music only
+		ids := make([]string, 0, len(likedVideos))
+		for _, v := range likedVideos {
+			ids = append(ids, v.ID)
+		}
+		var metadata map[string]youtube.VideoMetadata
+		if input.MatchTags {
+			metadata, err = s.ytClient.GetVideoMetadata(ctx, ids)
+		} else {
+			var categories map[string]string
+			categories, err = s.ytClient.GetVideoCategories(ctx, ids)
+			metadata = make(map[string]youtube.VideoMetadata, len(categories))
+			for id, categoryID := range categories {
+				metadata[id] = youtube.VideoMetadata{CategoryID: categoryID}
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get video metadata: %w", err)
+		}
+
+		// Score liked songs against the keywords; likes are newest first, so
+		// a stable sort keeps recent likes ahead among equal scores
+		type match struct {
+			video youtube.Video
+			score int
+		}
+		var matches []match
+		likedSongs := 0
+		musicID := s.ytClient.ResolveMusicCategoryID(ctx)
+		for _, v := range likedVideos {
+			meta, ok := metadata[v.ID]
+			if !ok || meta.CategoryID != musicID {
+				continue
+			}
+			likedSongs++
+
+			score := keywordScore(keywords, append([]string{v.Title, v.ChannelTitle}, meta.Tags...)...)
+			if score > 0 {
+				matches = append(matches, match{video: v, score: score})
+			}
+		}
+		slices.SortStableFunc(matches, func(a, b match) int {
+			return cmp.Compare(b.score, a.score)
+		})
+
+		if len(matches) == 0 {
+			return nil, nil, fmt.Errorf("none of your %d liked songs match '%s'", likedSongs, strings.Join(keywords, ", "))
+		}
+		matches = matches[:min(len(matches), numberOfSongs)]
+
+		videoIDs := make([]string, 0, len(matches))
+		for _, m := range matches {
+			videoIDs = append(videoIDs, m.video.ID)
+		}
+
+		title := input.Title
+		if strings.TrimSpace(title) == "" {
+			title = input.Description
+		}
+
+		if err := s.checkQuotaBudget(ctx, fmt.Sprintf("a playlist of %d songs", len(videoIDs)), costs.Write+len(videoIDs)*costs.Write); err != nil {
+			return nil, nil, err
+		}
+
+		// Create playlist
+		description := fmt.Sprintf("Liked songs matching '%s'", input.Description)
+		playlist, err := s.ytClient.CreatePlaylist(ctx, s.prefixedTitle(title), description, input.PrivacyStatus)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
+		}
+
+		// Add videos to playlist
+		added, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add videos to playlist: %w", err)
+		}
+
+		// Build response
+		var output strings.Builder
+		fmt.Fprintf(&output, "# Playlist Created: %s\n\n", playlist.Title)
+		fmt.Fprintf(&output, "**YouTube Music URL:** %s\n\n", playlistURL(playlist.ID))
+		fmt.Fprintf(&output, "**Songs added:** %d of %d requested\n\n", added, numberOfSongs)
+		fmt.Fprintf(&output, "**Keywords matched:** %s (against %d liked songs)\n\n", strings.Join(keywords, ", "), likedSongs)
+		output.WriteString("Songs:\n")
+		for _, m := range matches {
+			fmt.Fprintf(&output, "- %s - %s\n", m.video.Title, m.video.ChannelTitle)
+		}
+		fmt.Fprintf(&output, "\n**Quota used:** %d units (%d metadata lookups + playlist creation + %d adds)\n", quotaSpent(ctx), (len(ids)+49)/50, len(videoIDs))
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: output.String()},
+			},
+		}, nil, nil
+	})
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreatePlaylistFromSearchRefusesOverBudget(t *testing.T) {
+	var calls atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("/youtube/v3/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, map[string]any{"items": []any{}})
+	})
+	// A search and a playlist of 10 songs cost ~650 units
+	s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": "500"}), api)
+
+	result := callTool(t, s, "ym:create-playlist-from-search", map[string]any{"query": "daft punk", "numberOfSongs": 10})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "MAX_QUOTA_PER_CALL") {
+		t.Fatalf("result %q, want the playlist refused over MAX_QUOTA_PER_CALL", text)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("%d API calls were sent, want none", n)
+	}
+}
+
+func TestPlaylistFromLikesRefusesOverBudget(t *testing.T) {
+	var writes atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":             "UCme",
+			"contentDetails": map[string]any{"relatedPlaylists": map[string]any{"likes": "LLme"}},
+		}}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, playlistItemsResponse("song0000001", "song0000002", "song0000003"))
+	})
+	api.HandleFunc("GET /youtube/v3/videoCategories", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{"id": "10", "snippet": map[string]any{"title": "Music"}}}})
+	})
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, id := range r.URL.Query()["id"] {
+			items = append(items, map[string]any{"id": id, "snippet": map[string]any{"categoryId": "10"}})
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	api.HandleFunc("POST /youtube/v3/", func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		writeJSON(w, map[string]any{"id": "PLnew"})
+	})
+	// A playlist of the three matching songs costs ~200 units
+	s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": "150"}), api)
+
+	result := callTool(t, s, "ym:playlist-from-likes", map[string]any{"description": "song0000001 song0000002 song0000003", "numberOfSongs": 10})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "a playlist of 3 songs") || !strings.Contains(text, "MAX_QUOTA_PER_CALL") {
+		t.Fatalf("result %q, want the playlist refused over MAX_QUOTA_PER_CALL", text)
+	}
+	if n := writes.Load(); n != 0 {
+		t.Errorf("%d writes were sent, want none", n)
+	}
+}
+
+func TestCreatePlaylistFromSearchSkipIfExists(t *testing.T) {
+	library := newLibraryAPI()
+	var searches atomic.Int32
+	api := http.NewServeMux()
+	api.Handle("/", library.handler())
+	api.HandleFunc("GET /youtube/v3/search", func(w http.ResponseWriter, r *http.Request) {
+		searches.Add(1)
+		var items []any
+		for _, videoID := range []string{"song0000001", "song0000002"} {
+			items = append(items, map[string]any{
+				"id":      map[string]any{"kind": "youtube#video", "videoId": videoID},
+				"snippet": map[string]any{"title": "Song " + videoID, "channelTitle": "Daft Punk"},
+			})
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+	args := map[string]any{"query": "daft punk", "numberOfSongs": 2, "title": "Road trip", "skipIfExists": true}
+
+	// No playlist with the title yet: a new one is created
+	result := callTool(t, s, "ym:create-playlist-from-search", args)
+	text := resultText(result)
+	if result.IsError || !strings.Contains(text, "# Playlist Created:") || !strings.Contains(text, "a new one was created") {
+		t.Fatalf("first call: %s", text)
+	}
+	if len(library.order) != 1 || searches.Load() != 1 {
+		t.Fatalf("first call created %d playlists with %d searches, want 1 and 1", len(library.order), searches.Load())
+	}
+
+	// A retry finds it and neither searches nor creates again
+	result = callTool(t, s, "ym:create-playlist-from-search", args)
+	text = resultText(result)
+	if result.IsError || !strings.Contains(text, "# Playlist Already Exists: "+defaultPlaylistPrefix+" Road trip") || !strings.Contains(text, "(2 songs)") {
+		t.Errorf("retry: %s", text)
+	}
+	if len(library.order) != 1 || searches.Load() != 1 {
+		t.Errorf("retry created %d playlists with %d searches in total, want 1 and 1", len(library.order), searches.Load())
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Input and output types for quota tools
+
+type quotaUsageInput struct{}
+
+type quotaUsageOutput struct {
+	Used        int `json:"used" jsonschema:"Estimated quota units used today by the calling user"`
+	ProjectUsed int `json:"projectUsed" jsonschema:"Estimated quota units used today by all users of this server, who share the Google project's quota"`
+	Limit       int `json:"limit" jsonschema:"Daily quota limit of the Google project"`
+	Remaining   int `json:"remaining" jsonschema:"Estimated quota units remaining today for all users together"`
+}
+
+type quotaCostsInput struct{}
+
+type quotaCostsOutput struct {
+	Costs      []quotaCostOutput `json:"costs" jsonschema:"Quota cost of each kind of YouTube API operation"`
+	DailyLimit int               `json:"dailyLimit" jsonschema:"Daily quota limit"`
+}
+
+type quotaCostOutput struct {
+	Operation   string `json:"operation" jsonschema:"Kind of operation: list, search or write"`
+	Units       int    `json:"units" jsonschema:"Quota units one such API call costs"`
+	Description string `json:"description" jsonschema:"Which API calls are of this kind"`
+}
+
+// registerQuotaTools registers the quota MCP tools
+func (s *Server) registerQuotaTools() {
+	// Tool: ym:quota-usage
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:quota-usage",
+		Description: "Reports the calling user's estimated YouTube Data API quota usage for today (resets at midnight Pacific Time), and that of all users of this server, who share one Google project quota. Estimates are tracked by this server and exclude usage from other apps sharing the Google project. Quota cost: 0 units.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input quotaUsageInput) (*mcp.CallToolResult, *quotaUsageOutput, error) {
+		used, _ := s.ytClient.QuotaUsage(ctx)
+		projectUsed, limit := s.ytClient.TotalQuotaUsage()
+		out := &quotaUsageOutput{
+			Used:        used,
+			ProjectUsed: projectUsed,
+			Limit:       limit,
+			Remaining:   max(limit-projectUsed, 0),
+		}
+
+		text := fmt.Sprintf("Estimated YouTube API quota used today: ~%s/%s units by all users (%s remaining), ~%s of them by you.",
+			formatThousands(out.ProjectUsed), formatThousands(out.Limit), formatThousands(out.Remaining), formatThousands(out.Used))
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, out, nil
+	})
+	// Tool: ym:get-quota-costs
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-quota-costs",
+		Description: "Returns the quota cost of each kind of YouTube API operation (list, search, write) as this server accounts it, so an agent can plan and compare operations before running them. Tool descriptions quote the same costs. Quota cost: 0 units.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input quotaCostsInput) (*mcp.CallToolResult, *quotaCostsOutput, error) {
+		costs := quotaCosts(s.cfg)
+		out := &quotaCostsOutput{
+			Costs: []quotaCostOutput{
+				{"list", costs.List, "Any list or lookup, per page of up to 50 results: videos, playlists, playlist items, likes, subscriptions, channels"},
+				{"search", costs.Search, "A search for videos, up to 50 results"},
+				{"write", costs.Write, "Any insert, update or delete: creating or deleting a playlist, adding, moving or removing one playlist item"},
+			},
+			DailyLimit: cmp.Or(s.cfg.DailyQuota, youtube.DefaultDailyQuota),
+		}
+
+		var text strings.Builder
+		text.WriteString("YouTube API quota costs:\n")
+		for _, cost := range out.Costs {
+			fmt.Fprintf(&text, "- %s: %d units (%s)\n", cost.Operation, cost.Units, cost.Description)
+		}
+		fmt.Fprintf(&text, "\nDaily limit: %s units.\n", formatThousands(out.DailyLimit))
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text.String()},
+			},
+		}, out, nil
+	})
+}
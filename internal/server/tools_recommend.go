@@ -1,12 +1,15 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"math"
-	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -17,237 +20,794 @@ var instructionalWords = []string{
 	"similar to what", "songs from", "mix of",
 }
 
+// leadingFillers are phrases that introduce artists in a description but only
+// dilute a search query, e.g. "songs by Daft Punk".
+var leadingFillers = []string{"songs by ", "music by ", "tracks by ", "stuff like ", "something like ", "artists like "}
+
+// subscriptionCues are description phrases asking for music from the artists
+// the user follows, which seed the searches from subscriptions instead.
+var subscriptionCues = []string{
+	"my artists", "artists i follow", "artists i'm subscribed to", "artists i am subscribed to",
+	"channels i follow", "my subscriptions", "i'm subscribed to", "i subscribe to",
+}
+
+// wantsSubscriptionSeeds reports whether a description asks for the artists the user follows.
+func wantsSubscriptionSeeds(description string) bool {
+	lower := strings.ToLower(strings.ReplaceAll(description, "’", "'"))
+	for _, cue := range subscriptionCues {
+		if strings.Contains(lower, cue) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptionQueries returns up to limit subscription channel titles to search
+// for, ordered by the artist ranking so the user's favorites come first.
+func subscriptionQueries(artists []artistScore, subscriptions []youtube.Subscription, limit int) []string {
+	subscribed := make(map[string]struct{}, len(subscriptions))
+	for _, sub := range subscriptions {
+		if sub.Title != "" {
+			subscribed[sub.Title] = struct{}{}
+		}
+	}
+
+	var queries []string
+	for _, artist := range artists {
+		if len(queries) >= limit {
+			break
+		}
+		if _, ok := subscribed[artist.name]; ok {
+			queries = append(queries, truncateTerm(artist.name))
+		}
+	}
+	return queries
+}
+
 // splitDescriptionIntoTerms splits a description into individual search-friendly terms.
+// It splits on commas, semicolons, newlines, sentence-ending periods and the word
+// "and", except inside double quotes or parentheses. Quoted phrases become exact-phrase
+// terms, "artist - song" and "artist: song" stay one term, and instructional
+// phrases are dropped.
 func splitDescriptionIntoTerms(description string) []string {
-	// Split by commas, periods, semicolons, colons, and the word "and"
-	re := regexp.MustCompile(`[,;.:\n]+|\band\b`)
-	parts := re.Split(description, -1)
-
 	var terms []string
-	for _, part := range parts {
-		term := strings.TrimSpace(part)
+	var current strings.Builder
+
+	// flush ends the current term, filtering and normalizing it
+	flush := func() {
+		term := strings.TrimSpace(current.String())
+		current.Reset()
 		if term == "" {
-			continue
+			return
 		}
 
 		// Skip instructional/meta phrases
 		lower := strings.ToLower(term)
-		isInstructional := false
 		for _, word := range instructionalWords {
 			if strings.Contains(lower, word) {
-				isInstructional = true
-				break
+				return
 			}
 		}
-		if isInstructional {
-			continue
+		for _, filler := range leadingFillers {
+			if strings.HasPrefix(lower, filler) {
+				term = strings.TrimSpace(term[len(filler):])
+				break
+			}
 		}
 
-		// Cap at 80 characters
-		if len(term) > 80 {
-			term = term[:80]
+		// "artist: song" searches like "artist - song"
+		if artist, song, ok := strings.Cut(term, ":"); ok && strings.TrimSpace(artist) != "" && strings.TrimSpace(song) != "" {
+			term = strings.TrimSpace(artist) + " - " + strings.TrimSpace(song)
 		}
 
-		terms = append(terms, term)
+		terms = append(terms, truncateTerm(term))
+	}
+
+	runes := []rune(description)
+	depth := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '"':
+			// A quoted phrase is always its own exact-phrase term
+			end := slices.Index(runes[i+1:], '"')
+			if end < 0 {
+				continue // unbalanced quote: ignore it
+			}
+			flush()
+			if phrase := strings.TrimSpace(string(runes[i+1 : i+1+end])); phrase != "" {
+				terms = append(terms, truncateTerm(`"`+phrase+`"`))
+			}
+			i += end + 1
+		case r == '(':
+			depth++
+			current.WriteRune(r)
+		case r == ')':
+			depth = max(depth-1, 0)
+			current.WriteRune(r)
+		case depth > 0:
+			current.WriteRune(r)
+		case r == ',' || r == ';' || r == '\n':
+			flush()
+		case r == '.' && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) && !isAbbreviation(current.String()):
+			flush()
+		case isAndAt(runes, i):
+			flush()
+			i += len("and") - 1
+		default:
+			current.WriteRune(r)
+		}
 	}
+	flush()
 
 	return terms
 }
 
+// isAndAt reports whether the standalone word "and" starts at runes[i].
+func isAndAt(runes []rune, i int) bool {
+	if i+3 > len(runes) || !strings.EqualFold(string(runes[i:i+3]), "and") {
+		return false
+	}
+	isWordRune := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	if i > 0 && isWordRune(runes[i-1]) {
+		return false
+	}
+	return i+3 == len(runes) || !isWordRune(runes[i+3])
+}
+
+// isAbbreviation reports whether text ends in a short word such as "St" or "Mr",
+// whose following period abbreviates rather than ends a sentence ("St. Vincent").
+func isAbbreviation(text string) bool {
+	fields := strings.Fields(text)
+	return len(fields) > 0 && len([]rune(fields[len(fields)-1])) <= 2
+}
+
+// truncateTerm caps a search term at 80 characters.
+func truncateTerm(term string) string {
+	if len(term) > 80 {
+		return term[:80]
+	}
+	return term
+}
+
+// maxRecommendedSongs is the most songs recommend-playlist adds in one call.
+const maxRecommendedSongs = 50
+
+// Search breadth limits of recommend-playlist.
+const (
+	defaultResultsPerQuery = 5
+	maxResultsPerQuery     = 25
+	defaultMaxQueries      = 10 // cap of the default of one query per 3 songs
+	maxQueriesLimit        = 20
+)
+
+// estimateRecommendCost estimates the quota a recommend-playlist call will
+// spend: the searches, the optional duration lookups and, unless it is a dry
+// run, creating the playlist and adding the songs. Library reads cost a few
+// units at most and are left out.
+func estimateRecommendCost(costs youtube.QuotaCosts, searches int, durationLookups bool, songs int, dryRun bool) int {
+	cost := searches * costs.Search
+	if durationLookups {
+		cost += searches * costs.List
+	}
+	if !dryRun {
+		cost += costs.Write + songs*costs.Write
+	}
+	return cost
+}
+
+// knownVideoIDs returns the IDs of videos already in the user's library: liked videos
+// plus songs in playlists previously created by this tool.
+// Quota cost: ~1 unit per 50 items in each playlist created by this server
+// (liked videos are normally cached).
+func (s *Server) knownVideoIDs(ctx context.Context, playlists []youtube.Playlist) map[string]struct{} {
+	known, err := s.ytClient.LikedVideoSet(ctx)
+	if err != nil {
+		s.logger.Warn("failed to get liked video IDs", "error", err)
+		youtube.NoteFallback(ctx, "could not read liked videos for known songs; liked songs may be recommended again")
+		known = make(map[string]struct{})
+	}
+
+	for _, pl := range playlists {
+		if !s.isOwnPlaylist(pl.Title) {
+			continue
+		}
+		items, err := s.ytClient.GetPlaylistItems(ctx, pl.ID)
+		if err != nil {
+			// Log error but continue
+			s.logger.Warn("failed to fetch items for playlist", "playlist", pl.Title, "error", err)
+			youtube.NoteFallback(ctx, "could not read playlist '%s' for known songs; its songs may be recommended again", pl.Title)
+			continue
+		}
+		for _, item := range items {
+			known[item.ID] = struct{}{}
+		}
+	}
+
+	return known
+}
+
 // Input types for recommendation tools
 
 type recommendPlaylistInput struct {
-	NumberOfSongs int    `json:"numberOfSongs" jsonschema:"Number of songs to find and add to the playlist (1-50)"`
-	Description   string `json:"description,omitempty" jsonschema:"What kind of music to find (genres/moods/artists/era). If empty recommendations are based purely on taste analysis."`
+	NumberOfSongs      int      `json:"numberOfSongs" jsonschema:"Number of songs to find and add to the playlist (1-50)"`
+	Description        string   `json:"description,omitempty" jsonschema:"What kind of music to find (genres/moods/artists/era). If empty recommendations are based purely on taste analysis."`
+	DedupStrategy      string   `json:"dedupStrategy,omitempty" jsonschema:"How to deduplicate results: 'id' collapses identical videos only, 'title' also collapses different uploads of the same song (same title and channel). Defaults to the server configuration."`
+	Categories         []string `json:"categories,omitempty" jsonschema:"Video categories to search, by name (music/comedy/entertainment/gaming/film/...) or ID. Defaults to music only. Each extra category runs every query again at 100 quota units per search."`
+	ExcludeKnown       *bool    `json:"excludeKnown,omitempty" jsonschema:"If true (default) skip songs the user already liked or that are already in playlists previously created by this tool"`
+	WeightByRecency    bool     `json:"weightByRecency,omitempty" jsonschema:"If true recently liked artists count more when choosing top artists to search for"`
+	MinDurationSeconds int64    `json:"minDurationSeconds,omitempty" jsonschema:"Skip songs shorter than this many seconds (adds 1 quota unit per search for the duration lookup)"`
+	MaxDurationSeconds int64    `json:"maxDurationSeconds,omitempty" jsonschema:"Skip songs longer than this many seconds, e.g. to keep out long mixes (adds 1 quota unit per search for the duration lookup)"`
+	DryRun             bool     `json:"dryRun,omitempty" jsonschema:"If true run the searches but create nothing: return the candidate songs and what creating the playlist would cost, so the user can approve first"`
+	ResultsPerQuery    int64    `json:"resultsPerQuery,omitempty" jsonschema:"Search results considered per query (1-25, default 5). More gives each query more variety at no extra quota"`
+	MaxQueries         int      `json:"maxQueries,omitempty" jsonschema:"Most searches to run per category (1-20, default one per 3 songs, at most 10). Each search costs 100 quota units; more queries give more diverse playlists"`
+	TargetPlaylistID   string   `json:"targetPlaylistId,omitempty" jsonschema:"ID or URL of an existing playlist to append the songs to instead of creating a new one. Songs already in it are skipped"`
+	MaxLiked           int      `json:"maxLiked,omitempty" jsonschema:"Only base the taste on this many most recently liked videos: faster and cheaper on big libraries but less complete. -1 analyzes all. Defaults to the server's MAX_TASTE_VIDEOS (all unless set)"`
+	VerifyBeforeAdd    *bool    `json:"verifyBeforeAdd,omitempty" jsonschema:"If true (default) look up every search's results (1 quota unit per search) and drop deleted, private, unprocessed or region-blocked videos that would not play"`
+	// Cues like "artists I follow" in the description set this too
+	SeedFromSubscriptions bool `json:"seedFromSubscriptions,omitempty" jsonschema:"If true search for the artists the user is subscribed to (favorites first) instead of splitting the description into queries. Implied when the description mentions 'my artists' or 'artists I follow'"`
+}
+
+type createRadioInput struct {
+	VideoID       string `json:"videoId" jsonschema:"Seed song: a video ID or YouTube/YouTube Music URL"`
+	NumberOfSongs int    `json:"numberOfSongs,omitempty" jsonschema:"Length of the radio playlist including the seed song (2-50, default 25)"`
+	DedupStrategy string `json:"dedupStrategy,omitempty" jsonschema:"How to deduplicate results: 'id' collapses identical videos only, 'title' also collapses different uploads of the same song. Defaults to the server configuration."`
 }
 
 type recommendArtistsInput struct {
 	Description string `json:"description,omitempty" jsonschema:"What kind of artists to recommend (genre preferences/mood/any guidance)"`
+	MaxLiked    int    `json:"maxLiked,omitempty" jsonschema:"Only base the taste on this many most recently liked videos: faster and cheaper on big libraries but less complete. -1 analyzes all. Defaults to the server's MAX_TASTE_VIDEOS (all unless set)"`
 }
 
 type recommendAlbumsInput struct {
 	Description string `json:"description,omitempty" jsonschema:"What kind of albums to recommend (genre preferences/mood/era/any guidance)"`
+	MaxLiked    int    `json:"maxLiked,omitempty" jsonschema:"Only base the taste on this many most recently liked videos: faster and cheaper on big libraries but less complete. -1 analyzes all. Defaults to the server's MAX_TASTE_VIDEOS (all unless set)"`
 }
 
-// registerRecommendTools registers the 3 recommendation MCP tools
+// defaultRadioSongs is the radio length when create-radio is not given one.
+const defaultRadioSongs = 25
+
+// maxRadioGenres caps the genre searches create-radio runs besides the artist search.
+const maxRadioGenres = 2
+
+// registerRecommendTools registers the 4 recommendation MCP tools
 func (s *Server) registerRecommendTools() {
-	// Tool 1: ym:recommend-playlist
-	mcp.AddTool(s.mcpServer, &mcp.Tool{
-		Name:        "ym:recommend-playlist",
-		Description: "Creates a playlist with recommended music based on the user's taste and an optional description. Gathers taste data, searches for songs, creates a playlist, and adds songs in one call. WARNING: Each search costs 100 quota units. This tool will use multiple searches to find diverse songs. Quota cost: ~200-500 units depending on number of songs.",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input recommendPlaylistInput) (*mcp.CallToolResult, any, error) {
-		// Gather taste context (uses full library - no caps)
-		likedVideos, err := s.ytClient.GetLikedVideos(ctx)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
-		}
+	costs := quotaCosts(s.cfg)
+
+	// Creating playlists needs write access, so skip it in read-only mode
+	if !s.cfg.ReadOnly {
+		// Tool 1: ym:recommend-playlist
+		mcp.AddTool(s.mcpServer, &mcp.Tool{
+			Name:        "ym:recommend-playlist",
+			Description: fmt.Sprintf("Creates a playlist with recommended music based on the user's taste and an optional description. Gathers taste data, searches for songs, creates a playlist, and adds songs in one call. WARNING: Each search costs %d quota units. This tool will use multiple searches to find diverse songs. Skips songs already in the user's library unless excludeKnown is false. Searches the Music category unless other categories are given; each extra category multiplies the search cost. Each search's results are looked up (%d per search) to drop videos that would not play, which also covers the min/max duration filter; set verifyBeforeAdd to false to skip it. Use dryRun to preview the songs before spending the playlist creation quota (%d + %d per song). Set targetPlaylistId to grow one existing playlist instead of creating a new one each time. Set seedFromSubscriptions (implied by descriptions like 'more from artists I follow') to search for the user's subscribed artists instead of the description's terms. Quota cost: ~%d units per search plus the playlist writes, depending on number of songs.", costs.Search, costs.List, costs.Write, costs.Write, costs.Search),
+		}, func(ctx context.Context, req *mcp.CallToolRequest, input recommendPlaylistInput) (*mcp.CallToolResult, any, error) {
+			if input.NumberOfSongs < 1 || input.NumberOfSongs > maxRecommendedSongs {
+				return nil, nil, fmt.Errorf("numberOfSongs must be between 1 and %d, got %d", maxRecommendedSongs, input.NumberOfSongs)
+			}
+			dedupStrategy, err := validateDedupStrategy(input.DedupStrategy, s.cfg.DedupStrategy)
+			if err != nil {
+				return nil, nil, err
+			}
+			durations, err := newDurationRange(input.MinDurationSeconds, input.MaxDurationSeconds)
+			if err != nil {
+				return nil, nil, err
+			}
+			verify := input.VerifyBeforeAdd == nil || *input.VerifyBeforeAdd
+			detailLookups := verify || durations.active()
 
-		subscriptions, err := s.ytClient.GetSubscriptions(ctx)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get subscriptions: %w", err)
-		}
+			// Resolve search categories (music only by default)
+			type searchCategory struct {
+				name string
+				id   string
+			}
+			categoryNames := input.Categories
+			if len(categoryNames) == 0 {
+				categoryNames = []string{"music"}
+			}
+			var categories []searchCategory
+			seenCategories := make(map[string]struct{}, len(categoryNames))
+			for _, name := range categoryNames {
+				id, err := youtube.ResolveCategoryID(name)
+				if err != nil {
+					return nil, nil, err
+				}
+				if _, seen := seenCategories[id]; seen {
+					continue
+				}
+				seenCategories[id] = struct{}{}
+				categories = append(categories, searchCategory{name: strings.ToLower(strings.TrimSpace(name)), id: id})
+			}
 
-		playlists, err := s.ytClient.ListPlaylists(ctx)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to list playlists: %w", err)
-		}
+			// Construct search queries
+			resultsPerQuery := cmp.Or(input.ResultsPerQuery, defaultResultsPerQuery)
+			if resultsPerQuery < 1 || resultsPerQuery > maxResultsPerQuery {
+				return nil, nil, fmt.Errorf("resultsPerQuery must be between 1 and %d, got %d", maxResultsPerQuery, resultsPerQuery)
+			}
+			maxQueries := cmp.Or(input.MaxQueries, min(int(math.Ceil(float64(input.NumberOfSongs)/3.0)), defaultMaxQueries))
+			if maxQueries < 1 || maxQueries > maxQueriesLimit {
+				return nil, nil, fmt.Errorf("maxQueries must be between 1 and %d, got %d", maxQueriesLimit, maxQueries)
+			}
 
-		// Build taste summary - extract unique artists/channels
-		artistMap := make(map[string]int)
-		for _, v := range likedVideos {
-			if v.ChannelTitle != "" {
-				artistMap[v.ChannelTitle]++
+			// Refuse before spending anything if the call could exceed the budget or the quota left
+			estimate := estimateRecommendCost(costs, maxQueries*len(categories), detailLookups, input.NumberOfSongs, input.DryRun)
+			if err := s.checkQuotaBudget(ctx, "this recommendation", estimate); err != nil {
+				return nil, nil, fmt.Errorf("%w; ask for fewer songs or categories, or use dryRun", err)
 			}
-		}
-		for _, sub := range subscriptions {
-			if sub.Title != "" {
-				artistMap[sub.Title]++
+
+			// Appending: check the target exists and collect its songs before searching
+			var target *youtube.Playlist
+			var targetIDs map[string]struct{}
+			if input.TargetPlaylistID != "" {
+				target, err = s.ytClient.GetPlaylist(ctx, input.TargetPlaylistID)
+				if err != nil {
+					return nil, nil, playlistReadError(input.TargetPlaylistID, "get target playlist", err)
+				}
+				if target == nil {
+					return nil, nil, playlistReadError(input.TargetPlaylistID, "", youtube.ErrPlaylistNotFound)
+				}
+				items, err := s.ytClient.GetPlaylistItems(ctx, target.ID)
+				if err != nil {
+					return nil, nil, playlistReadError(target.ID, "get target playlist items", err)
+				}
+				targetIDs = make(map[string]struct{}, len(items))
+				for _, item := range items {
+					targetIDs[item.ID] = struct{}{}
+				}
 			}
-		}
 
-		// Get top 10 most frequent artists
-		type artistCount struct {
-			name  string
-			count int
-		}
-		var artists []artistCount
-		for name, count := range artistMap {
-			artists = append(artists, artistCount{name, count})
-		}
+			// Gather taste context (full library unless capped)
+			likedVideos, _, err := s.tasteLikedVideos(ctx, input.MaxLiked)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
+			}
+
+			subscriptions, err := s.ytClient.GetSubscriptions(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get subscriptions: %w", err)
+			}
+
+			playlists, err := s.ytClient.ListPlaylists(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to list playlists: %w", err)
+			}
+
+			// Build taste summary - rank artists/channels and take the top 10 (or one per query)
+			artists := rankArtists(likedVideos, subscriptions, input.WeightByRecency)
+			topArtists := make([]string, 0, max(10, maxQueries))
+			for i := 0; i < len(artists) && i < max(10, maxQueries); i++ {
+				topArtists = append(topArtists, artists[i].name)
+			}
 
-		// Sort by count (simple bubble sort for small data)
-		for i := 0; i < len(artists); i++ {
-			for j := i + 1; j < len(artists); j++ {
-				if artists[j].count > artists[i].count {
-					artists[i], artists[j] = artists[j], artists[i]
+			var searchQueries []string
+			seedFromSubscriptions := input.SeedFromSubscriptions || wantsSubscriptionSeeds(input.Description)
+			if seedFromSubscriptions {
+				searchQueries = subscriptionQueries(artists, subscriptions, maxQueries)
+				if len(searchQueries) < maxQueries {
+					youtube.NoteFallback(ctx, "subscriptions yielded %d of %d search queries; filled the rest with top artists", len(searchQueries), maxQueries)
+				}
+			} else if input.Description != "" {
+				// Extract individual search terms from description
+				terms := splitDescriptionIntoTerms(input.Description)
+				for _, term := range terms {
+					if len(searchQueries) >= maxQueries {
+						break
+					}
+					searchQueries = append(searchQueries, term)
 				}
 			}
-		}
 
-		// Take top 10
-		topArtists := make([]string, 0, 10)
-		for i := 0; i < len(artists) && i < 10; i++ {
-			topArtists = append(topArtists, artists[i].name)
-		}
+			// Fall back to top artists if description yielded insufficient queries
+			if len(searchQueries) < maxQueries {
+				if input.Description != "" && !seedFromSubscriptions {
+					youtube.NoteFallback(ctx, "description yielded %d of %d search queries; filled the rest with top artists", len(searchQueries), maxQueries)
+				}
+				for i := 0; i < len(topArtists) && len(searchQueries) < maxQueries; i++ {
+					if !slices.Contains(searchQueries, topArtists[i]) {
+						searchQueries = append(searchQueries, topArtists[i])
+					}
+				}
+			}
 
-		// Construct search queries
-		maxQueries := min(int(math.Ceil(float64(input.NumberOfSongs)/3.0)), 10)
+			// Build the set of songs already in the user's library
+			excludeKnown := input.ExcludeKnown == nil || *input.ExcludeKnown
+			var knownIDs map[string]struct{}
+			if excludeKnown {
+				knownIDs = s.knownVideoIDs(ctx, playlists)
+			}
 
-		var searchQueries []string
-		if input.Description != "" {
-			// Extract individual search terms from description
-			terms := splitDescriptionIntoTerms(input.Description)
-			for _, term := range terms {
-				if len(searchQueries) >= maxQueries {
-					break
+			// Execute searches and collect video IDs
+			videoIDMap := make(map[string]struct{}) // Deduplication
+			trackMap := make(map[string]struct{})   // Near-duplicate detection (title strategy)
+			nearDuplicates := 0
+			excludedKnown := 0
+			alreadyInTarget := 0
+			outsideDuration := 0
+			unplayable := 0
+			detailLookupsRun := 0
+			var videoIDs []string
+			var candidates []youtube.SearchResult // kept in step with videoIDs for dry runs
+			var searchSummary strings.Builder
+
+			if seedFromSubscriptions {
+				searchSummary.WriteString("Search queries executed (seeded from your subscriptions):\n")
+			} else {
+				searchSummary.WriteString("Search queries executed:\n")
+			}
+			searchesRun := 0
+			categoryContribution := make(map[string]int, len(categories))
+		searchLoop:
+			for _, query := range searchQueries {
+				for _, category := range categories {
+					results, err := s.ytClient.SearchVideosInCategory(ctx, query, category.id, resultsPerQuery)
+					searchesRun++
+
+					label := fmt.Sprintf("'%s'", query)
+					if len(categories) > 1 {
+						label = fmt.Sprintf("'%s' [%s]", query, category.name)
+					}
+					if err != nil {
+						// Log error but continue with other searches
+						s.logger.Warn("search failed", "query", query, "category", category.name, "error", err)
+						youtube.NoteFallback(ctx, "search '%s' [%s] failed; continued with other queries", query, category.name)
+						fmt.Fprintf(&searchSummary, "- %s (failed)\n", label)
+						continue
+					}
+
+					fmt.Fprintf(&searchSummary, "- %s (%d results)\n", label, len(results))
+
+					// Look up this search's results for the duration filter and the playability check
+					var details map[string]youtube.VideoDetail
+					verified := false
+					if detailLookups && len(results) > 0 {
+						ids := make([]string, 0, len(results))
+						for _, result := range results {
+							ids = append(ids, result.VideoID)
+						}
+						details, err = s.videoDurations(ctx, ids)
+						detailLookupsRun++
+						switch {
+						case err != nil && durations.active():
+							s.logger.Warn("duration lookup failed", "query", query, "error", err)
+							youtube.NoteFallback(ctx, "duration lookup for '%s' [%s] failed; skipped its results", query, category.name)
+							continue
+						case err != nil:
+							s.logger.Warn("playability lookup failed", "query", query, "error", err)
+							youtube.NoteFallback(ctx, "playability check for '%s' [%s] failed; its results were not verified", query, category.name)
+						default:
+							verified = verify
+						}
+					}
+
+					for _, result := range results {
+						if _, exists := videoIDMap[result.VideoID]; exists {
+							continue
+						}
+						videoIDMap[result.VideoID] = struct{}{}
+
+						// Skip songs already in the playlist being appended to
+						if _, present := targetIDs[result.VideoID]; present {
+							alreadyInTarget++
+							continue
+						}
+
+						// Skip songs the user already has
+						if _, known := knownIDs[result.VideoID]; known {
+							excludedKnown++
+							continue
+						}
+
+						// Skip videos that are gone or would not play
+						if verified {
+							detail, found := details[result.VideoID]
+							if !found || detail.UnplayableReason(s.cfg.SearchRegion) != "" {
+								unplayable++
+								continue
+							}
+						}
+
+						// Skip songs outside the requested duration range
+						if durations.active() && !durations.contains(details[result.VideoID].DurationSeconds) {
+							outsideDuration++
+							continue
+						}
+
+						// Collapse different uploads of the same song
+						if dedupStrategy == dedupByTitle {
+							key := trackKey(result.Title, result.ChannelTitle)
+							if _, exists := trackMap[key]; exists {
+								nearDuplicates++
+								continue
+							}
+							trackMap[key] = struct{}{}
+						}
+
+						videoIDs = append(videoIDs, result.VideoID)
+						candidates = append(candidates, result)
+						categoryContribution[category.name]++
+
+						// Stop if we have enough songs
+						if len(videoIDs) >= input.NumberOfSongs {
+							break searchLoop
+						}
+					}
 				}
-				searchQueries = append(searchQueries, term)
 			}
-		}
 
-		// Fall back to top artists if description yielded insufficient queries
-		if len(searchQueries) < maxQueries {
-			for i := 0; i < len(topArtists) && len(searchQueries) < maxQueries; i++ {
-				searchQueries = append(searchQueries, topArtists[i])
+			// Truncate to requested number
+			if len(videoIDs) > input.NumberOfSongs {
+				videoIDs = videoIDs[:input.NumberOfSongs]
+				candidates = candidates[:input.NumberOfSongs]
 			}
-		}
 
-		// Execute searches and collect video IDs
-		videoIDMap := make(map[string]struct{}) // Deduplication
-		var videoIDs []string
-		var searchSummary strings.Builder
+			if len(videoIDs) == 0 {
+				if outsideDuration > 0 {
+					return nil, nil, fmt.Errorf("no videos found for the given criteria (%d results outside the requested duration range)", outsideDuration)
+				}
+				if unplayable > 0 {
+					return nil, nil, fmt.Errorf("no playable videos found for the given criteria (%d results were deleted, private or blocked)", unplayable)
+				}
+				if excludedKnown > 0 || alreadyInTarget > 0 {
+					return nil, nil, fmt.Errorf("no new videos found for the given criteria (%d results excluded as already in your library, %d already in the target playlist)", excludedKnown, alreadyInTarget)
+				}
+				return nil, nil, fmt.Errorf("no videos found for the given criteria")
+			}
 
-		searchSummary.WriteString("Search queries executed:\n")
-		for _, query := range searchQueries {
-			results, err := s.ytClient.SearchVideos(ctx, query, 5)
-			if err != nil {
-				// Log error but continue with other searches
-				s.logger.Warn("search failed", "query", query, "error", err)
-				fmt.Fprintf(&searchSummary, "- '%s' (failed)\n", query)
-				continue
+			// Generate playlist title
+			playlistTitle := s.prefixedTitle("Recommended Mix")
+			if input.Description != "" {
+				// Use first few words of description
+				words := strings.Fields(input.Description)
+				titleWords := words
+				if len(words) > 4 {
+					titleWords = words[:4]
+				}
+				playlistTitle = s.prefixedTitle(strings.Join(titleWords, " "))
 			}
 
-			fmt.Fprintf(&searchSummary, "- '%s' (%d results)\n", query, len(results))
+			// Dry run: show what would be added and stop before any write
+			if input.DryRun {
+				var output strings.Builder
+				fmt.Fprintf(&output, "# DRY RUN - Playlist Preview (nothing was created)\n\n")
+				if target != nil {
+					fmt.Fprintf(&output, "**Would append to:** %s (%d songs already in it were skipped)\n\n", target.Title, alreadyInTarget)
+				} else {
+					fmt.Fprintf(&output, "**Would create:** %s (%s)\n\n", playlistTitle, s.cfg.DefaultPlaylistPrivacy)
+				}
+				fmt.Fprintf(&output, "**Candidate songs:** %d of %d requested\n", len(candidates), input.NumberOfSongs)
+				if unplayable > 0 {
+					fmt.Fprintf(&output, "**Unplayable videos dropped:** %d\n", unplayable)
+				}
+				for i, c := range candidates {
+					fmt.Fprintf(&output, "%d. %s - %s (%s)\n", i+1, c.Title, c.ChannelTitle, c.VideoID)
+				}
+				output.WriteString("\n")
+				output.WriteString(searchSummary.String())
+				fmt.Fprintf(&output, "\n**Quota used by this preview:** %d units\n", quotaSpent(ctx))
+				if target != nil {
+					fmt.Fprintf(&output, "**Quota to append them:** ~%d units (%d x %d adds)\n\nRun again without dryRun to add the songs; the searches run again, so results may differ slightly.\n", len(videoIDs)*costs.Write, len(videoIDs), costs.Write)
+				} else {
+					fmt.Fprintf(&output, "**Quota to create it:** ~%d units (%d playlist creation + %d x %d adds)\n\nRun again without dryRun to create the playlist; the searches run again, so results may differ slightly.\n", costs.Write+len(videoIDs)*costs.Write, costs.Write, len(videoIDs), costs.Write)
+				}
 
-			for _, result := range results {
-				if _, exists := videoIDMap[result.VideoID]; !exists {
-					videoIDMap[result.VideoID] = struct{}{}
-					videoIDs = append(videoIDs, result.VideoID)
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: output.String()},
+					},
+				}, nil, nil
+			}
 
-					// Stop if we have enough songs
-					if len(videoIDs) >= input.NumberOfSongs {
-						break
-					}
+			// Create the playlist, unless appending to an existing one
+			playlist := target
+			if playlist == nil {
+				playlist, err = s.ytClient.CreatePlaylist(ctx, playlistTitle, input.Description, "")
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
 				}
 			}
 
-			if len(videoIDs) >= input.NumberOfSongs {
-				break
+			// Add videos to playlist
+			added, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to add videos to playlist: %w", err)
 			}
-		}
 
-		// Truncate to requested number
-		if len(videoIDs) > input.NumberOfSongs {
-			videoIDs = videoIDs[:input.NumberOfSongs]
-		}
+			// Build response
+			var output strings.Builder
+			if target != nil {
+				fmt.Fprintf(&output, "# Songs Appended to Playlist: %s\n\n", playlist.Title)
+			} else {
+				fmt.Fprintf(&output, "# Playlist Created: %s\n\n", playlist.Title)
+			}
+			fmt.Fprintf(&output, "**YouTube Music URL:** %s\n\n", playlistURL(playlist.ID))
+			fmt.Fprintf(&output, "**Songs added:** %d of %d requested\n\n", added, input.NumberOfSongs)
+			if target != nil {
+				fmt.Fprintf(&output, "**Already in the playlist:** %d search results skipped\n\n", alreadyInTarget)
+			}
+			fmt.Fprintf(&output, "**Taste context:** %d liked songs, %d subscriptions, %d playlists analyzed\n\n", len(likedVideos), len(subscriptions), len(playlists))
+			fmt.Fprintf(&output, "**Top artists in your taste:** %s\n\n", strings.Join(topArtists[:min(5, len(topArtists))], ", "))
+			if excludeKnown {
+				fmt.Fprintf(&output, "**Already-known songs excluded:** %d (liked or in previous %s playlists)\n\n", excludedKnown, s.playlistPrefix())
+				if len(videoIDs) < input.NumberOfSongs && excludedKnown > 0 {
+					fmt.Fprintf(&output, "Only %d new songs were found because %d search results were already in your library.\n\n", len(videoIDs), excludedKnown)
+				}
+			}
+			if dedupStrategy == dedupByTitle {
+				fmt.Fprintf(&output, "**Near-duplicates collapsed:** %d (same title and channel, different upload)\n\n", nearDuplicates)
+			}
+			if durations.active() {
+				fmt.Fprintf(&output, "**Outside duration range:** %d songs skipped\n\n", outsideDuration)
+			}
+			if verify {
+				fmt.Fprintf(&output, "**Unplayable videos dropped:** %d (deleted, private, unprocessed or region-blocked)\n\n", unplayable)
+			}
+			if len(categories) > 1 {
+				output.WriteString("**Per-category contribution:**\n")
+				for _, category := range categories {
+					fmt.Fprintf(&output, "- %s: %d songs\n", category.name, categoryContribution[category.name])
+				}
+				output.WriteString("\n")
+			}
+			output.WriteString(searchSummary.String())
+			fmt.Fprintf(&output, "\n**Quota used:** %d units (%d searches + ", quotaSpent(ctx), searchesRun)
+			if target != nil {
+				output.WriteString("target playlist lookup")
+			} else {
+				output.WriteString("playlist creation")
+			}
+			fmt.Fprintf(&output, " + %d adds", added)
+			if detailLookupsRun > 0 {
+				fmt.Fprintf(&output, " + %d video lookups", detailLookupsRun)
+			}
+			output.WriteString(")\n")
+			if len(categories) > 1 {
+				fmt.Fprintf(&output, "Searching %d categories multiplies search cost: each query ran once per category.\n", len(categories))
+			}
 
-		if len(videoIDs) == 0 {
-			return nil, nil, fmt.Errorf("no videos found for the given criteria")
-		}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: output.String()},
+				},
+			}, nil, nil
+		})
+
+		// Tool 2: ym:create-radio
+		mcp.AddTool(s.mcpServer, &mcp.Tool{
+			Name:        "ym:create-radio",
+			Description: fmt.Sprintf("Starts a radio from a seed song, like YouTube Music's radio: looks up the seed, searches for its artist and up to %d of its genres, and creates a '%s Radio: <title>' playlist that starts with the seed and is filled with the results, interleaved, to the requested length. WARNING: Each search costs %d quota units, so this runs up to %d searches (~%d units). Quota cost: ~%d (seed lookup) + %d per search + %d playlist creation + %d per song added; ~%d units for the default %d songs.", maxRadioGenres, s.playlistPrefix(), costs.Search, 1+maxRadioGenres, costs.Search*(1+maxRadioGenres), costs.List*2, costs.Search, costs.Write, costs.Write, estimateRecommendCost(costs, 1+maxRadioGenres, false, defaultRadioSongs, false), defaultRadioSongs),
+		}, func(ctx context.Context, req *mcp.CallToolRequest, input createRadioInput) (*mcp.CallToolResult, any, error) {
+			numberOfSongs := cmp.Or(input.NumberOfSongs, defaultRadioSongs)
+			if numberOfSongs < 2 || numberOfSongs > maxRecommendedSongs {
+				return nil, nil, fmt.Errorf("numberOfSongs must be between 2 and %d, got %d", maxRecommendedSongs, numberOfSongs)
+			}
+			dedupStrategy, err := validateDedupStrategy(input.DedupStrategy, s.cfg.DedupStrategy)
+			if err != nil {
+				return nil, nil, err
+			}
 
-		// Generate playlist title
-		playlistTitle := "[YM-MCP] Recommended Mix"
-		if input.Description != "" {
-			// Use first few words of description
-			words := strings.Fields(input.Description)
-			titleWords := words
-			if len(words) > 4 {
-				titleWords = words[:4]
+			// Refuse before spending anything if the call could exceed the budget or the quota left
+			estimate := estimateRecommendCost(costs, 1+maxRadioGenres, false, numberOfSongs, false)
+			if err := s.checkQuotaBudget(ctx, "this radio", estimate); err != nil {
+				return nil, nil, fmt.Errorf("%w; ask for fewer songs", err)
 			}
-			playlistTitle = fmt.Sprintf("[YM-MCP] %s", strings.Join(titleWords, " "))
-		}
 
-		// Create playlist
-		playlist, err := s.ytClient.CreatePlaylist(ctx, playlistTitle, input.Description, "private")
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
-		}
+			seed, err := s.ytClient.GetVideo(ctx, input.VideoID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get seed song: %w", err)
+			}
+			if seed == nil {
+				return nil, nil, fmt.Errorf("seed song %s not found", input.VideoID)
+			}
 
-		// Add videos to playlist
-		added, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to add videos to playlist: %w", err)
-		}
+			// Search for the seed's artist, then its genres
+			artist := strings.TrimSuffix(seed.ChannelTitle, " - Topic")
+			queries := []string{artist}
+			topics, err := s.ytClient.FetchVideoTopics(ctx, []string{seed.ID})
+			if err != nil {
+				s.logger.Warn("failed to fetch seed topics", "video", seed.ID, "error", err)
+				youtube.NoteFallback(ctx, "could not read the seed's genres; searched for its artist only")
+			}
+			for _, genre := range rankGenres(topics) {
+				if len(queries) > maxRadioGenres {
+					break
+				}
+				queries = append(queries, genre.name)
+			}
+
+			var searchSummary strings.Builder
+			searchSummary.WriteString("Search queries executed:\n")
+			var resultLists [][]youtube.SearchResult
+			for _, query := range queries {
+				results, err := s.ytClient.SearchVideos(ctx, query, int64(numberOfSongs))
+				if err != nil {
+					s.logger.Warn("search failed", "query", query, "error", err)
+					youtube.NoteFallback(ctx, "search '%s' failed; continued with other queries", query)
+					fmt.Fprintf(&searchSummary, "- '%s' (failed)\n", query)
+					continue
+				}
+				fmt.Fprintf(&searchSummary, "- '%s' (%d results)\n", query, len(results))
+				resultLists = append(resultLists, results)
+			}
 
-		// Build response
-		playlistURL := fmt.Sprintf("https://music.youtube.com/playlist?list=%s", playlist.ID)
+			// Interleave the searches so artist and genre songs alternate,
+			// skipping the seed and duplicates
+			videoIDs := []string{seed.ID}
+			seenIDs := map[string]struct{}{seed.ID: {}}
+			seenTracks := map[string]struct{}{trackKey(seed.Title, seed.ChannelTitle): {}}
+			nearDuplicates := 0
+		fill:
+			for i := 0; ; i++ {
+				exhausted := true
+				for _, results := range resultLists {
+					if i >= len(results) {
+						continue
+					}
+					exhausted = false
+					result := results[i]
+					if _, exists := seenIDs[result.VideoID]; exists {
+						continue
+					}
+					seenIDs[result.VideoID] = struct{}{}
+					if dedupStrategy == dedupByTitle {
+						key := trackKey(result.Title, result.ChannelTitle)
+						if _, exists := seenTracks[key]; exists {
+							nearDuplicates++
+							continue
+						}
+						seenTracks[key] = struct{}{}
+					}
+					videoIDs = append(videoIDs, result.VideoID)
+					if len(videoIDs) >= numberOfSongs {
+						break fill
+					}
+				}
+				if exhausted {
+					break
+				}
+			}
+			if len(videoIDs) == 1 {
+				return nil, nil, fmt.Errorf("no songs found for a radio from '%s'", seed.Title)
+			}
 
-		var output strings.Builder
-		fmt.Fprintf(&output, "# Playlist Created: %s\n\n", playlist.Title)
-		fmt.Fprintf(&output, "**YouTube Music URL:** %s\n\n", playlistURL)
-		fmt.Fprintf(&output, "**Songs added:** %d of %d requested\n\n", added, input.NumberOfSongs)
-		fmt.Fprintf(&output, "**Taste context:** %d liked songs, %d subscriptions, %d playlists analyzed\n\n", len(likedVideos), len(subscriptions), len(playlists))
-		fmt.Fprintf(&output, "**Top artists in your taste:** %s\n\n", strings.Join(topArtists[:min(5, len(topArtists))], ", "))
-		output.WriteString(searchSummary.String())
-		fmt.Fprintf(&output, "\n**Estimated quota usage:** ~%d units (%d searches x 100 + 50 playlist creation + %d x 50 adds)\n", len(searchQueries)*100+50+added*50, len(searchQueries), added)
+			playlist, err := s.ytClient.CreatePlaylist(ctx, s.prefixedTitle("Radio: "+seed.Title), fmt.Sprintf("Radio started from %s by %s", seed.Title, artist), "")
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
+			}
 
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: output.String()},
-			},
-		}, nil, nil
-	})
+			added, err := s.ytClient.AddVideosToPlaylist(ctx, playlist.ID, videoIDs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to add videos to playlist: %w", err)
+			}
+
+			var output strings.Builder
+			fmt.Fprintf(&output, "# Radio Created: %s\n\n", playlist.Title)
+			fmt.Fprintf(&output, "**YouTube Music URL:** %s\n\n", playlistURL(playlist.ID))
+			fmt.Fprintf(&output, "**Seed:** %s - %s\n\n", seed.Title, seed.ChannelTitle)
+			fmt.Fprintf(&output, "**Songs added:** %d of %d requested (including the seed)\n\n", added, numberOfSongs)
+			if dedupStrategy == dedupByTitle {
+				fmt.Fprintf(&output, "**Near-duplicates collapsed:** %d (same title and channel, different upload)\n\n", nearDuplicates)
+			}
+			output.WriteString(searchSummary.String())
+			fmt.Fprintf(&output, "\n**Quota used:** %d units (%d searches + playlist creation + %d adds)\n", quotaSpent(ctx), len(queries), added)
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: output.String()},
+				},
+			}, nil, nil
+		})
+	}
 
-	// Tool 2: ym:recommend-artists
+	// Tool 3: ym:recommend-artists
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "ym:recommend-artists",
 		Description: "Recommends artists the user would like based on their YouTube Music taste. Returns structured taste data for the LLM to use its own knowledge to generate recommendations. Does not search YouTube. Quota cost: ~5 units.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input recommendArtistsInput) (*mcp.CallToolResult, any, error) {
-		// Gather full taste data (no caps)
-		likedVideos, err := s.ytClient.GetLikedVideos(ctx)
+		// Gather taste data (full library unless capped)
+		likedVideos, _, err := s.tasteLikedVideos(ctx, input.MaxLiked)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
 		}
+		likedVideos, _ = youtube.SkipUnavailable(likedVideos)
 
 		subscriptions, err := s.ytClient.GetSubscriptions(ctx)
 		if err != nil {
@@ -300,16 +860,17 @@ func (s *Server) registerRecommendTools() {
 		}, nil, nil
 	})
 
-	// Tool 3: ym:recommend-albums
+	// Tool 4: ym:recommend-albums
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "ym:recommend-albums",
 		Description: "Recommends albums the user would like based on their YouTube Music taste. Returns structured taste data for the LLM to use its own knowledge to generate recommendations. Does not search YouTube. Quota cost: ~5 units.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input recommendAlbumsInput) (*mcp.CallToolResult, any, error) {
-		// Gather full taste data (no caps)
-		likedVideos, err := s.ytClient.GetLikedVideos(ctx)
+		// Gather taste data (full library unless capped)
+		likedVideos, _, err := s.tasteLikedVideos(ctx, input.MaxLiked)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get liked videos: %w", err)
 		}
+		likedVideos, _ = youtube.SkipUnavailable(likedVideos)
 
 		subscriptions, err := s.ytClient.GetSubscriptions(ctx)
 		if err != nil {
@@ -0,0 +1,455 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+)
+
+// recommendAPI fakes the calls of a ym:recommend-playlist dry run for a
+// library with nothing in it, whose one search finds the given videos.
+// Only the videos in details come back from the lookup.
+func recommendAPI(found []string, details map[string]map[string]any) http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":             "UCme",
+			"contentDetails": map[string]any{"relatedPlaylists": map[string]any{"likes": "LLme"}},
+		}}})
+	})
+	for _, path := range []string{"/youtube/v3/playlistItems", "/youtube/v3/playlists", "/youtube/v3/subscriptions"} {
+		api.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"items": []any{}})
+		})
+	}
+	api.HandleFunc("GET /youtube/v3/search", func(w http.ResponseWriter, r *http.Request) {
+		items := make([]any, len(found))
+		for i, videoID := range found {
+			items[i] = map[string]any{
+				"id":      map[string]any{"kind": "youtube#video", "videoId": videoID},
+				"snippet": map[string]any{"title": "Song " + videoID, "channelTitle": "Artist " + videoID},
+			}
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, videoID := range strings.Split(r.URL.Query().Get("id"), ",") {
+			if status, ok := details[videoID]; ok {
+				items = append(items, map[string]any{
+					"id":             videoID,
+					"snippet":        map[string]any{"title": "Song " + videoID},
+					"contentDetails": map[string]any{"duration": "PT3M"},
+					"status":         status,
+				})
+			}
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	return api
+}
+
+func TestRecommendPlaylistDropsUnplayableCandidates(t *testing.T) {
+	playable := map[string]any{"uploadStatus": "processed", "privacyStatus": "public"}
+	api := recommendAPI(
+		[]string{"song0000001", "song0000002", "song0000003"},
+		map[string]map[string]any{
+			"song0000001": playable,
+			// song0000002 was deleted: the lookup doesn't return it
+			"song0000003": {"uploadStatus": "processed", "privacyStatus": "private"},
+		},
+	)
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	result := callTool(t, s, "ym:recommend-playlist", map[string]any{
+		"description":   "test",
+		"numberOfSongs": 3,
+		"maxQueries":    1,
+		"excludeKnown":  false,
+		"dryRun":        true,
+	})
+	text := resultText(result)
+	if result.IsError {
+		t.Fatalf("recommend-playlist failed: %s", text)
+	}
+	if !strings.Contains(text, "**Candidate songs:** 1 of 3") || !strings.Contains(text, "(song0000001)") {
+		t.Errorf("preview does not list only song0000001:\n%s", text)
+	}
+	if !strings.Contains(text, "**Unplayable videos dropped:** 2") {
+		t.Errorf("preview does not report 2 dropped videos:\n%s", text)
+	}
+}
+
+func TestRecommendPlaylistKeepsCandidatesWithoutVerification(t *testing.T) {
+	api := recommendAPI([]string{"song0000001", "song0000002"}, nil)
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	result := callTool(t, s, "ym:recommend-playlist", map[string]any{
+		"description":     "test",
+		"numberOfSongs":   2,
+		"maxQueries":      1,
+		"excludeKnown":    false,
+		"dryRun":          true,
+		"verifyBeforeAdd": false,
+	})
+	text := resultText(result)
+	if result.IsError || !strings.Contains(text, "**Candidate songs:** 2 of 2") {
+		t.Errorf("want both unverified candidates kept, got:\n%s", text)
+	}
+}
+
+func TestRecommendPlaylistRejectsNumberOfSongsOutOfRange(t *testing.T) {
+	s := newTestServer(t, testConfig(t, nil), recommendAPI(nil, nil))
+
+	for _, songs := range []int{0, maxRecommendedSongs + 1, 500} {
+		t.Run(fmt.Sprint(songs), func(t *testing.T) {
+			result := callTool(t, s, "ym:recommend-playlist", map[string]any{"description": "test", "numberOfSongs": songs})
+			if text := resultText(result); !result.IsError || !strings.Contains(text, "numberOfSongs must be between 1 and 50") {
+				t.Errorf("result %q, want numberOfSongs rejected", text)
+			}
+		})
+	}
+}
+
+func TestRecommendPlaylistRefusesOverBudget(t *testing.T) {
+	tests := []struct {
+		name string
+		set  map[string]string
+		want string
+	}{
+		// 2 searches with their detail lookups, a playlist and 5 songs: ~502 units
+		{"per call", map[string]string{"MAX_QUOTA_PER_CALL": "500"}, "over the per-call budget of 500"},
+		{"quota left", map[string]string{"YOUTUBE_DAILY_QUOTA": "500"}, "only ~500 remain today"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searches atomic.Int32
+			api := http.NewServeMux()
+			api.Handle("/", recommendAPI(nil, nil))
+			api.HandleFunc("GET /youtube/v3/search", func(w http.ResponseWriter, r *http.Request) {
+				searches.Add(1)
+				writeJSON(w, map[string]any{"items": []any{}})
+			})
+			s := newTestServer(t, testConfig(t, tt.set), api)
+
+			result := callTool(t, s, "ym:recommend-playlist", map[string]any{"description": "test", "numberOfSongs": 5, "maxQueries": 2})
+			if text := resultText(result); !result.IsError || !strings.Contains(text, tt.want) {
+				t.Fatalf("result %q, want the recommendation refused: %s", text, tt.want)
+			}
+			if n := searches.Load(); n != 0 {
+				t.Errorf("%d searches were run, want none", n)
+			}
+		})
+	}
+}
+
+func TestCreateRadioRefusesOverQuotaLeft(t *testing.T) {
+	var calls atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("/youtube/v3/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, map[string]any{"items": []any{}})
+	})
+	// Up to 3 searches, a playlist and 10 songs: ~850 units
+	s := newTestServer(t, testConfig(t, map[string]string{"YOUTUBE_DAILY_QUOTA": "800"}), api)
+
+	result := callTool(t, s, "ym:create-radio", map[string]any{"videoId": "song0000001", "numberOfSongs": 10})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "only ~800 remain today") {
+		t.Fatalf("result %q, want the radio refused over the quota left", text)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("%d API calls were sent, want none", n)
+	}
+}
+
+func TestSplitDescriptionIntoTerms(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        []string
+	}{
+		{
+			name:        "quoted phrases",
+			description: `"Get Lucky", "Harder, Better, Faster, Stronger"`,
+			want:        []string{`"Get Lucky"`, `"Harder, Better, Faster, Stronger"`},
+		},
+		{
+			name:        "and between two artists",
+			description: "songs by Daft Punk and Justice, upbeat",
+			want:        []string{"Daft Punk", "Justice", "upbeat"},
+		},
+		{
+			name:        "artist and song pairs",
+			description: "Daft Punk - One More Time; Justice: Genesis",
+			want:        []string{"Daft Punk - One More Time", "Justice - Genesis"},
+		},
+		{
+			name:        "parentheses are not split",
+			description: "Simon (and Garfunkel, early), St. Vincent",
+			want:        []string{"Simon (and Garfunkel, early)", "St. Vincent"},
+		},
+		{
+			name:        "instructions mixed with terms",
+			description: "Focus on 80s synthpop. Avoid ballads, \"Tainted Love\" and music by Depeche Mode\nmake sure it is danceable",
+			want:        []string{`"Tainted Love"`, "Depeche Mode"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitDescriptionIntoTerms(tt.description); !slices.Equal(got, tt.want) {
+				t.Errorf("splitDescriptionIntoTerms(%q) = %q, want %q", tt.description, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateRecommendCost(t *testing.T) {
+	custom := youtube.QuotaCosts{List: 2, Search: 7, Write: 3}
+	tests := []struct {
+		name            string
+		costs           youtube.QuotaCosts
+		searches        int
+		durationLookups bool
+		songs           int
+		dryRun          bool
+		want            int
+	}{
+		{"defaults", youtube.DefaultQuotaCosts, 2, true, 5, false, 2*100 + 2*1 + 50 + 5*50},
+		{"defaults dry run", youtube.DefaultQuotaCosts, 2, true, 5, true, 2*100 + 2*1},
+		{"defaults without lookups", youtube.DefaultQuotaCosts, 3, false, 10, false, 3*100 + 50 + 10*50},
+		{"custom", custom, 2, true, 5, false, 2*7 + 2*2 + 3 + 5*3},
+		{"custom dry run", custom, 4, false, 5, true, 4 * 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateRecommendCost(tt.costs, tt.searches, tt.durationLookups, tt.songs, tt.dryRun); got != tt.want {
+				t.Errorf("estimateRecommendCost = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendPlaylistEstimateFollowsQuotaCosts(t *testing.T) {
+	s := newTestServer(t, testConfig(t, map[string]string{
+		"QUOTA_COSTS":        "list:2,search:7,write:3",
+		"MAX_QUOTA_PER_CALL": "1",
+	}), recommendAPI(nil, nil))
+
+	// 2 searches at 7 with their lookups at 2, a playlist at 3 and 5 songs at 3
+	result := callTool(t, s, "ym:recommend-playlist", map[string]any{"description": "test", "numberOfSongs": 5, "maxQueries": 2})
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "would cost ~36 quota units") {
+		t.Errorf("result %q, want the estimate from the configured costs", text)
+	}
+
+	var costs quotaCostsOutput
+	decodeOutput(t, callTool(t, s, "ym:get-quota-costs", map[string]any{}), &costs)
+	for _, c := range costs.Costs {
+		if want := map[string]int{"list": 2, "search": 7, "write": 3}[c.Operation]; c.Units != want {
+			t.Errorf("ym:get-quota-costs %s = %d, want %d", c.Operation, c.Units, want)
+		}
+	}
+}
+
+func TestRecommendPlaylistCreatesOrAppends(t *testing.T) {
+	var mu sync.Mutex
+	var created []string
+	added := make(map[string][]string) // playlist ID -> video IDs
+	api := http.NewServeMux()
+	api.Handle("/", recommendAPI([]string{"song0000001", "song0000002", "song0000003"}, nil))
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":             "PLtarget",
+			"snippet":        map[string]any{"title": "Big Mix"},
+			"contentDetails": map[string]any{"itemCount": 1},
+		}}})
+	})
+	api.HandleFunc("POST /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		created = append(created, "PLnew")
+		writeJSON(w, map[string]any{"id": "PLnew", "snippet": map[string]any{"title": "New Mix"}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("playlistId") == "PLtarget" {
+			writeJSON(w, playlistItemsResponse("song0000002"))
+			return
+		}
+		writeJSON(w, playlistItemsResponse())
+	})
+	api.HandleFunc("POST /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		var item struct {
+			Snippet struct {
+				PlaylistID string `json:"playlistId"`
+				ResourceID struct {
+					VideoID string `json:"videoId"`
+				} `json:"resourceId"`
+			} `json:"snippet"`
+		}
+		json.NewDecoder(r.Body).Decode(&item)
+		mu.Lock()
+		defer mu.Unlock()
+		added[item.Snippet.PlaylistID] = append(added[item.Snippet.PlaylistID], item.Snippet.ResourceID.VideoID)
+		writeJSON(w, map[string]any{"id": "item-" + item.Snippet.ResourceID.VideoID})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	args := map[string]any{
+		"description":     "test",
+		"numberOfSongs":   3,
+		"maxQueries":      1,
+		"excludeKnown":    false,
+		"verifyBeforeAdd": false,
+	}
+
+	result := callTool(t, s, "ym:recommend-playlist", args)
+	text := resultText(result)
+	if result.IsError || !strings.Contains(text, "# Playlist Created:") {
+		t.Fatalf("create: %s", text)
+	}
+	if len(created) != 1 || !slices.Equal(added["PLnew"], []string{"song0000001", "song0000002", "song0000003"}) {
+		t.Errorf("create: created %q and added %q, want one new playlist with all 3 songs", created, added["PLnew"])
+	}
+
+	args["targetPlaylistId"] = "https://music.youtube.com/playlist?list=PLtarget"
+	result = callTool(t, s, "ym:recommend-playlist", args)
+	text = resultText(result)
+	if result.IsError || !strings.Contains(text, "# Songs Appended to Playlist: Big Mix") {
+		t.Fatalf("append: %s", text)
+	}
+	if len(created) != 1 {
+		t.Errorf("append created another playlist")
+	}
+	if !slices.Equal(added["PLtarget"], []string{"song0000001", "song0000003"}) || !strings.Contains(text, "**Already in the playlist:** 1") {
+		t.Errorf("append: added %q, want the 2 songs not already in it and 1 reported as present:\n%s", added["PLtarget"], text)
+	}
+}
+
+func TestRecommendPlaylistSearchBreadth(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            map[string]any
+		wantSearches    int
+		wantMaxResults  string
+		wantDryRunQuota int
+	}{
+		{"defaults", map[string]any{"numberOfSongs": 6}, 2, "5", 2 * 100},
+		{"more queries", map[string]any{"numberOfSongs": 6, "maxQueries": 4}, 4, "5", 4 * 100},
+		{"fewer queries, more results", map[string]any{"numberOfSongs": 6, "maxQueries": 1, "resultsPerQuery": 25}, 1, "25", 1 * 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var maxResults []string
+			api := http.NewServeMux()
+			api.Handle("/", recommendAPI(nil, nil))
+			api.HandleFunc("GET /youtube/v3/search", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				maxResults = append(maxResults, r.URL.Query().Get("maxResults"))
+				// A fresh song per search, so every query runs
+				videoID := fmt.Sprintf("song%07d", len(maxResults))
+				mu.Unlock()
+				writeJSON(w, map[string]any{"items": []any{map[string]any{
+					"id":      map[string]any{"kind": "youtube#video", "videoId": videoID},
+					"snippet": map[string]any{"title": "Song " + videoID, "channelTitle": "Artist"},
+				}}})
+			})
+			// The estimate must cover the chosen breadth: one unit short is refused
+			s := newTestServer(t, testConfig(t, map[string]string{"MAX_QUOTA_PER_CALL": fmt.Sprint(tt.wantDryRunQuota - 1)}), api)
+			args := map[string]any{"description": "one, two, three, four, five", "excludeKnown": false, "verifyBeforeAdd": false, "dryRun": true}
+			maps.Copy(args, tt.args)
+			if result := callTool(t, s, "ym:recommend-playlist", args); !result.IsError || !strings.Contains(resultText(result), fmt.Sprintf("~%d quota units", tt.wantDryRunQuota)) {
+				t.Fatalf("result %q, want the dry run estimated at %d", resultText(result), tt.wantDryRunQuota)
+			}
+
+			s = newTestServer(t, testConfig(t, nil), api)
+			result := callTool(t, s, "ym:recommend-playlist", args)
+			if result.IsError {
+				t.Fatalf("recommend-playlist: %s", resultText(result))
+			}
+			if len(maxResults) != tt.wantSearches {
+				t.Errorf("%d searches, want %d", len(maxResults), tt.wantSearches)
+			}
+			for _, got := range maxResults {
+				if got != tt.wantMaxResults {
+					t.Errorf("search maxResults = %s, want %s", got, tt.wantMaxResults)
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendPlaylistSeedFromSubscriptions(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	api := http.NewServeMux()
+	api.Handle("/", recommendAPI(nil, nil))
+	api.HandleFunc("GET /youtube/v3/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, title := range []string{"Phoenix", "Air"} {
+			items = append(items, map[string]any{"snippet": map[string]any{
+				"title":      title,
+				"resourceId": map[string]any{"kind": "youtube#channel", "channelId": "UC" + title},
+			}})
+		}
+		writeJSON(w, map[string]any{"items": items})
+	})
+	api.HandleFunc("GET /youtube/v3/search", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query().Get("q"))
+		mu.Unlock()
+		writeJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":      map[string]any{"kind": "youtube#video", "videoId": "song0000001"},
+			"snippet": map[string]any{"title": "Song song0000001"},
+		}}})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	tests := []struct {
+		name        string
+		description string
+		seed        bool
+		want        []string
+	}{
+		{"description terms", "indie rock, synthwave", false, []string{"indie rock", "synthwave"}},
+		{"flag", "indie rock, synthwave", true, []string{"Air", "Phoenix"}},
+		{"description cue", "more from artists I follow", false, []string{"Air", "Phoenix"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			queries = nil
+			mu.Unlock()
+
+			result := callTool(t, s, "ym:recommend-playlist", map[string]any{
+				"description":           tt.description,
+				"seedFromSubscriptions": tt.seed,
+				"numberOfSongs":         5,
+				"maxQueries":            2,
+				"dryRun":                true,
+				"verifyBeforeAdd":       false,
+			})
+			text := resultText(result)
+			if result.IsError {
+				t.Fatalf("recommend-playlist failed: %s", text)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.Equal(queries, tt.want) {
+				t.Errorf("searched for %q, want %q", queries, tt.want)
+			}
+			if seeded := strings.Contains(text, "seeded from your subscriptions"); seeded != (tt.want[0] == "Air") {
+				t.Errorf("summary says seeded from subscriptions = %v:\n%s", seeded, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gxravel/youtube-music-mcp/internal/youtube"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Input and output types for video tools
+
+type getVideoInput struct {
+	VideoID string `json:"videoId" jsonschema:"YouTube video ID or URL"`
+}
+
+type videoDetailOutput struct {
+	Found           bool   `json:"found" jsonschema:"Whether the video exists"`
+	ID              string `json:"id,omitempty" jsonschema:"Video ID"`
+	Title           string `json:"title,omitempty" jsonschema:"Video title"`
+	ChannelTitle    string `json:"channelTitle,omitempty" jsonschema:"Channel (usually the artist) that published the video"`
+	Description     string `json:"description,omitempty" jsonschema:"Video description"`
+	Duration        string `json:"duration,omitempty" jsonschema:"Raw ISO 8601 duration, e.g. PT4M30S"`
+	DurationSeconds int64  `json:"durationSeconds,omitempty" jsonschema:"Duration in seconds (omitted for livestreams)"`
+	DurationHuman   string `json:"durationHuman,omitempty" jsonschema:"Human-readable duration, e.g. 4:30"`
+	PublishedAt     string `json:"publishedAt,omitempty" jsonschema:"When the video was published (RFC 3339)"`
+	ThumbnailURL    string `json:"thumbnailUrl,omitempty" jsonschema:"URL of the video's thumbnail image"`
+	QuotaUsed       int    `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type searchVideosInput struct {
+	Query              string `json:"query" jsonschema:"Search query (artist/song/genre/mood)"`
+	MaxResults         int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of results (1-25, default 10)"`
+	MinDurationSeconds int64  `json:"minDurationSeconds,omitempty" jsonschema:"Drop results shorter than this many seconds (adds 1 quota unit for the duration lookup)"`
+	MaxDurationSeconds int64  `json:"maxDurationSeconds,omitempty" jsonschema:"Drop results longer than this many seconds, e.g. to exclude long mixes (adds 1 quota unit for the duration lookup)"`
+	CategoryID         string `json:"categoryId,omitempty" jsonschema:"Video category to search instead of Music, by name (comedy/entertainment/gaming/...) or ID"`
+	IncludeNonMusic    bool   `json:"includeNonMusic,omitempty" jsonschema:"If true search across all categories (podcasts, live performances filed elsewhere, ...) instead of Music only"`
+	StrictMusic        bool   `json:"strictMusic,omitempty" jsonschema:"If true look up each result's category and drop any not actually filed under Music, e.g. interviews or reaction videos the search let through (adds 1 quota unit per 50 results)"`
+	RegionCode         string `json:"regionCode,omitempty" jsonschema:"ISO 3166-1 alpha-2 country code to return results relevant to, e.g. DE. Defaults to the server setting, else inferred from the account"`
+	RelevanceLanguage  string `json:"relevanceLanguage,omitempty" jsonschema:"ISO 639-1 language code results should be most relevant to, e.g. de. Defaults to the server setting, else inferred from the account"`
+	Order              string `json:"order,omitempty" jsonschema:"Result order: relevance (default), date (newest first), rating, viewCount (most popular first), or title"`
+	PublishedAfter     string `json:"publishedAfter,omitempty" jsonschema:"Only videos uploaded at or after this RFC 3339 time, e.g. 2024-01-01T00:00:00Z (filtered by YouTube)"`
+	PublishedBefore    string `json:"publishedBefore,omitempty" jsonschema:"Only videos uploaded before this RFC 3339 time, e.g. 2000-01-01T00:00:00Z (filtered by YouTube)"`
+	PageToken          string `json:"pageToken,omitempty" jsonschema:"nextPageToken of a previous call with the same query and options, to get the next page of results. EACH PAGE IS A NEW SEARCH AND COSTS THE FULL SEARCH QUOTA AGAIN; only use it when the user asks for more results"`
+}
+
+type searchVideosOutput struct {
+	Results         []searchResultOutput `json:"results" jsonschema:"Matching videos in relevance order"`
+	NextPageToken   string               `json:"nextPageToken,omitempty" jsonschema:"Pass as pageToken for the next page of results (another full-price search); empty on the last page"`
+	OutsideDuration int                  `json:"outsideDuration,omitempty" jsonschema:"Number of results dropped by the duration filter"`
+	NonMusic        int                  `json:"nonMusic,omitempty" jsonschema:"Number of results dropped by strictMusic for not being in the Music category"`
+	QuotaUsed       int                  `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type searchResultOutput struct {
+	VideoID         string `json:"videoId" jsonschema:"Video ID"`
+	Title           string `json:"title" jsonschema:"Video title"`
+	ChannelTitle    string `json:"channelTitle" jsonschema:"Channel (usually the artist) that published the video"`
+	DurationSeconds int64  `json:"durationSeconds,omitempty" jsonschema:"Duration in seconds (only when a duration filter is set)"`
+	DurationHuman   string `json:"durationHuman,omitempty" jsonschema:"Human-readable duration (only when a duration filter is set)"`
+	ThumbnailURL    string `json:"thumbnailUrl,omitempty" jsonschema:"URL of the video's thumbnail image"`
+}
+
+type getRelatedVideosInput struct {
+	VideoID    string `json:"videoId" jsonschema:"Seed video ID or URL to find similar music for"`
+	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of related videos (1-24, default 10)"`
+}
+
+type getRelatedVideosOutput struct {
+	Query     string               `json:"query" jsonschema:"Search query derived from the seed's channel and title"`
+	Results   []searchResultOutput `json:"results" jsonschema:"Related videos, most relevant first (the seed itself is excluded)"`
+	QuotaUsed int                  `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type getVideoCommentsInput struct {
+	VideoID    string `json:"videoId" jsonschema:"YouTube video ID or URL"`
+	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of top-level comments (1-100, default 20)"`
+}
+
+type getVideoCommentsOutput struct {
+	Comments         []commentOutput `json:"comments" jsonschema:"Top-level comments, most relevant first"`
+	CommentsDisabled bool            `json:"commentsDisabled,omitempty" jsonschema:"Whether the video has comments turned off"`
+	QuotaUsed        int             `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type getCategoriesInput struct {
+	RegionCode string `json:"regionCode,omitempty" jsonschema:"ISO 3166-1 alpha-2 region code, e.g. JP (default: the configured search region, else US)"`
+}
+
+type getCategoriesOutput struct {
+	RegionCode      string           `json:"regionCode" jsonschema:"Region the categories were listed for"`
+	Categories      []categoryOutput `json:"categories" jsonschema:"Video categories available in the region"`
+	MusicCategoryID string           `json:"musicCategoryId,omitempty" jsonschema:"ID of the Music category in the region (omitted if the region has none)"`
+	QuotaUsed       int              `json:"quotaUsed" jsonschema:"Quota units this call actually spent"`
+}
+
+type categoryOutput struct {
+	ID         string `json:"id" jsonschema:"Category ID"`
+	Title      string `json:"title" jsonschema:"Category title"`
+	Assignable bool   `json:"assignable" jsonschema:"Whether videos can be filed under the category"`
+}
+
+type commentOutput struct {
+	Author      string `json:"author" jsonschema:"Display name of the commenter"`
+	Text        string `json:"text" jsonschema:"Comment text"`
+	LikeCount   int64  `json:"likeCount" jsonschema:"Number of likes on the comment"`
+	ReplyCount  int64  `json:"replyCount,omitempty" jsonschema:"Number of replies to the comment"`
+	PublishedAt string `json:"publishedAt,omitempty" jsonschema:"When the comment was posted (RFC 3339)"`
+}
+
+// maxComments caps the comments get-video-comments returns, to keep them from
+// flooding the context.
+const maxComments = 100
+
+// durationRange is an optional inclusive [min, max] bound on video length in
+// seconds. A zero bound is unset.
+type durationRange struct {
+	minSeconds int64
+	maxSeconds int64
+}
+
+// newDurationRange validates and builds a duration range from tool inputs.
+func newDurationRange(minSeconds, maxSeconds int64) (durationRange, error) {
+	if minSeconds < 0 || maxSeconds < 0 {
+		return durationRange{}, fmt.Errorf("duration bounds cannot be negative")
+	}
+	if maxSeconds > 0 && minSeconds > maxSeconds {
+		return durationRange{}, fmt.Errorf("minDurationSeconds (%d) cannot exceed maxDurationSeconds (%d)", minSeconds, maxSeconds)
+	}
+	return durationRange{minSeconds: minSeconds, maxSeconds: maxSeconds}, nil
+}
+
+// active reports whether any bound is set.
+func (r durationRange) active() bool {
+	return r.minSeconds > 0 || r.maxSeconds > 0
+}
+
+// contains reports whether a video of the given length passes the range.
+// Unknown lengths (0, e.g. livestreams) never pass an active range.
+func (r durationRange) contains(seconds int64) bool {
+	if !r.active() {
+		return true
+	}
+	if seconds <= 0 {
+		return false
+	}
+	return seconds >= r.minSeconds && (r.maxSeconds == 0 || seconds <= r.maxSeconds)
+}
+
+// videoDurations looks up the details of videoIDs, keyed by ID, so their
+// durations (and playability) can be checked. Quota cost: 1 unit per 50 videos.
+func (s *Server) videoDurations(ctx context.Context, videoIDs []string) (map[string]youtube.VideoDetail, error) {
+	videos, err := s.ytClient.GetVideos(ctx, videoIDs)
+	if err != nil {
+		return nil, err
+	}
+	details := make(map[string]youtube.VideoDetail, len(videos))
+	for _, v := range videos {
+		details[v.ID] = v
+	}
+	return details, nil
+}
+
+// parseOptionalTime parses an RFC 3339 tool input, returning the zero time if it is empty.
+func parseOptionalTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected an RFC 3339 time such as 2024-01-01T00:00:00Z", name, value)
+	}
+	return t, nil
+}
+
+// newVideoDetailOutput converts a youtube.VideoDetail into its tool output form.
+func newVideoDetailOutput(v youtube.VideoDetail) videoDetailOutput {
+	return videoDetailOutput{
+		Found:           true,
+		ID:              v.ID,
+		Title:           v.Title,
+		ChannelTitle:    v.ChannelTitle,
+		Description:     v.Description,
+		Duration:        v.Duration,
+		DurationSeconds: v.DurationSeconds,
+		DurationHuman:   v.DurationHuman,
+		PublishedAt:     v.PublishedAt,
+		ThumbnailURL:    v.ThumbnailURL,
+	}
+}
+
+// registerVideoTools registers the video MCP tools
+func (s *Server) registerVideoTools() {
+	costs := quotaCosts(s.cfg)
+
+	// Tool: ym:get-video
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-video",
+		Description: "Gets a video's details: title, channel, description, publish date, and duration (raw ISO 8601 plus seconds and a human-readable form like 4:30). Quota cost: 1 unit.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getVideoInput) (*mcp.CallToolResult, *videoDetailOutput, error) {
+		video, err := s.ytClient.GetVideo(ctx, input.VideoID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get video: %w", err)
+		}
+		if video == nil {
+			return nil, &videoDetailOutput{Found: false, QuotaUsed: quotaSpent(ctx)}, nil
+		}
+
+		out := newVideoDetailOutput(*video)
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, &out, nil
+	})
+	// Tool: ym:search-videos
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:search-videos",
+		Description: fmt.Sprintf("Searches YouTube Music for songs matching a query and returns the results without creating anything. Searches the Music category unless categoryId or includeNonMusic is set; non-music results may be ones the taste analysis would ignore. Results can be ordered by date (newest releases) or viewCount (most popular) instead of relevance, and limited to an upload date range (applied by YouTube, no extra quota). Optionally filters by duration (e.g. to drop long mixes); enabling the filter adds %d quota units for the duration lookup. Set strictMusic to drop results YouTube let through but that are not actually music, for %d more units. Quota cost: %d units per search. Returns one page; passing nextPageToken back as pageToken fetches the next one, but EVERY EXTRA PAGE COSTS ANOTHER %d UNITS, so only page deeper when the user explicitly wants more.", costs.List, costs.List, costs.Search, costs.Search),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input searchVideosInput) (*mcp.CallToolResult, *searchVideosOutput, error) {
+		if strings.TrimSpace(input.Query) == "" {
+			return nil, nil, fmt.Errorf("query cannot be empty")
+		}
+		durations, err := newDurationRange(input.MinDurationSeconds, input.MaxDurationSeconds)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Music only unless another category (or none) is requested
+		categoryID := ""
+		switch {
+		case input.IncludeNonMusic && input.CategoryID != "":
+			return nil, nil, fmt.Errorf("categoryId and includeNonMusic cannot be combined")
+		case input.StrictMusic && (input.IncludeNonMusic || input.CategoryID != ""):
+			return nil, nil, fmt.Errorf("strictMusic keeps Music results only and cannot be combined with categoryId or includeNonMusic")
+		case input.IncludeNonMusic:
+			categoryID = ""
+		case input.CategoryID != "":
+			categoryID, err = youtube.ResolveCategoryID(input.CategoryID)
+			if err != nil {
+				return nil, nil, err
+			}
+		default:
+			categoryID = s.ytClient.ResolveMusicCategoryID(ctx)
+		}
+
+		opts := youtube.SearchOptions{
+			CategoryID:        categoryID,
+			RegionCode:        input.RegionCode,
+			RelevanceLanguage: input.RelevanceLanguage,
+			Order:             input.Order,
+		}
+		if opts.PublishedAfter, err = parseOptionalTime("publishedAfter", input.PublishedAfter); err != nil {
+			return nil, nil, err
+		}
+		if opts.PublishedBefore, err = parseOptionalTime("publishedBefore", input.PublishedBefore); err != nil {
+			return nil, nil, err
+		}
+		if err := opts.Validate(); err != nil {
+			return nil, nil, err
+		}
+
+		results, nextPageToken, err := s.ytClient.SearchVideosPage(ctx, input.Query, input.MaxResults, opts, input.PageToken)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to search: %w", err)
+		}
+
+		// Drop results YouTube filed under another category despite the Music filter
+		out := &searchVideosOutput{NextPageToken: nextPageToken}
+		if input.StrictMusic && len(results) > 0 {
+			ids := make([]string, 0, len(results))
+			for _, result := range results {
+				ids = append(ids, result.VideoID)
+			}
+			categories, err := s.ytClient.GetVideoCategories(ctx, ids)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get video categories: %w", err)
+			}
+			music := results[:0]
+			for _, result := range results {
+				if categories[result.VideoID] == opts.CategoryID {
+					music = append(music, result)
+				}
+			}
+			out.NonMusic = len(results) - len(music)
+			results = music
+		}
+
+		var details map[string]youtube.VideoDetail
+		if durations.active() && len(results) > 0 {
+			ids := make([]string, 0, len(results))
+			for _, result := range results {
+				ids = append(ids, result.VideoID)
+			}
+			details, err = s.videoDurations(ctx, ids)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get video durations: %w", err)
+			}
+		}
+
+		out.Results = make([]searchResultOutput, 0, len(results))
+		for _, result := range results {
+			item := searchResultOutput{
+				VideoID:      result.VideoID,
+				Title:        result.Title,
+				ChannelTitle: result.ChannelTitle,
+				ThumbnailURL: result.ThumbnailURL,
+			}
+			if durations.active() {
+				detail := details[result.VideoID]
+				if !durations.contains(detail.DurationSeconds) {
+					out.OutsideDuration++
+					continue
+				}
+				item.DurationSeconds = detail.DurationSeconds
+				item.DurationHuman = detail.DurationHuman
+			}
+			out.Results = append(out.Results, item)
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+	// Tool: ym:get-related-videos
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-related-videos",
+		Description: fmt.Sprintf("Finds music similar to a seed video (\"more like this\"). YouTube no longer offers related-video search, so this approximates it by searching the Music category for the seed's artist and title; the query used is returned. Quota cost: %d (seed lookup) + %d units (search).", costs.List, costs.Search),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getRelatedVideosInput) (*mcp.CallToolResult, *getRelatedVideosOutput, error) {
+		results, query, err := s.ytClient.GetRelatedVideos(ctx, input.VideoID, min(input.MaxResults, maxSearchResults-1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get related videos: %w", err)
+		}
+
+		out := &getRelatedVideosOutput{
+			Query:   query,
+			Results: make([]searchResultOutput, 0, len(results)),
+		}
+		for _, result := range results {
+			out.Results = append(out.Results, searchResultOutput{
+				VideoID:      result.VideoID,
+				Title:        result.Title,
+				ChannelTitle: result.ChannelTitle,
+				ThumbnailURL: result.ThumbnailURL,
+			})
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+	// Tool: ym:get-video-comments
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-video-comments",
+		Description: fmt.Sprintf("Gets a video's top-level comments, most relevant first, with author, text, and like count: context on what listeners say about a song and whether it is a fan favorite. Videos with comments turned off return no comments and commentsDisabled: true. Quota cost: %d per page of up to 100 comments.", costs.List),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getVideoCommentsInput) (*mcp.CallToolResult, *getVideoCommentsOutput, error) {
+		out := &getVideoCommentsOutput{Comments: []commentOutput{}}
+		comments, err := s.ytClient.GetVideoComments(ctx, input.VideoID, min(input.MaxResults, maxComments))
+		if errors.Is(err, youtube.ErrCommentsDisabled) {
+			out.CommentsDisabled = true
+			out.QuotaUsed = quotaSpent(ctx)
+			return nil, out, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get video comments: %w", err)
+		}
+
+		for _, comment := range comments {
+			out.Comments = append(out.Comments, commentOutput{
+				Author:      comment.Author,
+				Text:        comment.Text,
+				LikeCount:   comment.LikeCount,
+				ReplyCount:  comment.ReplyCount,
+				PublishedAt: comment.PublishedAt,
+			})
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+	// Tool: ym:get-categories
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "ym:get-categories",
+		Description: fmt.Sprintf("Lists the video categories available in a region, with the ID of its Music category. Category IDs can differ between regions; music filtering looks the Music ID up the same way. Quota cost: %d unit.", costs.List),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getCategoriesInput) (*mcp.CallToolResult, *getCategoriesOutput, error) {
+		regionCode := strings.ToUpper(cmp.Or(input.RegionCode, s.cfg.SearchRegion, "US"))
+		categories, err := s.ytClient.ListVideoCategories(ctx, regionCode)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get video categories: %w", err)
+		}
+
+		out := &getCategoriesOutput{
+			RegionCode: regionCode,
+			Categories: make([]categoryOutput, 0, len(categories)),
+		}
+		for _, category := range categories {
+			out.Categories = append(out.Categories, categoryOutput{
+				ID:         category.ID,
+				Title:      category.Title,
+				Assignable: category.Assignable,
+			})
+			if out.MusicCategoryID == "" && strings.EqualFold(category.Title, "Music") {
+				out.MusicCategoryID = category.ID
+			}
+		}
+
+		out.QuotaUsed = quotaSpent(ctx)
+		return nil, out, nil
+	})
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestSearchVideosThreadsPageToken(t *testing.T) {
+	var pageTokens []string
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/videoCategories", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"items": []any{map[string]any{"id": "10", "snippet": map[string]any{"title": "Music"}}}})
+	})
+	api.HandleFunc("GET /youtube/v3/search", func(w http.ResponseWriter, r *http.Request) {
+		pageToken := r.URL.Query().Get("pageToken")
+		pageTokens = append(pageTokens, pageToken)
+		result := func(videoID string) map[string]any {
+			return map[string]any{
+				"id":      map[string]any{"kind": "youtube#video", "videoId": videoID},
+				"snippet": map[string]any{"title": "Song " + videoID},
+			}
+		}
+		if pageToken == "" {
+			writeJSON(w, map[string]any{"items": []any{result("song0000001"), result("song0000002")}, "nextPageToken": "page2"})
+			return
+		}
+		writeJSON(w, map[string]any{"items": []any{result("song0000003")}})
+	})
+	s := newTestServer(t, testConfig(t, nil), api)
+
+	search := func(args map[string]any) (ids []string, out searchVideosOutput) {
+		t.Helper()
+		decodeOutput(t, callTool(t, s, "ym:search-videos", args), &out)
+		for _, result := range out.Results {
+			ids = append(ids, result.VideoID)
+		}
+		return ids, out
+	}
+
+	// Without a token the first page comes back with the token for the next
+	ids, out := search(map[string]any{"query": "daft punk"})
+	if !slices.Equal(ids, []string{"song0000001", "song0000002"}) || out.NextPageToken != "page2" {
+		t.Errorf("first page: results %q, next page %q; want the first two and page2", ids, out.NextPageToken)
+	}
+
+	// Passing it back fetches the next page, another full-price search
+	ids, out = search(map[string]any{"query": "daft punk", "pageToken": "page2"})
+	if !slices.Equal(ids, []string{"song0000003"}) || out.NextPageToken != "" {
+		t.Errorf("second page: results %q, next page %q; want the third and no next page", ids, out.NextPageToken)
+	}
+	if out.QuotaUsed != 100 {
+		t.Errorf("second page used %d quota, want 100", out.QuotaUsed)
+	}
+
+	if !slices.Equal(pageTokens, []string{"", "page2"}) {
+		t.Errorf("searches sent page tokens %q, want none then page2", pageTokens)
+	}
+}
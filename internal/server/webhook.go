@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Delivery limits of the AUTH_WEBHOOK_URL notification.
+const (
+	webhookTimeout  = 10 * time.Second
+	webhookAttempts = 3
+	webhookBackoff  = 2 * time.Second
+)
+
+// authWebhookPayload is the JSON body posted to AUTH_WEBHOOK_URL.
+type authWebhookPayload struct {
+	Event     string `json:"event"`
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+}
+
+// announceGrant sets up the YouTube client of a user who just completed the
+// Google authorization, instead of waiting for their first MCP request, and
+// then posts the auth webhook. Failures are logged, never returned: the
+// authorization itself already succeeded.
+func (s *Server) announceGrant(ctx context.Context, grantID string) {
+	t, err := s.tenant(ctx, grantID)
+	if err != nil {
+		s.logger.Warn("auth webhook skipped: could not set up the new user's YouTube client", "error", err)
+		return
+	}
+
+	payload := authWebhookPayload{
+		Event:     "authenticated",
+		Channel:   t.channelName,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := postWebhook(ctx, s.cfg.AuthWebhookURL, payload); err != nil {
+		s.logger.Warn("auth webhook failed", "channel", t.channelName, "error", err)
+		return
+	}
+	s.logger.Info("auth webhook delivered", "channel", t.channelName)
+}
+
+// postWebhook POSTs payload as JSON to url, retrying failed attempts and
+// non-2xx responses up to webhookAttempts times.
+func postWebhook(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = postWebhookOnce(ctx, url, body)
+		if err == nil || attempt == webhookAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * webhookBackoff):
+		}
+	}
+}
+
+// postWebhookOnce makes a single webhook delivery attempt.
+func postWebhookOnce(ctx context.Context, url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", serverName+"/"+serverVersion)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
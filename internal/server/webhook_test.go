@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver records the auth webhooks posted to it, answering the first
+// failures requests with 500.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	payloads []authWebhookPayload
+}
+
+func (rcv *webhookReceiver) start(t *testing.T) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		rcv.attempts++
+		if rcv.attempts <= rcv.failures {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("webhook Content-Type = %q, want application/json", ct)
+		}
+		var payload authWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode webhook: %v", err)
+		}
+		rcv.payloads = append(rcv.payloads, payload)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestAnnounceGrantPostsWebhook(t *testing.T) {
+	var rcv webhookReceiver
+	google := &fakeGoogleAPI{revoked: make(map[string]bool)}
+	cfg := testConfig(t, map[string]string{"AUTH_WEBHOOK_URL": rcv.start(t)})
+	root, ctx := newTestSSEServer(t, cfg, google)
+
+	grantID := grantGoogleAccess(t, root.mcpOAuth, "alice")
+	before := time.Now().UTC().Truncate(time.Second)
+	root.announceGrant(ctx, grantID)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if len(rcv.payloads) != 1 {
+		t.Fatalf("received %d webhooks, want 1", len(rcv.payloads))
+	}
+	got := rcv.payloads[0]
+	if got.Event != "authenticated" || got.Channel != "alice" {
+		t.Errorf("webhook = %+v, want an authenticated event for alice", got)
+	}
+	if ts, err := time.Parse(time.RFC3339, got.Timestamp); err != nil || ts.Before(before) {
+		t.Errorf("webhook timestamp %q, want an RFC 3339 time of the grant", got.Timestamp)
+	}
+	// The user's YouTube client was set up along the way
+	if len(root.tenantList()) != 1 {
+		t.Errorf("tenants after the grant = %d, want 1", len(root.tenantList()))
+	}
+}
+
+func TestAnnounceGrantSkipsWebhookWhenClientSetupFails(t *testing.T) {
+	var rcv webhookReceiver
+	google := &fakeGoogleAPI{revoked: make(map[string]bool)}
+	cfg := testConfig(t, map[string]string{"AUTH_WEBHOOK_URL": rcv.start(t)})
+	root, ctx := newTestSSEServer(t, cfg, google)
+
+	// No Google token was ever granted for this user
+	root.announceGrant(ctx, "unknown-grant")
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if rcv.attempts != 0 {
+		t.Errorf("webhook posted %d times, want none", rcv.attempts)
+	}
+}
+
+func TestPostWebhookRetriesFailedDelivery(t *testing.T) {
+	rcv := webhookReceiver{failures: 1}
+	url := rcv.start(t)
+
+	if err := postWebhook(context.Background(), url, authWebhookPayload{Event: "authenticated", Channel: "alice"}); err != nil {
+		t.Fatalf("postWebhook: %v", err)
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if rcv.attempts != 2 || len(rcv.payloads) != 1 {
+		t.Errorf("attempts %d, delivered %d; want a retry after the 500 and one delivery", rcv.attempts, len(rcv.payloads))
+	}
+}
+
+func TestPostWebhookStopsWhenContextEnds(t *testing.T) {
+	rcv := webhookReceiver{failures: webhookAttempts}
+	url := rcv.start(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := postWebhook(ctx, url, authWebhookPayload{}); err == nil {
+		t.Fatal("postWebhook succeeded, want the failed delivery reported")
+	}
+	if elapsed := time.Since(start); elapsed > webhookBackoff {
+		t.Errorf("postWebhook returned after %v, want it to give up when the context ended", elapsed)
+	}
+}
@@ -0,0 +1,101 @@
+package youtube
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached library reads are served before refetching.
+const DefaultCacheTTL = 5 * time.Minute
+
+// Cache keys, one per cached read method.
+const (
+	cacheKeyLikedVideos   = "GetLikedVideos"
+	cacheKeyLikedVideoSet = "LikedVideoSet"
+	cacheKeySubscriptions = "GetSubscriptions"
+)
+
+// cacheEntry holds a cached value and the time it stops being valid.
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// ttlCache is a small thread-safe cache keyed by method name.
+// A zero or negative TTL disables caching entirely.
+type ttlCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+// newTTLCache creates a cache whose entries expire after ttl.
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached value for key if present and not expired.
+func (c *ttlCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set stores value under key until the TTL elapses.
+func (c *ttlCache) set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{value: value, expiresAt: c.now().Add(c.ttl)}
+}
+
+// clear drops all cached entries.
+func (c *ttlCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// cachedSlice returns the cached slice for key, or calls fetch and caches its result.
+// Callers always receive a copy so they cannot mutate the cached data.
+func cachedSlice[T any](ctx context.Context, c *ttlCache, key string, fetch func() ([]T, error)) ([]T, error) {
+	if v, ok := c.get(key); ok {
+		if log := OperationLogFromContext(ctx); log != nil {
+			log.record(Operation{Name: key + " (cached)"})
+		}
+		return slices.Clone(v.([]T)), nil
+	}
+
+	result, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, result)
+	return slices.Clone(result), nil
+}
+
+// InvalidateCache drops all cached library reads. Call it after any operation
+// that changes the user's likes or subscriptions so stale taste data isn't served.
+func (c *Client) InvalidateCache() {
+	c.cache.clear()
+}
@@ -0,0 +1,151 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for ttlCache.now.
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func TestCachedSliceHitMissExpiry(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	c := newTTLCache(time.Minute)
+	c.now = clock.now
+
+	fetches := 0
+	fetch := func() ([]string, error) {
+		fetches++
+		return []string{"a", "b"}, nil
+	}
+	get := func() []string {
+		t.Helper()
+		got, err := cachedSlice(context.Background(), c, "key", fetch)
+		if err != nil {
+			t.Fatalf("cachedSlice: %v", err)
+		}
+		return got
+	}
+
+	// Miss: fetched and cached
+	first := get()
+	if fetches != 1 {
+		t.Fatalf("%d fetches after a miss, want 1", fetches)
+	}
+
+	// Hit: served from the cache as a copy
+	first[0] = "mutated"
+	clock.t = clock.t.Add(59 * time.Second)
+	if got := get(); fetches != 1 || got[0] != "a" {
+		t.Errorf("hit returned %v after %d fetches, want the unmodified cached value and 1 fetch", got, fetches)
+	}
+
+	// Expiry: refetched once the TTL has elapsed
+	clock.t = clock.t.Add(time.Second)
+	get()
+	if fetches != 2 {
+		t.Errorf("%d fetches after expiry, want 2", fetches)
+	}
+}
+
+func TestCachedSliceDoesNotCacheErrors(t *testing.T) {
+	c := newTTLCache(time.Minute)
+	fetches := 0
+	fetch := func() ([]string, error) {
+		fetches++
+		return nil, errors.New("boom")
+	}
+	for range 2 {
+		if _, err := cachedSlice(context.Background(), c, "key", fetch); err == nil {
+			t.Fatal("cachedSlice: want the fetch error")
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("%d fetches, want 2: errors are not cached", fetches)
+	}
+}
+
+func TestTTLCacheDisabled(t *testing.T) {
+	c := newTTLCache(0)
+	c.set("key", []string{"a"})
+	if _, ok := c.get("key"); ok {
+		t.Error("a zero-TTL cache served a value, want caching disabled")
+	}
+}
+
+func TestTTLCacheClear(t *testing.T) {
+	c := newTTLCache(time.Minute)
+	c.set("key", []string{"a"})
+	c.clear()
+	if _, ok := c.get("key"); ok {
+		t.Error("value served after clear")
+	}
+}
+
+func TestLikedVideoSetSeesNewLikeAfterRating(t *testing.T) {
+	var mu sync.Mutex
+	liked := []string{"aaaaaaaaaaa"}
+	likesReads := 0
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"items": []any{map[string]any{
+			"contentDetails": map[string]any{"relatedPlaylists": map[string]any{"likes": "LLme"}},
+		}}})
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		likesReads++
+		var items []any
+		for _, id := range liked {
+			items = append(items, map[string]any{"snippet": map[string]any{"resourceId": map[string]any{"videoId": id}}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"items": items})
+	})
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Query().Get("rating") == "like" {
+			liked = append([]string{r.URL.Query().Get("id")}, liked...)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	c := newTestClientWithOptions(t, api, Options{CacheTTL: time.Hour})
+	ctx := context.Background()
+
+	set, err := c.LikedVideoSet(ctx)
+	if err != nil {
+		t.Fatalf("LikedVideoSet: %v", err)
+	}
+	if _, ok := set["bbbbbbbbbbb"]; ok || len(set) != 1 {
+		t.Fatalf("set before liking = %v, want only aaaaaaaaaaa", set)
+	}
+	if _, err := c.LikedVideoSet(ctx); err != nil || likesReads != 1 {
+		t.Fatalf("second LikedVideoSet read the likes %d times (err %v), want 1 from the cache", likesReads, err)
+	}
+
+	if err := c.RateVideo(ctx, "bbbbbbbbbbb", "like"); err != nil {
+		t.Fatalf("RateVideo: %v", err)
+	}
+	set, err = c.LikedVideoSet(ctx)
+	if err != nil {
+		t.Fatalf("LikedVideoSet after liking: %v", err)
+	}
+	if _, ok := set["bbbbbbbbbbb"]; !ok {
+		t.Errorf("set after liking = %v, want it to contain bbbbbbbbbbb", set)
+	}
+	if likesReads != 2 {
+		t.Errorf("likes read %d times, want 2: rating must drop the cached set", likesReads)
+	}
+}
@@ -0,0 +1,129 @@
+package youtube
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MusicCategoryID is the YouTube video category ID for Music. Use
+// Client.ResolveMusicCategoryID to check it against the user's region.
+const MusicCategoryID = "10"
+
+// categoryIDs maps common YouTube video category names to their IDs.
+// IDs are stable across regions, though not every category is assignable everywhere.
+var categoryIDs = map[string]string{
+	"film":          "1",
+	"autos":         "2",
+	"music":         MusicCategoryID,
+	"pets":          "15",
+	"sports":        "17",
+	"travel":        "19",
+	"gaming":        "20",
+	"people":        "22",
+	"comedy":        "23",
+	"entertainment": "24",
+	"news":          "25",
+	"howto":         "26",
+	"education":     "27",
+	"science":       "28",
+	"nonprofits":    "29",
+}
+
+// ResolveCategoryID converts a category name (e.g. "music", "comedy") or numeric
+// category ID into a YouTube video category ID.
+func ResolveCategoryID(category string) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return "", fmt.Errorf("category cannot be empty")
+	}
+
+	if id, ok := categoryIDs[category]; ok {
+		return id, nil
+	}
+	for _, id := range categoryIDs {
+		if id == category {
+			return id, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown video category %q", category)
+}
+
+// CategoryName returns the short name of a video category ID (e.g. "10" -> "music"),
+// or the ID itself if the category is not known.
+func CategoryName(categoryID string) string {
+	for name, id := range categoryIDs {
+		if id == categoryID {
+			return name
+		}
+	}
+	return categoryID
+}
+
+// VideoCategory is a video category available in a region.
+type VideoCategory struct {
+	ID    string
+	Title string
+	// Assignable reports whether videos can be filed under the category.
+	Assignable bool
+}
+
+// ListVideoCategories returns the video categories of regionCode (ISO 3166-1
+// alpha-2), defaulting to the client's search region, else US.
+// Quota cost: 1 unit.
+func (c *Client) ListVideoCategories(ctx context.Context, regionCode string) ([]VideoCategory, error) {
+	regionCode = strings.ToUpper(cmp.Or(regionCode, c.searchDefaults.RegionCode, "US"))
+	if len(regionCode) != 2 {
+		return nil, fmt.Errorf("invalid region code %q: must be a 2-letter ISO 3166-1 code", regionCode)
+	}
+
+	call := c.startCall(ctx)
+	resp, err := c.service.VideoCategories.List([]string{"snippet"}).RegionCode(regionCode).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "videoCategories.list", c.costs.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list video categories: %w", err)
+	}
+
+	categories := make([]VideoCategory, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		if item.Snippet == nil {
+			continue
+		}
+		categories = append(categories, VideoCategory{
+			ID:         item.Id,
+			Title:      item.Snippet.Title,
+			Assignable: item.Snippet.Assignable,
+		})
+	}
+	return categories, nil
+}
+
+// ResolveMusicCategoryID returns the ID of the Music category in the client's
+// search region, looked up once and then remembered. It falls back to
+// MusicCategoryID if the lookup fails or finds no Music category.
+// Quota cost: 1 unit on the first call.
+func (c *Client) ResolveMusicCategoryID(ctx context.Context) string {
+	c.musicCategory.mu.Lock()
+	defer c.musicCategory.mu.Unlock()
+	if c.musicCategory.id != "" {
+		return c.musicCategory.id
+	}
+
+	categories, err := c.ListVideoCategories(ctx, "")
+	if err != nil {
+		// Don't remember the fallback, so a later call can retry the lookup
+		NoteFallback(ctx, "could not look up the Music category (%v); assumed ID %s", err, MusicCategoryID)
+		return MusicCategoryID
+	}
+	c.musicCategory.id = MusicCategoryID
+	for _, category := range categories {
+		if strings.EqualFold(category.Title, "Music") {
+			c.musicCategory.id = category.ID
+			break
+		}
+	}
+	return c.musicCategory.id
+}
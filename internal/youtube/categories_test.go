@@ -0,0 +1,80 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// categoriesAPI serves a region whose Music category is not 10, and video
+// categories by ID: music* videos are filed under it, the rest under 10.
+func categoriesAPI(lookups *atomic.Int32, failing *atomic.Bool) http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/videoCategories", func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		if failing.Load() {
+			http.Error(w, `{"error":{"code":403,"message":"forbidden"}}`, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"items": []any{
+			map[string]any{"id": "10", "snippet": map[string]any{"title": "Film & Animation"}},
+			map[string]any{"id": "42", "snippet": map[string]any{"title": "Music"}},
+		}})
+	})
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		var items []any
+		for _, id := range queryIDs(r) {
+			category := "10"
+			if strings.HasPrefix(id, "music") {
+				category = "42"
+			}
+			items = append(items, map[string]any{"id": id, "snippet": map[string]any{"categoryId": category}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"items": items})
+	})
+	return api
+}
+
+func TestFilterMusicVideosResolvesRegionalMusicCategory(t *testing.T) {
+	var lookups atomic.Int32
+	var failing atomic.Bool
+	c := newTestClient(t, categoriesAPI(&lookups, &failing))
+	videos := []Video{{ID: "music000001"}, {ID: "film0000001"}, {ID: "music000002"}}
+
+	for range 2 {
+		music, err := c.FilterMusicVideos(context.Background(), videos)
+		if err != nil {
+			t.Fatalf("FilterMusicVideos: %v", err)
+		}
+		if len(music) != 2 || music[0].ID != "music000001" || music[1].ID != "music000002" {
+			t.Errorf("FilterMusicVideos = %v, want the videos filed under category 42", music)
+		}
+	}
+	if got := c.ResolveMusicCategoryID(context.Background()); got != "42" {
+		t.Errorf("ResolveMusicCategoryID = %q, want 42", got)
+	}
+	if n := lookups.Load(); n != 1 {
+		t.Errorf("categories were looked up %d times, want once", n)
+	}
+}
+
+func TestResolveMusicCategoryIDRetriesAfterFailedLookup(t *testing.T) {
+	var lookups atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	c := newTestClient(t, categoriesAPI(&lookups, &failing))
+
+	if got := c.ResolveMusicCategoryID(context.Background()); got != MusicCategoryID {
+		t.Errorf("ResolveMusicCategoryID with a failing lookup = %q, want the %s fallback", got, MusicCategoryID)
+	}
+
+	failing.Store(false)
+	if got := c.ResolveMusicCategoryID(context.Background()); got != "42" {
+		t.Errorf("ResolveMusicCategoryID once the lookup works = %q, want 42", got)
+	}
+}
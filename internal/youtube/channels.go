@@ -0,0 +1,142 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+// Channel represents a YouTube channel's metadata and statistics
+type Channel struct {
+	ID          string
+	Title       string
+	Description string
+	// SubscriberCount is 0 when HiddenSubscriberCount is true.
+	SubscriberCount       uint64
+	HiddenSubscriberCount bool
+	VideoCount            uint64
+	UploadsPlaylistID     string
+}
+
+// GetChannel retrieves metadata and statistics for a channel by ID.
+// Returns nil, nil if the channel is not found (not an error).
+// Costs only 1 quota unit.
+func (c *Client) GetChannel(ctx context.Context, channelID string) (*Channel, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID cannot be empty")
+	}
+
+	call := c.startCall(ctx)
+	resp, err := c.service.Channels.List([]string{"snippet", "statistics", "contentDetails"}).
+		Id(channelID).
+		Context(call.ctx).
+		Do()
+	err = call.done(err)
+	c.addQuota(ctx, "channels.list", c.costs.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel: %w", err)
+	}
+
+	// Channel not found - not an error
+	if len(resp.Items) == 0 {
+		return nil, nil
+	}
+
+	return channelFromAPI(resp.Items[0]), nil
+}
+
+// GetMyChannel retrieves the authenticated user's own channel, identifying
+// which account the server is acting as. Costs only 1 quota unit.
+func (c *Client) GetMyChannel(ctx context.Context) (*Channel, error) {
+	call := c.startCall(ctx)
+	resp, err := c.service.Channels.List([]string{"snippet", "statistics", "contentDetails"}).
+		Mine(true).
+		Context(call.ctx).
+		Do()
+	err = call.done(err)
+	c.addQuota(ctx, "channels.list", c.costs.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get own channel: %w", err)
+	}
+
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("no channel found for authenticated user")
+	}
+
+	return channelFromAPI(resp.Items[0]), nil
+}
+
+// channelFromAPI converts an API channel resource into a domain Channel.
+func channelFromAPI(item *youtube.Channel) *Channel {
+	channel := &Channel{
+		ID: item.Id,
+	}
+	if item.Snippet != nil {
+		channel.Title = item.Snippet.Title
+		channel.Description = item.Snippet.Description
+	}
+	if item.Statistics != nil {
+		channel.HiddenSubscriberCount = item.Statistics.HiddenSubscriberCount
+		if !channel.HiddenSubscriberCount {
+			channel.SubscriberCount = item.Statistics.SubscriberCount
+		}
+		channel.VideoCount = item.Statistics.VideoCount
+	}
+	if item.ContentDetails != nil && item.ContentDetails.RelatedPlaylists != nil {
+		channel.UploadsPlaylistID = item.ContentDetails.RelatedPlaylists.Uploads
+	}
+	return channel
+}
+
+// GetChannelUploads retrieves up to maxResults of a channel's most recent uploads.
+// It resolves the channel's uploads playlist, then pages through it, stopping
+// as soon as maxResults videos are collected. A non-positive maxResults defaults to 50.
+// Quota cost: 1 unit for the channel lookup + 1 unit per 50 videos (far cheaper than search).
+func (c *Client) GetChannelUploads(ctx context.Context, channelID string, maxResults int64) ([]Video, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID cannot be empty")
+	}
+	if maxResults <= 0 {
+		maxResults = 50
+	}
+
+	call := c.startCall(ctx)
+	resp, err := c.service.Channels.List([]string{"contentDetails"}).
+		Id(channelID).
+		Context(call.ctx).
+		Do()
+	err = call.done(err)
+	c.addQuota(ctx, "channels.list", c.costs.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel: %w", err)
+	}
+
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("channel %s not found", channelID)
+	}
+
+	details := resp.Items[0].ContentDetails
+	if details == nil || details.RelatedPlaylists == nil || details.RelatedPlaylists.Uploads == "" {
+		return nil, fmt.Errorf("no uploads playlist found for channel %s", channelID)
+	}
+	uploadsPlaylistID := details.RelatedPlaylists.Uploads
+
+	// Page through uploads, terminating early once enough are collected
+	var videos []Video
+	pageToken := ""
+	for int64(len(videos)) < maxResults {
+		page, nextPageToken, err := c.GetPlaylistItemsPage(ctx, uploadsPlaylistID, pageToken, maxResults-int64(len(videos)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve channel uploads: %w", err)
+		}
+
+		videos = append(videos, page...)
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	return videos, nil
+}
@@ -1,10 +1,14 @@
 package youtube
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 )
@@ -12,25 +16,107 @@ import (
 // Client wraps the YouTube API service with helper methods
 type Client struct {
 	service *youtube.Service
+	cache   *ttlCache
+	quotas  *quotaRegistry
+	// project accounts every call against the Google Cloud project's daily
+	// quota; see Options.ProjectQuota.
+	project *QuotaTracker
+	// apiTimeout bounds each API request; see startCall.
+	apiTimeout time.Duration
+	// searchDefaults supplies the region and language of searches that set none.
+	searchDefaults SearchOptions
+	// defaultPrivacy is the privacy status of playlists created without one.
+	defaultPrivacy string
+	// costs are the quota costs API calls are accounted with.
+	costs QuotaCosts
+	// musicCategory remembers the Music category ID; see ResolveMusicCategoryID.
+	musicCategory struct {
+		mu sync.Mutex
+		id string
+	}
+	// related remembers the user's special playlist IDs; see relatedPlaylists.
+	related struct {
+		mu        sync.Mutex
+		playlists *youtube.ChannelContentDetailsRelatedPlaylists
+	}
+}
+
+// Options configures optional Client behavior.
+type Options struct {
+	// CacheTTL is how long GetLikedVideos and GetSubscriptions results are
+	// served from memory before refetching. Zero disables caching.
+	CacheTTL time.Duration
+
+	// DailyQuota is the daily YouTube Data API quota limit used for usage
+	// tracking. Zero uses DefaultDailyQuota.
+	DailyQuota int
+
+	// SearchRegion and SearchLanguage are the default regionCode and
+	// relevanceLanguage of every search. Empty lets YouTube infer them from
+	// the authenticated account.
+	SearchRegion   string
+	SearchLanguage string
+
+	// APITimeout bounds each API request (each page, for paginated listings).
+	// Zero uses DefaultAPITimeout; negative disables the timeout.
+	APITimeout time.Duration
+
+	// DefaultPrivacy is the privacy status CreatePlaylist uses when none is
+	// given. Empty uses "private".
+	DefaultPrivacy string
+
+	// QuotaCosts overrides the quota cost of API operations. Zero fields use
+	// DefaultQuotaCosts.
+	QuotaCosts QuotaCosts
+
+	// UserAgent identifies this app's requests, e.g. in Google Cloud quota
+	// dashboards. It follows the Google API client's own User-Agent; empty
+	// sends only that.
+	UserAgent string
+
+	// RateLimit caps API requests per second (each page of a listing counts),
+	// smoothing bursts that trip YouTube's rate limits. Zero disables it.
+	RateLimit float64
+
+	// ProjectQuota accounts the calls of every client sharing it, for clients
+	// of one Google Cloud project, such as one per SSE user, that draw on its
+	// single daily quota. Nil gives the client a tracker of its own.
+	ProjectQuota *QuotaTracker
 }
 
 // NewClient creates a new YouTube API client using the provided HTTP client
-func NewClient(ctx context.Context, httpClient *http.Client) (*Client, error) {
-	service, err := youtube.NewService(ctx, option.WithHTTPClient(httpClient))
+func NewClient(ctx context.Context, httpClient *http.Client, opts Options) (*Client, error) {
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(withRateLimit(httpClient, opts.RateLimit)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create youtube service: %w", err)
 	}
+	// option.WithUserAgent only reaches transports the library builds itself,
+	// not a caller's HTTP client, so set it on the service, which appends it
+	// to the User-Agent of every request
+	service.UserAgent = opts.UserAgent
 
 	return &Client{
-		service: service,
+		service:    service,
+		cache:      newTTLCache(opts.CacheTTL),
+		quotas:     newQuotaRegistry(opts.DailyQuota),
+		project:    cmp.Or(opts.ProjectQuota, NewQuotaTracker(opts.DailyQuota)),
+		apiTimeout: cmp.Or(opts.APITimeout, DefaultAPITimeout),
+		searchDefaults: SearchOptions{
+			RegionCode:        opts.SearchRegion,
+			RelevanceLanguage: opts.SearchLanguage,
+		},
+		defaultPrivacy: cmp.Or(opts.DefaultPrivacy, "private"),
+		costs:          opts.QuotaCosts.WithDefaults(),
 	}, nil
 }
 
 // ValidateAuth validates the authenticated user has access to YouTube API
 // by fetching their channel information. Returns the channel name on success.
 func (c *Client) ValidateAuth(ctx context.Context) (string, error) {
-	call := c.service.Channels.List([]string{"snippet"}).Mine(true)
-	resp, err := call.Do()
+	call := c.startCall(ctx)
+	resp, err := c.service.Channels.List([]string{"snippet"}).Mine(true).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "channels.list", c.costs.List)
 	if err != nil {
 		return "", fmt.Errorf("auth validation failed: %w", err)
 	}
@@ -43,58 +129,122 @@ func (c *Client) ValidateAuth(ctx context.Context) (string, error) {
 }
 
 // FilterMusicVideos filters a slice of videos to only those in the Music category
-// (categoryId == "10"). Processes in batches of 50 to stay within API limits.
-// Quota cost: 1 unit per 50 videos.
+// of the client's region (see ResolveMusicCategoryID). Processes in batches of
+// 50 to stay within API limits.
+// Quota cost: 1 unit per 50 videos, plus 1 to resolve the Music category once.
 func (c *Client) FilterMusicVideos(ctx context.Context, videos []Video) ([]Video, error) {
 	if len(videos) == 0 {
 		return videos, nil
 	}
 
-	// Build a map of videoID -> Video for quick lookup
-	videoMap := make(map[string]Video, len(videos))
 	ids := make([]string, 0, len(videos))
 	for _, v := range videos {
 		if v.ID != "" {
-			videoMap[v.ID] = v
 			ids = append(ids, v.ID)
 		}
 	}
 
-	const batchSize = 50
-	musicIDs := make(map[string]struct{})
+	categories, err := c.GetVideoCategories(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < len(ids); i += batchSize {
+	// Return only music videos in original order
+	musicID := c.ResolveMusicCategoryID(ctx)
+	filtered := make([]Video, 0, len(videos))
+	for _, v := range videos {
+		if categories[v.ID] == musicID {
+			filtered = append(filtered, v)
+		}
+	}
+
+	return filtered, nil
+}
+
+// VideoMetadata holds snippet metadata used to classify a video.
+type VideoMetadata struct {
+	CategoryID string
+	Tags       []string
+}
+
+// GetVideoCategories returns a map of video ID to category ID for the given videos.
+// Videos that no longer exist are omitted. Processes in batches of 50 to stay within API limits.
+// Quota cost: 1 unit per 50 videos.
+func (c *Client) GetVideoCategories(ctx context.Context, videoIDs []string) (map[string]string, error) {
+	metadata, err := c.getVideoMetadata(ctx, videoIDs, "items(id,snippet/categoryId)")
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]string, len(metadata))
+	for id, m := range metadata {
+		categories[id] = m.CategoryID
+	}
+	return categories, nil
+}
+
+// GetVideoMetadata returns a map of video ID to category and tags for the given videos.
+// Videos that no longer exist are omitted. Processes in batches of 50 to stay within API limits.
+// Quota cost: 1 unit per 50 videos.
+func (c *Client) GetVideoMetadata(ctx context.Context, videoIDs []string) (map[string]VideoMetadata, error) {
+	return c.getVideoMetadata(ctx, videoIDs, "items(id,snippet/categoryId,snippet/tags)")
+}
+
+// getVideoMetadata fetches snippet metadata restricted to the given response fields.
+func (c *Client) getVideoMetadata(ctx context.Context, videoIDs []string, fields googleapi.Field) (map[string]VideoMetadata, error) {
+	metadata := make(map[string]VideoMetadata, len(videoIDs))
+
+	for _, batch := range batchIDs(videoIDs) {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
 
-		end := min(i+batchSize, len(ids))
-		batch := ids[i:end]
-
+		call := c.startCall(ctx)
 		resp, err := c.service.Videos.
 			List([]string{"snippet"}).
 			Id(batch...).
-			Fields("items(id,snippet/categoryId)").
+			Fields(fields).
+			Context(call.ctx).
 			Do()
+		err = call.done(err)
+		c.addQuota(ctx, "videos.list", c.costs.List)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch video categories: %w", err)
+			return nil, fmt.Errorf("failed to fetch video metadata: %w", err)
 		}
 
 		for _, item := range resp.Items {
-			if item.Snippet != nil && item.Snippet.CategoryId == "10" {
-				musicIDs[item.Id] = struct{}{}
+			if item.Snippet != nil {
+				metadata[item.Id] = VideoMetadata{
+					CategoryID: item.Snippet.CategoryId,
+					Tags:       item.Snippet.Tags,
+				}
 			}
 		}
 	}
 
-	// Return only music videos in original order
-	filtered := make([]Video, 0, len(musicIDs))
-	for _, v := range videos {
-		if _, ok := musicIDs[v.ID]; ok {
-			filtered = append(filtered, v)
+	return metadata, nil
+}
+
+// maxIDsPerRequest is the most IDs the API accepts in a single list call.
+const maxIDsPerRequest = 50
+
+// batchIDs splits IDs into consecutive batches of at most maxIDsPerRequest,
+// dropping empty and duplicate IDs. The final batch may be partial.
+func batchIDs(ids []string) [][]string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, dup := seen[id]; dup || id == "" {
+			continue
 		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
 	}
 
-	return filtered, nil
+	batches := make([][]string, 0, (len(unique)+maxIDsPerRequest-1)/maxIDsPerRequest)
+	for i := 0; i < len(unique); i += maxIDsPerRequest {
+		batches = append(batches, unique[i:min(i+maxIDsPerRequest, len(unique))])
+	}
+	return batches
 }
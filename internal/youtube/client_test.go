@@ -0,0 +1,81 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// redirectTransport sends every request to target, keeping its path and query.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a client that calls api instead of the YouTube Data API.
+func newTestClient(t *testing.T, api http.Handler) *Client {
+	t.Helper()
+	return newTestClientWithOptions(t, api, Options{})
+}
+
+// newTestClientWithOptions is newTestClient with the given options.
+func newTestClientWithOptions(t *testing.T, api http.Handler, opts Options) *Client {
+	t.Helper()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	c, err := NewClient(context.Background(), &http.Client{Transport: redirectTransport{target: target}}, opts)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+// queryIDs returns the IDs of a list request, sent as repeated or comma-separated id parameters.
+func queryIDs(r *http.Request) []string {
+	var ids []string
+	for _, v := range r.URL.Query()["id"] {
+		ids = append(ids, strings.Split(v, ",")...)
+	}
+	return ids
+}
+
+func TestGetVideoMetadataBatchesIDs(t *testing.T) {
+	var batches []int
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		batches = append(batches, len(queryIDs(r)))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[]}`)
+	})
+	c := newTestClient(t, api)
+
+	ids := make([]string, 0, 125)
+	for i := range 120 {
+		ids = append(ids, fmt.Sprintf("video%06d", i))
+	}
+	// Duplicates and empty IDs are dropped rather than sent
+	ids = append(ids, "", "video000000", "video000001", "", "video000119")
+
+	if _, err := c.GetVideoMetadata(context.Background(), ids); err != nil {
+		t.Fatalf("GetVideoMetadata: %v", err)
+	}
+	if !slices.Equal(batches, []int{50, 50, 20}) {
+		t.Errorf("batches of %v IDs, want 3 calls of [50 50 20]", batches)
+	}
+	if used, _ := c.QuotaUsage(context.Background()); used != 3 {
+		t.Errorf("quota used %d, want 3", used)
+	}
+}
@@ -0,0 +1,92 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// ErrCommentsDisabled is returned when a video's comments are turned off.
+var ErrCommentsDisabled = errors.New("comments are disabled for this video")
+
+// Comment is a top-level comment on a video.
+type Comment struct {
+	Author      string
+	Text        string
+	LikeCount   int64
+	ReplyCount  int64
+	PublishedAt string
+}
+
+// GetVideoComments retrieves up to maxResults of a video's top-level comments,
+// most relevant first. A non-positive maxResults defaults to 20. Returns
+// ErrCommentsDisabled when the video has comments turned off.
+// Quota cost: 1 unit per page of up to 100 comments.
+func (c *Client) GetVideoComments(ctx context.Context, videoID string, maxResults int64) ([]Comment, error) {
+	videoID, err := ParseVideoID(videoID)
+	if err != nil {
+		return nil, err
+	}
+	if maxResults <= 0 {
+		maxResults = 20
+	}
+
+	var comments []Comment
+	pageToken := ""
+	for int64(len(comments)) < maxResults {
+		listCall := c.service.CommentThreads.List([]string{"snippet"}).
+			VideoId(videoID).
+			Order("relevance").
+			TextFormat("plainText").
+			MaxResults(min(maxResults-int64(len(comments)), 100))
+		if pageToken != "" {
+			listCall = listCall.PageToken(pageToken)
+		}
+
+		call := c.startCall(ctx)
+		resp, err := listCall.Context(call.ctx).Do()
+		err = call.done(err)
+		c.addQuota(ctx, "commentThreads.list", c.costs.List)
+		if err != nil {
+			var apiErr *googleapi.Error
+			if errors.As(err, &apiErr) {
+				for _, item := range apiErr.Errors {
+					if item.Reason == "commentsDisabled" {
+						return nil, ErrCommentsDisabled
+					}
+				}
+			}
+			return nil, fmt.Errorf("failed to get video comments: %w", err)
+		}
+
+		for _, thread := range resp.Items {
+			if comment, ok := commentFromThread(thread); ok {
+				comments = append(comments, comment)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return comments, nil
+}
+
+// commentFromThread converts an API comment thread into its top-level Comment.
+func commentFromThread(thread *youtube.CommentThread) (Comment, bool) {
+	if thread.Snippet == nil || thread.Snippet.TopLevelComment == nil || thread.Snippet.TopLevelComment.Snippet == nil {
+		return Comment{}, false
+	}
+	snippet := thread.Snippet.TopLevelComment.Snippet
+	return Comment{
+		Author:      snippet.AuthorDisplayName,
+		Text:        snippet.TextDisplay,
+		LikeCount:   snippet.LikeCount,
+		ReplyCount:  thread.Snippet.TotalReplyCount,
+		PublishedAt: snippet.PublishedAt,
+	}, true
+}
@@ -0,0 +1,49 @@
+package youtube
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// isoDurationRe matches the ISO 8601 durations returned by the API, e.g.
+// "PT4M30S", "PT1H2M3S", "P1DT2H" or "P0D" (livestreams).
+var isoDurationRe = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseISODuration parses an ISO 8601 duration such as "PT4M30S".
+// An empty string (e.g. an upcoming livestream) parses as zero.
+func ParseISODuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	match := isoDurationRe.FindStringSubmatch(value)
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %w", value, err)
+		}
+		total += time.Duration(n) * unit
+	}
+	return total, nil
+}
+
+// FormatDuration renders a duration as "m:ss", or "h:mm:ss" when it is an hour or longer.
+func FormatDuration(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,52 @@
+package youtube
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"PT1H2M3S", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"PT30S", 30 * time.Second, false},
+		{"PT4M", 4 * time.Minute, false},
+		{"P1DT1S", 24*time.Hour + time.Second, false},
+		// Livestreams report P0D, unknown durations are empty
+		{"P0D", 0, false},
+		{"", 0, false},
+		{"PT", 0, true},
+		{"1:02:03", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseISODuration(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseISODuration(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestVideoDetailFromAPIDuration(t *testing.T) {
+	tests := []struct {
+		duration    string
+		wantSeconds int64
+		wantHuman   string
+	}{
+		{"PT1H2M3S", 3723, "1:02:03"},
+		{"PT30S", 30, "0:30"},
+		{"P0D", 0, ""},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		item := &youtube.Video{Id: "song0000001", ContentDetails: &youtube.VideoContentDetails{Duration: tt.duration}}
+		detail := videoDetailFromAPI(item)
+		if detail.DurationSeconds != tt.wantSeconds || detail.DurationHuman != tt.wantHuman {
+			t.Errorf("duration %q: got %d seconds, %q; want %d, %q", tt.duration, detail.DurationSeconds, detail.DurationHuman, tt.wantSeconds, tt.wantHuman)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/api/googleapi"
+)
+
+// ErrPlaylistNotAccessible is returned when a playlist exists but is private,
+// or its items are otherwise hidden from the authenticated account.
+var ErrPlaylistNotAccessible = errors.New("playlist not accessible")
+
+// ErrPlaylistNotFound is returned when a playlist does not exist.
+var ErrPlaylistNotFound = errors.New("playlist not found")
+
+// playlistError marks err with ErrPlaylistNotAccessible or ErrPlaylistNotFound
+// when YouTube's error reason says so, and returns other errors unchanged.
+func playlistError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	for _, item := range apiErr.Errors {
+		switch item.Reason {
+		case "playlistItemsNotAccessible", "forbidden":
+			return fmt.Errorf("%w: %w", ErrPlaylistNotAccessible, err)
+		case "playlistNotFound":
+			return fmt.Errorf("%w: %w", ErrPlaylistNotFound, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestPlaylistError(t *testing.T) {
+	apiError := func(code int, reason string) error {
+		return fmt.Errorf("wrapped: %w", &googleapi.Error{
+			Code:   code,
+			Errors: []googleapi.ErrorItem{{Reason: reason}},
+		})
+	}
+
+	tests := []struct {
+		reason string
+		code   int
+		want   error // nil when the error is returned unchanged
+	}{
+		{"playlistItemsNotAccessible", http.StatusForbidden, ErrPlaylistNotAccessible},
+		{"forbidden", http.StatusForbidden, ErrPlaylistNotAccessible},
+		{"playlistNotFound", http.StatusNotFound, ErrPlaylistNotFound},
+		{"quotaExceeded", http.StatusForbidden, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			err := apiError(tt.code, tt.reason)
+			got := playlistError(err)
+
+			var apiErr *googleapi.Error
+			if !errors.As(got, &apiErr) {
+				t.Errorf("playlistError(%v) lost the API error", err)
+			}
+			if tt.want == nil {
+				if got != err {
+					t.Errorf("playlistError(%v) = %v, want it unchanged", err, got)
+				}
+				return
+			}
+			if !errors.Is(got, tt.want) {
+				t.Errorf("playlistError(%v) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+
+	if err := errors.New("network down"); playlistError(err) != err {
+		t.Error("playlistError changed a non-API error")
+	}
+}
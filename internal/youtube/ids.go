@@ -0,0 +1,136 @@
+package youtube
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// videoIDLength is the length of every YouTube video ID.
+const videoIDLength = 11
+
+// ParseVideoID extracts the video ID from a bare ID or a YouTube URL:
+// youtube.com/watch?v=, music.youtube.com/watch?v=, m.youtube.com,
+// youtu.be/<id>, and the /shorts/, /embed/, /live/ and /v/ paths. The scheme
+// may be omitted and other query parameters may appear in any order.
+func ParseVideoID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if isVideoID(input) {
+		return input, nil
+	}
+
+	if !strings.ContainsAny(input, "./") {
+		return "", fmt.Errorf("invalid video ID %q: expected an 11-character video ID or a YouTube URL", input)
+	}
+
+	raw := input
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid video URL %q: %w", input, err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var id string
+	switch host {
+	case "youtu.be":
+		id = segments[0]
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		switch segments[0] {
+		case "watch":
+			id = u.Query().Get("v")
+		case "shorts", "embed", "live", "v":
+			if len(segments) > 1 {
+				id = segments[1]
+			}
+		}
+	default:
+		return "", fmt.Errorf("invalid video URL %q: not a YouTube URL", input)
+	}
+
+	if !isVideoID(id) {
+		return "", fmt.Errorf("invalid video URL %q: no video ID found", input)
+	}
+	return id, nil
+}
+
+// minPlaylistIDLength is the length of the shortest playlist IDs, the
+// two-letter system playlists such as LL (liked videos) and WL (watch later).
+const minPlaylistIDLength = 2
+
+// ParsePlaylistID extracts the playlist ID from a bare ID or a YouTube URL
+// carrying a list= parameter, such as music.youtube.com/playlist?list=PL...
+// or a watch?v=...&list=... link. YouTube Music browse links
+// (music.youtube.com/browse/VLPL...) are accepted too.
+func ParsePlaylistID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if !strings.ContainsAny(input, "./") {
+		if !isPlaylistID(input) {
+			return "", fmt.Errorf("invalid playlist ID %q: expected a playlist ID or a YouTube playlist URL", input)
+		}
+		return input, nil
+	}
+
+	raw := input
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid playlist URL %q: %w", input, err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch host {
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtu.be":
+	default:
+		return "", fmt.Errorf("invalid playlist URL %q: not a YouTube URL", input)
+	}
+
+	id := u.Query().Get("list")
+	if browseID, ok := strings.CutPrefix(u.Path, "/browse/VL"); ok && id == "" {
+		id = browseID
+	}
+	if !isPlaylistID(id) {
+		return "", fmt.Errorf("invalid playlist URL %q: no list= playlist ID found", input)
+	}
+	return id, nil
+}
+
+// isPlaylistID reports whether s has the shape of a playlist ID: at least two
+// letters, digits, '-' and '_'.
+func isPlaylistID(s string) bool {
+	return len(s) >= minPlaylistIDLength && isIDChars(s)
+}
+
+// parseVideoIDs applies ParseVideoID to each input, failing on the first invalid one.
+func parseVideoIDs(inputs []string) ([]string, error) {
+	ids := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		id, err := ParseVideoID(input)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// isVideoID reports whether s has the shape of a video ID: 11 characters of
+// letters, digits, '-' and '_'.
+func isVideoID(s string) bool {
+	return len(s) == videoIDLength && isIDChars(s)
+}
+
+// isIDChars reports whether s consists only of the characters of YouTube IDs.
+func isIDChars(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestParseVideoIDURLShapes(t *testing.T) {
+	const id = "dQw4w9WgXcQ"
+	tests := []string{
+		id,
+		"  " + id + "\n",
+		"https://www.youtube.com/watch?v=" + id,
+		"https://youtube.com/watch?v=" + id,
+		"http://m.youtube.com/watch?v=" + id,
+		"https://music.youtube.com/watch?v=" + id,
+		"youtube.com/watch?v=" + id,
+		"https://youtu.be/" + id,
+		"youtu.be/" + id + "?si=abc123",
+		"https://www.youtube.com/shorts/" + id,
+		"https://www.youtube.com/embed/" + id,
+		"https://www.youtube.com/live/" + id + "?feature=share",
+		"https://www.youtube.com/v/" + id,
+		"https://WWW.YouTube.com/watch?v=" + id,
+	}
+	for _, input := range tests {
+		got, err := ParseVideoID(input)
+		if err != nil || got != id {
+			t.Errorf("ParseVideoID(%q) = %q, %v; want %q", input, got, err, id)
+		}
+	}
+}
+
+func TestParseVideoIDQueryOrder(t *testing.T) {
+	const id = "dQw4w9WgXcQ"
+	tests := []string{
+		"https://www.youtube.com/watch?v=" + id + "&list=PLabc&index=2",
+		"https://www.youtube.com/watch?list=PLabc&v=" + id,
+		"https://music.youtube.com/watch?si=xyz&list=RDAMVM&v=" + id + "&feature=share",
+		"https://www.youtube.com/watch?t=42s&v=" + id + "#comments",
+	}
+	for _, input := range tests {
+		got, err := ParseVideoID(input)
+		if err != nil || got != id {
+			t.Errorf("ParseVideoID(%q) = %q, %v; want %q", input, got, err, id)
+		}
+	}
+}
+
+func TestParseVideoIDRejectsInvalidInput(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"dQw4w9WgXc",   // 10 characters
+		"dQw4w9WgXcQQ", // 12 characters
+		"dQw4w9WgX!Q",
+		"never gonna give you up",
+		"https://vimeo.com/watch?v=dQw4w9WgXcQ",
+		"https://www.youtube.com/watch",
+		"https://www.youtube.com/watch?v=short",
+		"https://www.youtube.com/playlist?list=PLabc",
+		"https://www.youtube.com/shorts/",
+		"https://youtu.be/",
+		"https://www.youtube.com/channel/UCabcdefghij",
+	}
+	for _, input := range tests {
+		if got, err := ParseVideoID(input); err == nil {
+			t.Errorf("ParseVideoID(%q) = %q, want an error", input, got)
+		}
+	}
+}
+
+func TestVideoMethodsAcceptURLs(t *testing.T) {
+	const id = "dQw4w9WgXcQ"
+	const url = "https://music.youtube.com/watch?v=" + id + "&list=RDAMVM" + id
+	var videoIDs []string
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/videos", func(w http.ResponseWriter, r *http.Request) {
+		videoIDs = append(videoIDs, queryIDs(r)...)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"items":[{"id":%q,"snippet":{"title":"Never Gonna Give You Up"}}]}`, id)
+	})
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		videoIDs = append(videoIDs, r.URL.Query().Get("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[]}`)
+	})
+	api.HandleFunc("POST /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		var item struct {
+			Snippet struct {
+				ResourceID struct {
+					VideoID string `json:"videoId"`
+				} `json:"resourceId"`
+			} `json:"snippet"`
+		}
+		json.NewDecoder(r.Body).Decode(&item)
+		videoIDs = append(videoIDs, item.Snippet.ResourceID.VideoID)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"item-1"}`)
+	})
+	c := newTestClient(t, api)
+	ctx := context.Background()
+
+	if _, err := c.GetVideo(ctx, url); err != nil {
+		t.Errorf("GetVideo: %v", err)
+	}
+	if err := c.RateVideo(ctx, "https://youtu.be/"+id+"?si=abc", "like"); err != nil {
+		t.Errorf("RateVideo: %v", err)
+	}
+	if _, err := c.AddVideosToPlaylist(ctx, "PLtest", []string{url}); err != nil {
+		t.Errorf("AddVideosToPlaylist: %v", err)
+	}
+	if want := []string{id, id, id}; !slices.Equal(videoIDs, want) {
+		t.Errorf("API called with video IDs %q, want %q", videoIDs, want)
+	}
+
+	// An invalid ID fails before reaching the API
+	videoIDs = nil
+	if _, err := c.GetVideo(ctx, "not a video"); err == nil {
+		t.Error("GetVideo accepted an invalid ID")
+	}
+	if err := c.RateVideo(ctx, "https://example.com/watch?v="+id, "like"); err == nil {
+		t.Error("RateVideo accepted a non-YouTube URL")
+	}
+	if _, err := c.AddVideosToPlaylist(ctx, "PLtest", []string{id, "bad"}); err == nil {
+		t.Error("AddVideosToPlaylist accepted an invalid ID")
+	}
+	if len(videoIDs) != 0 {
+		t.Errorf("API called with %q for invalid IDs", videoIDs)
+	}
+}
+
+func TestParsePlaylistID(t *testing.T) {
+	const id = "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf"
+	tests := []string{
+		id,
+		" " + id + "\n",
+		"LL",
+		"https://music.youtube.com/playlist?list=" + id,
+		"https://www.youtube.com/playlist?list=" + id,
+		"youtube.com/playlist?list=" + id,
+		"https://music.youtube.com/playlist?list=" + id + "&si=abc123",
+		"https://www.youtube.com/playlist?feature=share&list=" + id + "&index=3",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=" + id + "&index=2",
+		"https://youtu.be/dQw4w9WgXcQ?list=" + id,
+		"https://music.youtube.com/browse/VL" + id,
+	}
+	for _, input := range tests {
+		want := id
+		if input == "LL" {
+			want = "LL"
+		}
+		got, err := ParsePlaylistID(input)
+		if err != nil || got != want {
+			t.Errorf("ParsePlaylistID(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+}
+
+func TestParsePlaylistIDRejectsInvalidInput(t *testing.T) {
+	tests := []string{
+		"",
+		"P",
+		"my favourite playlist",
+		"PL!abc",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://music.youtube.com/playlist",
+		"https://music.youtube.com/playlist?list=",
+		"https://example.com/playlist?list=PLabc",
+	}
+	for _, input := range tests {
+		if got, err := ParsePlaylistID(input); err == nil {
+			t.Errorf("ParsePlaylistID(%q) = %q, want an error", input, got)
+		}
+	}
+}
+
+func TestGetPlaylistItemsAcceptsURL(t *testing.T) {
+	const id = "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf"
+	var got string
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("playlistId")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[]}`)
+	})
+	c := newTestClient(t, api)
+
+	if _, err := c.GetPlaylistItems(context.Background(), "https://music.youtube.com/playlist?list="+id+"&si=abc123"); err != nil {
+		t.Fatalf("GetPlaylistItems: %v", err)
+	}
+	if got != id {
+		t.Errorf("playlistId = %q, want %q", got, id)
+	}
+}
@@ -0,0 +1,77 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Operation is a single YouTube API call and its estimated quota cost.
+type Operation struct {
+	Name string
+	Cost int
+}
+
+// OperationLog records the YouTube API operations and notable fallbacks of a
+// single tool call. It is safe for concurrent use.
+type OperationLog struct {
+	mu         sync.Mutex
+	operations []Operation
+	notes      []string
+}
+
+// operationLogKey is the context key for the operation log.
+type operationLogKey struct{}
+
+// WithOperationLog returns a context whose YouTube API calls are recorded in log.
+func WithOperationLog(ctx context.Context, log *OperationLog) context.Context {
+	return context.WithValue(ctx, operationLogKey{}, log)
+}
+
+// OperationLogFromContext returns the operation log stored in ctx, or nil if none.
+func OperationLogFromContext(ctx context.Context) *OperationLog {
+	log, _ := ctx.Value(operationLogKey{}).(*OperationLog)
+	return log
+}
+
+// NoteFallback records a fallback taken while serving the call in ctx.
+// It does nothing if ctx carries no operation log.
+func NoteFallback(ctx context.Context, format string, args ...any) {
+	if log := OperationLogFromContext(ctx); log != nil {
+		log.mu.Lock()
+		defer log.mu.Unlock()
+		log.notes = append(log.notes, fmt.Sprintf(format, args...))
+	}
+}
+
+// Operations returns the recorded operations in call order.
+func (l *OperationLog) Operations() []Operation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Operation(nil), l.operations...)
+}
+
+// TotalCost returns the quota units spent by the recorded operations.
+func (l *OperationLog) TotalCost() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	total := 0
+	for _, op := range l.operations {
+		total += op.Cost
+	}
+	return total
+}
+
+// Fallbacks returns the recorded fallback notes.
+func (l *OperationLog) Fallbacks() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.notes...)
+}
+
+// record appends an operation to the log.
+func (l *OperationLog) record(op Operation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.operations = append(l.operations, op)
+}
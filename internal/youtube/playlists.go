@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"google.golang.org/api/googleapi"
 	youtube_v3 "google.golang.org/api/youtube/v3"
@@ -15,6 +18,42 @@ type Video struct {
 	ID           string
 	Title        string
 	ChannelTitle string
+	// AddedAt is when the video was added to the playlist (for the likes
+	// playlist: when it was liked). Zero if unknown.
+	AddedAt time.Time
+	// PlaylistItemID identifies this entry within its playlist; needed to remove it.
+	PlaylistItemID string
+	// Position is the zero-based position of the entry within its playlist.
+	Position int64
+	// ThumbnailURL is the video's high-quality thumbnail, else its default
+	// one; empty when it has none (e.g. deleted videos).
+	ThumbnailURL string
+}
+
+// unavailableTitles are the placeholder titles YouTube gives playlist entries
+// whose video was deleted or made private.
+var unavailableTitles = map[string]struct{}{
+	"Deleted video": {},
+	"Private video": {},
+}
+
+// Unavailable reports whether the video was deleted or made private, so it
+// can neither be played nor re-added to a playlist.
+func (v Video) Unavailable() bool {
+	_, placeholder := unavailableTitles[v.Title]
+	return placeholder || v.ID == ""
+}
+
+// SkipUnavailable returns videos without the deleted and private ones, and how
+// many were dropped.
+func SkipUnavailable(videos []Video) ([]Video, int) {
+	available := make([]Video, 0, len(videos))
+	for _, v := range videos {
+		if !v.Unavailable() {
+			available = append(available, v)
+		}
+	}
+	return available, len(videos) - len(available)
 }
 
 type Playlist struct {
@@ -22,34 +61,107 @@ type Playlist struct {
 	Title       string
 	Description string
 	ItemCount   int64
+	// PrivacyStatus is public, private or unlisted; empty when the status
+	// part was not requested.
+	PrivacyStatus string
+	// CreatedAt is when the playlist was created. Zero if unknown.
+	CreatedAt time.Time
 }
 
 // GetLikedVideos retrieves ALL of the user's liked videos with no pagination cap.
+// Results are cached for the client's cache TTL.
 func (c *Client) GetLikedVideos(ctx context.Context) ([]Video, error) {
-	// First, get the likes playlist ID
-	channelsCall := c.service.Channels.List([]string{"contentDetails"}).Mine(true)
-	channelsResp, err := channelsCall.Do()
+	return cachedSlice(ctx, c.cache, cacheKeyLikedVideos, func() ([]Video, error) {
+		return c.fetchLikedVideos(ctx, 0)
+	})
+}
+
+// GetRecentLikedVideos retrieves the user's limit most recently liked videos,
+// or all of them when limit is not positive. Only the pages needed are
+// fetched, unless the full list is already cached. Results are cached for the
+// client's cache TTL.
+// Quota cost: 1 unit + 1 unit per 50 videos.
+func (c *Client) GetRecentLikedVideos(ctx context.Context, limit int) ([]Video, error) {
+	if limit <= 0 {
+		return c.GetLikedVideos(ctx)
+	}
+	if v, ok := c.cache.get(cacheKeyLikedVideos); ok {
+		if log := OperationLogFromContext(ctx); log != nil {
+			log.record(Operation{Name: cacheKeyLikedVideos + " (cached)"})
+		}
+		videos := v.([]Video)
+		return slices.Clone(videos[:min(limit, len(videos))]), nil
+	}
+
+	return cachedSlice(ctx, c.cache, fmt.Sprintf("%s:recent%d", cacheKeyLikedVideos, limit), func() ([]Video, error) {
+		return c.fetchLikedVideos(ctx, limit)
+	})
+}
+
+// LikedVideoSet returns the IDs of the user's liked videos as a set, for
+// membership checks. It is cached alongside GetLikedVideos and dropped with it
+// by InvalidateCache.
+func (c *Client) LikedVideoSet(ctx context.Context) (map[string]struct{}, error) {
+	if v, ok := c.cache.get(cacheKeyLikedVideoSet); ok {
+		if log := OperationLogFromContext(ctx); log != nil {
+			log.record(Operation{Name: cacheKeyLikedVideoSet + " (cached)"})
+		}
+		return maps.Clone(v.(map[string]struct{})), nil
+	}
+
+	videos, err := c.GetLikedVideos(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get likes playlist ID: %w", err)
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(videos))
+	for _, v := range videos {
+		set[v.ID] = struct{}{}
 	}
 
-	if len(channelsResp.Items) == 0 {
-		return nil, fmt.Errorf("no channel found for authenticated user")
+	c.cache.set(cacheKeyLikedVideoSet, set)
+	return maps.Clone(set), nil
+}
+
+// errEnoughPages stops a paginated listing once it has fetched what is needed.
+var errEnoughPages = errors.New("enough pages fetched")
+
+// fetchLikedVideos fetches the liked videos from the API, bypassing the cache.
+// A positive limit stops after the limit most recent likes.
+func (c *Client) fetchLikedVideos(ctx context.Context, limit int) ([]Video, error) {
+	// First, get the likes playlist ID
+	related, err := c.relatedPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get likes playlist ID: %w", err)
 	}
 
-	likesPlaylistID := channelsResp.Items[0].ContentDetails.RelatedPlaylists.Likes
+	likesPlaylistID := related.Likes
 	if likesPlaylistID == "" {
 		return nil, fmt.Errorf("no likes playlist found")
 	}
 
-	// Retrieve all liked videos using pagination (no cap)
+	// Retrieve liked videos using pagination, newest first (no cap unless limited)
 	var videos []Video
+	fetched := 0
 	playlistItemsCall := c.service.PlaylistItems.
 		List([]string{"snippet"}).
 		PlaylistId(likesPlaylistID).
 		MaxResults(50)
 
-	err = playlistItemsCall.Pages(ctx, func(response *youtube_v3.PlaylistItemListResponse) error {
+	call := c.startCall(ctx)
+	err = prefetchPages(call.ctx, func(ctx context.Context, page func(*youtube_v3.PlaylistItemListResponse) error) error {
+		return playlistItemsCall.Pages(ctx, func(response *youtube_v3.PlaylistItemListResponse) error {
+			call.nextPage()
+			c.addQuota(ctx, "playlistItems.list", c.costs.List)
+			if err := page(response); err != nil {
+				return err
+			}
+			fetched += len(response.Items)
+			if limit > 0 && fetched >= limit {
+				return errEnoughPages
+			}
+			return nil
+		})
+	}, func(response *youtube_v3.PlaylistItemListResponse) error {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return err
@@ -57,32 +169,43 @@ func (c *Client) GetLikedVideos(ctx context.Context) ([]Video, error) {
 
 		// Extract videos from this page
 		for _, item := range response.Items {
-			videos = append(videos, Video{
-				ID:           item.Snippet.ResourceId.VideoId,
-				Title:        item.Snippet.Title,
-				ChannelTitle: item.Snippet.VideoOwnerChannelTitle,
-			})
+			videos = append(videos, videoFromPlaylistItem(item))
 		}
 
 		return nil
 	})
+	err = call.done(err)
+	if errors.Is(err, errEnoughPages) {
+		err = nil
+	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve liked videos: %w", err)
 	}
 
+	if limit > 0 && len(videos) > limit {
+		videos = videos[:limit]
+	}
 	return videos, nil
 }
 
-// ListPlaylists retrieves ALL of the user's playlists with no pagination cap.
+// ListPlaylists retrieves ALL of the user's playlists, with their privacy
+// status, with no pagination cap.
 func (c *Client) ListPlaylists(ctx context.Context) ([]Playlist, error) {
 	var playlists []Playlist
 	playlistsCall := c.service.Playlists.
-		List([]string{"snippet", "contentDetails"}).
+		List([]string{"snippet", "status", "contentDetails"}).
 		Mine(true).
 		MaxResults(50)
 
-	err := playlistsCall.Pages(ctx, func(response *youtube_v3.PlaylistListResponse) error {
+	call := c.startCall(ctx)
+	err := prefetchPages(call.ctx, func(ctx context.Context, page func(*youtube_v3.PlaylistListResponse) error) error {
+		return playlistsCall.Pages(ctx, func(response *youtube_v3.PlaylistListResponse) error {
+			call.nextPage()
+			c.addQuota(ctx, "playlists.list", c.costs.List)
+			return page(response)
+		})
+	}, func(response *youtube_v3.PlaylistListResponse) error {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return err
@@ -90,16 +213,12 @@ func (c *Client) ListPlaylists(ctx context.Context) ([]Playlist, error) {
 
 		// Extract playlists from this page
 		for _, item := range response.Items {
-			playlists = append(playlists, Playlist{
-				ID:          item.Id,
-				Title:       item.Snippet.Title,
-				Description: item.Snippet.Description,
-				ItemCount:   item.ContentDetails.ItemCount,
-			})
+			playlists = append(playlists, playlistFromAPI(item))
 		}
 
 		return nil
 	})
+	err = call.done(err)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list playlists: %w", err)
@@ -108,12 +227,44 @@ func (c *Client) ListPlaylists(ctx context.Context) ([]Playlist, error) {
 	return playlists, nil
 }
 
+// ListPlaylistsPage retrieves a single page of the user's playlists.
+// Pass the returned nextPageToken to fetch the following page; it is empty on the last page.
+// Quota cost: 1 unit.
+func (c *Client) ListPlaylistsPage(ctx context.Context, pageToken string, maxResults int64) ([]Playlist, string, error) {
+	listCall := c.service.Playlists.
+		List([]string{"snippet", "status", "contentDetails"}).
+		Mine(true).
+		MaxResults(clampPageSize(maxResults))
+	if pageToken != "" {
+		listCall = listCall.PageToken(pageToken)
+	}
+
+	call := c.startCall(ctx)
+	resp, err := listCall.Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "playlists.list", c.costs.List)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	playlists := make([]Playlist, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		playlists = append(playlists, playlistFromAPI(item))
+	}
+
+	return playlists, resp.NextPageToken, nil
+}
+
 // GetPlaylistItems retrieves ALL videos from a specific playlist with no pagination cap.
 func (c *Client) GetPlaylistItems(ctx context.Context, playlistID string) ([]Video, error) {
 	// Validate input
 	if playlistID == "" {
 		return nil, fmt.Errorf("playlistID cannot be empty")
 	}
+	playlistID, err := ParsePlaylistID(playlistID)
+	if err != nil {
+		return nil, err
+	}
 
 	var videos []Video
 	playlistItemsCall := c.service.PlaylistItems.
@@ -121,7 +272,14 @@ func (c *Client) GetPlaylistItems(ctx context.Context, playlistID string) ([]Vid
 		PlaylistId(playlistID).
 		MaxResults(50)
 
-	err := playlistItemsCall.Pages(ctx, func(response *youtube_v3.PlaylistItemListResponse) error {
+	call := c.startCall(ctx)
+	err = prefetchPages(call.ctx, func(ctx context.Context, page func(*youtube_v3.PlaylistItemListResponse) error) error {
+		return playlistItemsCall.Pages(ctx, func(response *youtube_v3.PlaylistItemListResponse) error {
+			call.nextPage()
+			c.addQuota(ctx, "playlistItems.list", c.costs.List)
+			return page(response)
+		})
+	}, func(response *youtube_v3.PlaylistItemListResponse) error {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return err
@@ -129,24 +287,59 @@ func (c *Client) GetPlaylistItems(ctx context.Context, playlistID string) ([]Vid
 
 		// Extract videos from this page
 		for _, item := range response.Items {
-			videos = append(videos, Video{
-				ID:           item.Snippet.ResourceId.VideoId,
-				Title:        item.Snippet.Title,
-				ChannelTitle: item.Snippet.VideoOwnerChannelTitle,
-			})
+			videos = append(videos, videoFromPlaylistItem(item))
 		}
 
 		return nil
 	})
+	err = call.done(err)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve playlist items: %w", err)
+		return nil, fmt.Errorf("failed to retrieve playlist items: %w", playlistError(err))
 	}
 
 	return videos, nil
 }
 
+// GetPlaylistItemsPage retrieves a single page of videos from a specific playlist.
+// Pass the returned nextPageToken to fetch the following page; it is empty on the last page.
+// Quota cost: 1 unit.
+func (c *Client) GetPlaylistItemsPage(ctx context.Context, playlistID, pageToken string, maxResults int64) ([]Video, string, error) {
+	// Validate input
+	if playlistID == "" {
+		return nil, "", fmt.Errorf("playlistID cannot be empty")
+	}
+	playlistID, err := ParsePlaylistID(playlistID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	listCall := c.service.PlaylistItems.
+		List([]string{"snippet"}).
+		PlaylistId(playlistID).
+		MaxResults(clampPageSize(maxResults))
+	if pageToken != "" {
+		listCall = listCall.PageToken(pageToken)
+	}
+
+	call := c.startCall(ctx)
+	resp, err := listCall.Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "playlistItems.list", c.costs.List)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve playlist items: %w", playlistError(err))
+	}
+
+	videos := make([]Video, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		videos = append(videos, videoFromPlaylistItem(item))
+	}
+
+	return videos, resp.NextPageToken, nil
+}
+
 // CreatePlaylist creates a new playlist on the user's YouTube Music account.
+// An empty privacyStatus uses the client's default privacy (Options.DefaultPrivacy).
 // Quota cost: 50 units.
 func (c *Client) CreatePlaylist(ctx context.Context, title, description, privacyStatus string) (*Playlist, error) {
 	// Validate title is non-empty
@@ -154,9 +347,9 @@ func (c *Client) CreatePlaylist(ctx context.Context, title, description, privacy
 		return nil, fmt.Errorf("title cannot be empty")
 	}
 
-	// Default privacyStatus to "private" if empty
+	// Default privacyStatus to the configured default if empty
 	if privacyStatus == "" {
-		privacyStatus = "private"
+		privacyStatus = c.defaultPrivacy
 	}
 
 	// Validate privacyStatus
@@ -176,8 +369,10 @@ func (c *Client) CreatePlaylist(ctx context.Context, title, description, privacy
 		},
 	}
 
-	call := c.service.Playlists.Insert([]string{"snippet", "status"}, playlist)
-	resp, err := call.Do()
+	call := c.startCall(ctx)
+	resp, err := c.service.Playlists.Insert([]string{"snippet", "status"}, playlist).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "playlists.insert", c.costs.Write)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create playlist: %w", err)
 	}
@@ -191,17 +386,35 @@ func (c *Client) CreatePlaylist(ctx context.Context, title, description, privacy
 	}, nil
 }
 
-// AddVideosToPlaylist adds one or more videos to an existing playlist.
+// AddVideosToPlaylist adds one or more videos, by ID or URL, to the end of an existing playlist.
 // Duplicates are skipped silently. Returns the count of successfully added videos.
 // Quota cost: 50 units per video added.
 func (c *Client) AddVideosToPlaylist(ctx context.Context, playlistID string, videoIDs []string) (int, error) {
+	return c.AddVideosToPlaylistAt(ctx, playlistID, videoIDs, nil)
+}
+
+// AddVideosToPlaylistAt is AddVideosToPlaylist inserting the videos, in order,
+// starting at the zero-based position, shifting the items from there down.
+// A nil position appends. Quota cost: 50 units per video added.
+func (c *Client) AddVideosToPlaylistAt(ctx context.Context, playlistID string, videoIDs []string, position *int64) (int, error) {
 	// Validate inputs
 	if playlistID == "" {
 		return 0, fmt.Errorf("playlistID cannot be empty")
 	}
+	playlistID, err := ParsePlaylistID(playlistID)
+	if err != nil {
+		return 0, err
+	}
 	if len(videoIDs) == 0 {
 		return 0, fmt.Errorf("videoIDs cannot be empty")
 	}
+	videoIDs, err = parseVideoIDs(videoIDs)
+	if err != nil {
+		return 0, err
+	}
+	if position != nil && *position < 0 {
+		return 0, fmt.Errorf("invalid position %d: must be 0 or more", *position)
+	}
 
 	successCount := 0
 
@@ -222,10 +435,17 @@ func (c *Client) AddVideosToPlaylist(ctx context.Context, playlistID string, vid
 				},
 			},
 		}
+		if position != nil {
+			// Skipped duplicates take no slot, so the batch stays contiguous
+			playlistItem.Snippet.Position = *position + int64(successCount)
+			playlistItem.Snippet.ForceSendFields = []string{"Position"}
+		}
 
 		// Insert the item
-		call := c.service.PlaylistItems.Insert([]string{"snippet"}, playlistItem)
-		_, err := call.Do()
+		call := c.startCall(ctx)
+		_, err := c.service.PlaylistItems.Insert([]string{"snippet"}, playlistItem).Context(call.ctx).Do()
+		err = call.done(err)
+		c.addQuota(ctx, "playlistItems.insert", c.costs.Write)
 		if err != nil {
 			// Check for duplicate error
 			var apiErr *googleapi.Error
@@ -244,3 +464,252 @@ func (c *Client) AddVideosToPlaylist(ctx context.Context, playlistID string, vid
 
 	return successCount, nil
 }
+
+// GetPlaylist retrieves a single playlist's metadata, including its privacy
+// status, by ID. Returns nil, nil if the playlist is not found (not an error).
+// Quota cost: 1 unit.
+func (c *Client) GetPlaylist(ctx context.Context, playlistID string) (*Playlist, error) {
+	if playlistID == "" {
+		return nil, fmt.Errorf("playlistID cannot be empty")
+	}
+	playlistID, err := ParsePlaylistID(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	call := c.startCall(ctx)
+	resp, err := c.service.Playlists.List([]string{"snippet", "status", "contentDetails"}).
+		Id(playlistID).
+		Context(call.ctx).
+		Do()
+	err = call.done(err)
+	c.addQuota(ctx, "playlists.list", c.costs.List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist: %w", playlistError(err))
+	}
+
+	// Playlist not found - not an error
+	if len(resp.Items) == 0 {
+		return nil, nil
+	}
+
+	playlist := playlistFromAPI(resp.Items[0])
+	return &playlist, nil
+}
+
+// DeletePlaylist permanently deletes one of the user's playlists.
+// Quota cost: 50 units.
+func (c *Client) DeletePlaylist(ctx context.Context, playlistID string) error {
+	if playlistID == "" {
+		return fmt.Errorf("playlistID cannot be empty")
+	}
+	playlistID, err := ParsePlaylistID(playlistID)
+	if err != nil {
+		return err
+	}
+
+	call := c.startCall(ctx)
+	err = c.service.Playlists.Delete(playlistID).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "playlists.delete", c.costs.Write)
+	if err != nil {
+		return fmt.Errorf("failed to delete playlist: %w", err)
+	}
+
+	return nil
+}
+
+// RemovePlaylistItems removes entries from a playlist by playlist item ID
+// (see Video.PlaylistItemID). Returns the count of successfully removed items.
+// Quota cost: 50 units per item removed.
+func (c *Client) RemovePlaylistItems(ctx context.Context, playlistItemIDs []string) (int, error) {
+	if len(playlistItemIDs) == 0 {
+		return 0, fmt.Errorf("playlistItemIDs cannot be empty")
+	}
+
+	removed := 0
+	for _, itemID := range playlistItemIDs {
+		// Check for context cancellation
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+
+		call := c.startCall(ctx)
+		err := c.service.PlaylistItems.Delete(itemID).Context(call.ctx).Do()
+		err = call.done(err)
+		c.addQuota(ctx, "playlistItems.delete", c.costs.Write)
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove playlist item %s: %w", itemID, err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// ReorderPlaylistItem moves a playlist entry (see Video.PlaylistItemID) of
+// videoID to the zero-based position; the entries in between shift by one.
+// Quota cost: 50 units.
+func (c *Client) ReorderPlaylistItem(ctx context.Context, playlistID, playlistItemID, videoID string, position int64) error {
+	if playlistID == "" || playlistItemID == "" || videoID == "" {
+		return fmt.Errorf("playlistID, playlistItemID and videoID are required")
+	}
+
+	item := &youtube_v3.PlaylistItem{
+		Id: playlistItemID,
+		Snippet: &youtube_v3.PlaylistItemSnippet{
+			PlaylistId: playlistID,
+			Position:   position,
+			ResourceId: &youtube_v3.ResourceId{
+				Kind:    "youtube#video",
+				VideoId: videoID,
+			},
+			// Position 0 is the zero value; force it so moves to the top are sent
+			ForceSendFields: []string{"Position"},
+		},
+	}
+
+	call := c.startCall(ctx)
+	_, err := c.service.PlaylistItems.Update([]string{"snippet"}, item).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "playlistItems.update", c.costs.Write)
+	if err != nil {
+		return fmt.Errorf("failed to move playlist item %s: %w", playlistItemID, err)
+	}
+
+	return nil
+}
+
+// parseTime parses an RFC 3339 API timestamp, returning the zero time if it is empty or invalid.
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// clampPageSize limits a page size to the API's 1-50 range, defaulting to 50.
+func clampPageSize(maxResults int64) int64 {
+	if maxResults <= 0 || maxResults > 50 {
+		return 50
+	}
+	return maxResults
+}
+
+// playlistFromAPI converts an API playlist resource into a domain Playlist.
+func playlistFromAPI(item *youtube_v3.Playlist) Playlist {
+	playlist := Playlist{
+		ID:          item.Id,
+		Title:       item.Snippet.Title,
+		Description: item.Snippet.Description,
+		ItemCount:   item.ContentDetails.ItemCount,
+		CreatedAt:   parseTime(item.Snippet.PublishedAt),
+	}
+	if item.Status != nil {
+		playlist.PrivacyStatus = item.Status.PrivacyStatus
+	}
+	return playlist
+}
+
+// videoFromPlaylistItem converts an API playlist item into a domain Video.
+func videoFromPlaylistItem(item *youtube_v3.PlaylistItem) Video {
+	return Video{
+		ID:             item.Snippet.ResourceId.VideoId,
+		Title:          item.Snippet.Title,
+		ChannelTitle:   item.Snippet.VideoOwnerChannelTitle,
+		AddedAt:        parseTime(item.Snippet.PublishedAt),
+		PlaylistItemID: item.Id,
+		Position:       item.Snippet.Position,
+		ThumbnailURL:   thumbnailURL(item.Snippet.Thumbnails),
+	}
+}
+
+// SpecialPlaylistKinds are the special playlists GetSpecialPlaylist reads.
+var SpecialPlaylistKinds = []string{"likes", "uploads", "favorites", "watchLater", "watchHistory"}
+
+// ErrSpecialPlaylistUnavailable is returned when the YouTube API does not expose
+// a special playlist; it no longer returns watch history or watch later for
+// most accounts.
+var ErrSpecialPlaylistUnavailable = errors.New("special playlist not available through the YouTube API")
+
+// relatedPlaylists returns the IDs of the authenticated user's special
+// playlists (likes, uploads, ...). They never change for an account, so they
+// are looked up once per client; a new client after re-authentication looks
+// them up again.
+// Quota cost: 1 unit on the first call.
+func (c *Client) relatedPlaylists(ctx context.Context) (*youtube_v3.ChannelContentDetailsRelatedPlaylists, error) {
+	c.related.mu.Lock()
+	defer c.related.mu.Unlock()
+	if c.related.playlists != nil {
+		return c.related.playlists, nil
+	}
+
+	call := c.startCall(ctx)
+	resp, err := c.service.Channels.List([]string{"contentDetails"}).Mine(true).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "channels.list", c.costs.List)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("no channel found for authenticated user")
+	}
+
+	related := &youtube_v3.ChannelContentDetailsRelatedPlaylists{}
+	if details := resp.Items[0].ContentDetails; details != nil && details.RelatedPlaylists != nil {
+		related = details.RelatedPlaylists
+	}
+	c.related.playlists = related
+	return related, nil
+}
+
+// specialPlaylistID returns the ID of the special playlist kind among a
+// channel's related playlists, or "" if the channel has none.
+func specialPlaylistID(related *youtube_v3.ChannelContentDetailsRelatedPlaylists, kind string) string {
+	if related == nil {
+		return ""
+	}
+	switch kind {
+	case "likes":
+		return related.Likes
+	case "uploads":
+		return related.Uploads
+	case "favorites":
+		return related.Favorites
+	case "watchLater":
+		return related.WatchLater
+	case "watchHistory":
+		return related.WatchHistory
+	}
+	return ""
+}
+
+// GetSpecialPlaylist retrieves all videos of one of the authenticated user's
+// special playlists (see SpecialPlaylistKinds). Likes are served from the
+// GetLikedVideos cache. Returns ErrSpecialPlaylistUnavailable when the API
+// does not expose the playlist.
+// Quota cost: 1 unit for the channel lookup (first call only) + 1 unit per 50 videos.
+func (c *Client) GetSpecialPlaylist(ctx context.Context, kind string) ([]Video, error) {
+	if !slices.Contains(SpecialPlaylistKinds, kind) {
+		return nil, fmt.Errorf("invalid special playlist %q: must be one of %s", kind, strings.Join(SpecialPlaylistKinds, ", "))
+	}
+	if kind == "likes" {
+		return c.GetLikedVideos(ctx)
+	}
+
+	related, err := c.relatedPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s playlist ID: %w", kind, err)
+	}
+	playlistID := specialPlaylistID(related, kind)
+	if playlistID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrSpecialPlaylistUnavailable, kind)
+	}
+
+	videos, err := c.GetPlaylistItems(ctx, playlistID)
+	if errors.Is(err, ErrPlaylistNotAccessible) || errors.Is(err, ErrPlaylistNotFound) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrSpecialPlaylistUnavailable, kind, err)
+	}
+	return videos, err
+}
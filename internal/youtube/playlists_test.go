@@ -0,0 +1,166 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// insertedSnippets records the snippets of playlistItems.insert requests, as
+// JSON objects, so tests can tell an omitted field from a zero one.
+func insertedSnippets(t *testing.T) (http.Handler, func() []map[string]json.RawMessage) {
+	var (
+		mu       sync.Mutex
+		snippets []map[string]json.RawMessage
+	)
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		var item struct {
+			Snippet map[string]json.RawMessage `json:"snippet"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			t.Errorf("decode insert: %v", err)
+		}
+		mu.Lock()
+		snippets = append(snippets, item.Snippet)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"new-item"}`))
+	})
+	return api, func() []map[string]json.RawMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		return snippets
+	}
+}
+
+func TestAddVideosToPlaylistAtSendsPosition(t *testing.T) {
+	videoIDs := []string{"song0000001", "song0000002"}
+	zero, five := int64(0), int64(5)
+
+	tests := []struct {
+		name     string
+		position *int64
+		want     []string // position sent with each insert; "" when omitted
+	}{
+		{"unpositioned", nil, []string{"", ""}},
+		{"at zero", &zero, []string{"0", "1"}},
+		{"at five", &five, []string{"5", "6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, snippets := insertedSnippets(t)
+			c := newTestClient(t, api)
+
+			added, err := c.AddVideosToPlaylistAt(context.Background(), "PLtarget", videoIDs, tt.position)
+			if err != nil || added != len(videoIDs) {
+				t.Fatalf("added %d, err %v; want %d added", added, err, len(videoIDs))
+			}
+			got := snippets()
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d inserts, want %d", len(got), len(tt.want))
+			}
+			for i, snippet := range got {
+				if position := string(snippet["position"]); position != tt.want[i] {
+					t.Errorf("insert %d sent position %q, want %q", i, position, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetSpecialPlaylistRejectsUnknownKind(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API call %s %s", r.Method, r.URL.Path)
+	}))
+
+	_, err := c.GetSpecialPlaylist(context.Background(), "history")
+	if err == nil || !strings.Contains(err.Error(), `invalid special playlist "history"`) {
+		t.Errorf("err = %v, want an invalid special playlist error", err)
+	}
+}
+
+func TestListPlaylistsPopulatesPrivacyStatus(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/playlists", func(w http.ResponseWriter, r *http.Request) {
+		if parts := r.URL.Query()["part"]; !slices.Contains(parts, "status") {
+			t.Errorf("part = %v, want it to include status", parts)
+		}
+		playlist := func(id, privacy string) map[string]any {
+			pl := map[string]any{"id": id, "snippet": map[string]any{"title": id}, "contentDetails": map[string]any{}}
+			if privacy != "" {
+				pl["status"] = map[string]any{"privacyStatus": privacy}
+			}
+			return pl
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"items": []any{
+			playlist("PLpublic", "public"),
+			playlist("PLunlisted", "unlisted"),
+			playlist("PLprivate", "private"),
+			playlist("PLnostatus", ""),
+		}})
+	})
+	c := newTestClient(t, api)
+	want := map[string]string{"PLpublic": "public", "PLunlisted": "unlisted", "PLprivate": "private", "PLnostatus": ""}
+
+	all, err := c.ListPlaylists(context.Background())
+	if err != nil {
+		t.Fatalf("ListPlaylists: %v", err)
+	}
+	page, _, err := c.ListPlaylistsPage(context.Background(), "", 50)
+	if err != nil {
+		t.Fatalf("ListPlaylistsPage: %v", err)
+	}
+	for name, playlists := range map[string][]Playlist{"ListPlaylists": all, "ListPlaylistsPage": page} {
+		if len(playlists) != len(want) {
+			t.Fatalf("%s returned %d playlists, want %d", name, len(playlists), len(want))
+		}
+		for _, pl := range playlists {
+			if pl.PrivacyStatus != want[pl.ID] {
+				t.Errorf("%s: %s privacy = %q, want %q", name, pl.ID, pl.PrivacyStatus, want[pl.ID])
+			}
+		}
+	}
+}
+
+func TestGetLikedVideosLooksUpLikesPlaylistOnce(t *testing.T) {
+	var channelCalls, itemCalls atomic.Int32
+	api := http.NewServeMux()
+	api.HandleFunc("GET /youtube/v3/channels", func(w http.ResponseWriter, r *http.Request) {
+		channelCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"id":"UCme","contentDetails":{"relatedPlaylists":{"likes":"LLme","uploads":"UUme"}}}]}`))
+	})
+	api.HandleFunc("GET /youtube/v3/playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		itemCalls.Add(1)
+		if got := r.URL.Query().Get("playlistId"); got != "LLme" {
+			t.Errorf("playlistId = %q, want the likes playlist LLme", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"id":"item-1","snippet":{"title":"Song","resourceId":{"kind":"youtube#video","videoId":"song0000001"}}}]}`))
+	})
+	// No cache TTL, so each call lists the likes again
+	c := newTestClient(t, api)
+
+	for range 2 {
+		videos, err := c.GetLikedVideos(context.Background())
+		if err != nil {
+			t.Fatalf("GetLikedVideos: %v", err)
+		}
+		if len(videos) != 1 || videos[0].ID != "song0000001" {
+			t.Errorf("GetLikedVideos = %v, want song0000001", videos)
+		}
+	}
+	if n := itemCalls.Load(); n != 2 {
+		t.Errorf("likes were listed %d times, want 2", n)
+	}
+	if n := channelCalls.Load(); n != 1 {
+		t.Errorf("channels were looked up %d times, want once", n)
+	}
+}
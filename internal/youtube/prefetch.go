@@ -0,0 +1,45 @@
+package youtube
+
+import "context"
+
+// prefetchDepth is how many fetched pages may wait unprocessed while the next
+// page is being fetched.
+const prefetchDepth = 2
+
+// prefetchPages runs a paginated listing in a goroutine and passes each page
+// to handle on the caller's goroutine, so processing one page overlaps the
+// network fetch of the next. Pagination itself stays sequential, since each
+// page needs the previous page's token. listPages is typically a call's Pages
+// method; handle must not retain the page beyond the call.
+func prefetchPages[R any](ctx context.Context, listPages func(context.Context, func(R) error) error, handle func(R) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make(chan R, prefetchDepth)
+	fetchErr := make(chan error, 1)
+	go func() {
+		defer close(pages)
+		fetchErr <- listPages(ctx, func(page R) error {
+			select {
+			case pages <- page:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	var err error
+	for page := range pages {
+		if err != nil {
+			continue // drain so the fetcher can exit
+		}
+		if err = handle(page); err != nil {
+			cancel()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return <-fetchErr
+}
@@ -0,0 +1,145 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	youtube_v3 "google.golang.org/api/youtube/v3"
+)
+
+// pagingTransport serves playlistItems.list as pages pages of 50 items each,
+// taking latency per request like a round trip to the API would.
+type pagingTransport struct {
+	pages   int
+	latency time.Duration
+}
+
+func (pt pagingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case <-time.After(pt.latency):
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	page, _ := strconv.Atoi(req.URL.Query().Get("pageToken"))
+	var items []string
+	for i := range 50 {
+		id := fmt.Sprintf("v%05d", page*50+i)
+		items = append(items, fmt.Sprintf(`{"id":"item-%s","snippet":{"title":"Song %s","resourceId":{"videoId":%q}}}`, id, id, id))
+	}
+	next := ""
+	if page+1 < pt.pages {
+		next = strconv.Itoa(page + 1)
+	}
+	body := fmt.Sprintf(`{"items":[%s],"nextPageToken":%q}`, strings.Join(items, ","), next)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func newPagingClient(tb testing.TB, pages int, latency time.Duration) *Client {
+	tb.Helper()
+	c, err := NewClient(context.Background(), &http.Client{Transport: pagingTransport{pages: pages, latency: latency}}, Options{})
+	if err != nil {
+		tb.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestGetPlaylistItemsReadsAllPagesInOrder(t *testing.T) {
+	c := newPagingClient(t, 5, 0)
+
+	videos, err := c.GetPlaylistItems(context.Background(), "PLtest")
+	if err != nil {
+		t.Fatalf("GetPlaylistItems: %v", err)
+	}
+	if len(videos) != 250 {
+		t.Fatalf("got %d videos, want 250", len(videos))
+	}
+	for i, v := range videos {
+		if want := fmt.Sprintf("v%05d", i); v.ID != want {
+			t.Fatalf("videos[%d] = %s, want %s", i, v.ID, want)
+		}
+	}
+	if used, _ := c.QuotaUsage(context.Background()); used != 5 {
+		t.Errorf("quota used %d, want 5", used)
+	}
+}
+
+func TestPrefetchPagesStopsFetchingOnHandlerError(t *testing.T) {
+	errStop := errors.New("stop")
+	fetched := 0
+	listPages := func(ctx context.Context, page func(int) error) error {
+		// Like Pages, stop once the context is canceled
+		for i := 0; ctx.Err() == nil; i++ {
+			fetched++
+			if err := page(i); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}
+
+	var handled []int
+	err := prefetchPages(context.Background(), listPages, func(page int) error {
+		handled = append(handled, page)
+		if page == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("err = %v, want the handler's error", err)
+	}
+	if !slices.Equal(handled, []int{0, 1, 2}) {
+		t.Errorf("handled pages %v, want [0 1 2]", handled)
+	}
+	// At most the pages already buffered are fetched past the failing one
+	if fetched > 3+prefetchDepth+1 {
+		t.Errorf("fetched %d pages after the handler failed on the third", fetched)
+	}
+}
+
+// BenchmarkPlaylistPages reads 20 pages taking 1ms each to fetch and 1ms each
+// to process, handling each page after its fetch (serial) or while the next
+// one is fetched (prefetch).
+func BenchmarkPlaylistPages(b *testing.B) {
+	const pages = 20
+	const latency = time.Millisecond
+	handle := func(response *youtube_v3.PlaylistItemListResponse) error {
+		time.Sleep(latency)
+		for _, item := range response.Items {
+			_ = videoFromPlaylistItem(item)
+		}
+		return nil
+	}
+
+	c := newPagingClient(b, pages, latency)
+	b.Run("serial", func(b *testing.B) {
+		for b.Loop() {
+			call := c.service.PlaylistItems.List([]string{"snippet"}).PlaylistId("PLtest").MaxResults(50)
+			if err := call.Pages(context.Background(), handle); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("prefetch", func(b *testing.B) {
+		for b.Loop() {
+			call := c.service.PlaylistItems.List([]string{"snippet"}).PlaylistId("PLtest").MaxResults(50)
+			if err := prefetchPages(context.Background(), call.Pages, handle); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
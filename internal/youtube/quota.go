@@ -0,0 +1,165 @@
+package youtube
+
+import (
+	"cmp"
+	"context"
+	"sync"
+	"time"
+	_ "time/tzdata" // Embed tz database so quota resets work in minimal containers
+)
+
+// DefaultDailyQuota is the default YouTube Data API daily quota for a Google Cloud project.
+const DefaultDailyQuota = 10000
+
+// QuotaCosts are the estimated quota costs of YouTube Data API operations.
+// YouTube occasionally changes them, so they can be overridden; zero fields
+// use DefaultQuotaCosts.
+type QuotaCosts struct {
+	List   int // any list call, per page
+	Search int // search.list
+	Write  int // insert/update/delete calls
+}
+
+// DefaultQuotaCosts are YouTube's published quota costs.
+var DefaultQuotaCosts = QuotaCosts{List: 1, Search: 100, Write: 50}
+
+// WithDefaults returns q with zero fields taken from DefaultQuotaCosts.
+func (q QuotaCosts) WithDefaults() QuotaCosts {
+	return QuotaCosts{
+		List:   cmp.Or(q.List, DefaultQuotaCosts.List),
+		Search: cmp.Or(q.Search, DefaultQuotaCosts.Search),
+		Write:  cmp.Or(q.Write, DefaultQuotaCosts.Write),
+	}
+}
+
+// quotaResetLocation is the timezone in which YouTube daily quotas reset (midnight Pacific Time).
+var quotaResetLocation = loadResetLocation("America/Los_Angeles")
+
+// QuotaTracker tracks the estimated YouTube Data API quota used today by this process.
+// Usage resets at midnight Pacific Time, matching YouTube's quota window.
+// It is safe for concurrent use.
+type QuotaTracker struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+	day   string // current quota day (YYYY-MM-DD, Pacific Time)
+	now   func() time.Time
+}
+
+// NewQuotaTracker creates a tracker for the given daily quota limit.
+// A non-positive limit uses DefaultDailyQuota.
+func NewQuotaTracker(dailyLimit int) *QuotaTracker {
+	if dailyLimit <= 0 {
+		dailyLimit = DefaultDailyQuota
+	}
+	return &QuotaTracker{
+		limit: dailyLimit,
+		now:   time.Now,
+	}
+}
+
+// Add records units of quota as spent.
+func (q *QuotaTracker) Add(units int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover()
+	q.used += units
+}
+
+// Usage returns the units used today and the daily limit.
+func (q *QuotaTracker) Usage() (used, limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover()
+	return q.used, q.limit
+}
+
+// rollover resets usage when the quota day changes. Caller must hold q.mu.
+func (q *QuotaTracker) rollover() {
+	day := q.now().In(quotaResetLocation).Format(time.DateOnly)
+	if day != q.day {
+		q.day = day
+		q.used = 0
+	}
+}
+
+// quotaIdentityKey is the context key for the quota identity.
+type quotaIdentityKey struct{}
+
+// WithQuotaIdentity returns a context whose YouTube API calls are accounted to
+// identity. Calls made with a context without an identity share a default tracker.
+func WithQuotaIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, quotaIdentityKey{}, identity)
+}
+
+// quotaIdentityFromContext returns the quota identity stored in ctx, or "".
+func quotaIdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(quotaIdentityKey{}).(string)
+	return identity
+}
+
+// quotaRegistry holds one QuotaTracker per identity, so each user's share of
+// the project's quota can be reported separately.
+type quotaRegistry struct {
+	mu       sync.Mutex
+	limit    int
+	trackers map[string]*QuotaTracker
+}
+
+// newQuotaRegistry creates a registry whose trackers use the given daily limit.
+func newQuotaRegistry(dailyLimit int) *quotaRegistry {
+	return &quotaRegistry{
+		limit:    dailyLimit,
+		trackers: make(map[string]*QuotaTracker),
+	}
+}
+
+// forIdentity returns the tracker for identity, creating it on first use.
+func (r *quotaRegistry) forIdentity(identity string) *QuotaTracker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tracker, ok := r.trackers[identity]
+	if !ok {
+		tracker = NewQuotaTracker(r.limit)
+		r.trackers[identity] = tracker
+	}
+	return tracker
+}
+
+// addQuota records an API operation and the units of quota it spent against
+// the identity in ctx, the project, and ctx's operation log if any.
+func (c *Client) addQuota(ctx context.Context, operation string, units int) {
+	c.quotas.forIdentity(quotaIdentityFromContext(ctx)).Add(units)
+	c.project.Add(units)
+	if log := OperationLogFromContext(ctx); log != nil {
+		log.record(Operation{Name: operation, Cost: units})
+	}
+}
+
+// QuotaCosts returns the quota costs the client accounts API calls with.
+func (c *Client) QuotaCosts() QuotaCosts {
+	return c.costs
+}
+
+// QuotaUsage returns the estimated units used today by the identity in ctx and the daily limit.
+func (c *Client) QuotaUsage(ctx context.Context) (used, limit int) {
+	return c.quotas.forIdentity(quotaIdentityFromContext(ctx)).Usage()
+}
+
+// TotalQuotaUsage returns the estimated units used today by every identity of
+// every client sharing the client's project tracker (Options.ProjectQuota),
+// and the daily limit. This is what counts against the project's quota, so
+// budget checks use it rather than QuotaUsage.
+func (c *Client) TotalQuotaUsage() (used, limit int) {
+	return c.project.Usage()
+}
+
+// loadResetLocation loads a timezone, falling back to a fixed UTC-8 offset.
+func loadResetLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("PST", -8*60*60)
+	}
+	return loc
+}
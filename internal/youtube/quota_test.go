@@ -0,0 +1,62 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProjectQuotaIsSharedAcrossClientsAndIdentities(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	project := NewQuotaTracker(1000)
+	alice := newTestClientWithOptions(t, api, Options{DailyQuota: 1000, ProjectQuota: project})
+	bob := newTestClientWithOptions(t, api, Options{DailyQuota: 1000, ProjectQuota: project})
+
+	aliceCtx := WithQuotaIdentity(context.Background(), "alice")
+	bobCtx := WithQuotaIdentity(context.Background(), "bob")
+	for _, rate := range []struct {
+		c   *Client
+		ctx context.Context
+	}{{alice, aliceCtx}, {alice, aliceCtx}, {bob, bobCtx}} {
+		if err := rate.c.RateVideo(rate.ctx, "song0000001", "like"); err != nil {
+			t.Fatalf("RateVideo: %v", err)
+		}
+	}
+
+	if used, _ := alice.QuotaUsage(aliceCtx); used != 100 {
+		t.Errorf("alice used %d, want 100", used)
+	}
+	if used, _ := bob.QuotaUsage(bobCtx); used != 50 {
+		t.Errorf("bob used %d, want 50", used)
+	}
+	for name, c := range map[string]*Client{"alice": alice, "bob": bob} {
+		if used, limit := c.TotalQuotaUsage(); used != 150 || limit != 1000 {
+			t.Errorf("%s's client reports project usage %d/%d, want 150/1000", name, used, limit)
+		}
+	}
+}
+
+func TestClientWithoutProjectQuotaTracksItsOwn(t *testing.T) {
+	api := http.NewServeMux()
+	api.HandleFunc("POST /youtube/v3/videos/rate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	c := newTestClient(t, api)
+	other := newTestClient(t, api)
+
+	if err := c.RateVideo(WithQuotaIdentity(context.Background(), "alice"), "song0000001", "like"); err != nil {
+		t.Fatalf("RateVideo: %v", err)
+	}
+	if err := c.RateVideo(WithQuotaIdentity(context.Background(), "bob"), "song0000001", "like"); err != nil {
+		t.Fatalf("RateVideo: %v", err)
+	}
+	if used, limit := c.TotalQuotaUsage(); used != 100 || limit != DefaultDailyQuota {
+		t.Errorf("project usage %d/%d, want 100/%d", used, limit, DefaultDailyQuota)
+	}
+	if used, _ := other.TotalQuotaUsage(); used != 0 {
+		t.Errorf("an unrelated client reports %d used, want 0", used)
+	}
+}
@@ -0,0 +1,89 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that spaces out API requests to a steady rate,
+// allowing short bursts of up to burst requests. It is safe for concurrent use.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing perSecond requests per second, or
+// nil (no limit) if perSecond is not positive.
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	burst := max(1, float64(int(perSecond)))
+	return &rateLimiter{rate: perSecond, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait blocks until the caller may send a request, or returns ctx's cause if
+// ctx ends first.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give back the reserved token so later callers don't wait for it
+		l.mu.Lock()
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return context.Cause(ctx)
+	}
+}
+
+// rateLimitedTransport makes every request wait for its limiter first, so
+// paginated listings and concurrent tool calls are throttled alike.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+// RoundTrip waits for the limiter, then sends req with the base transport.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// withRateLimit returns a copy of httpClient whose requests are limited to
+// perSecond, or httpClient itself if perSecond is not positive.
+func withRateLimit(httpClient *http.Client, perSecond float64) *http.Client {
+	limiter := newRateLimiter(perSecond)
+	if limiter == nil {
+		return httpClient
+	}
+
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	limited := *httpClient
+	limited.Transport = &rateLimitedTransport{base: base, limiter: limiter}
+	return &limited
+}
@@ -0,0 +1,94 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitSerializesConcurrentCalls(t *testing.T) {
+	const (
+		perSecond = 40
+		calls     = 50
+	)
+	var (
+		mu       sync.Mutex
+		arrivals []time.Time
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	httpClient := withRateLimit(srv.Client(), perSecond)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range calls {
+		wg.Go(func() {
+			resp, err := httpClient.Get(srv.URL)
+			if err != nil {
+				t.Errorf("get: %v", err)
+				return
+			}
+			resp.Body.Close()
+		})
+	}
+	wg.Wait()
+
+	// The first burst goes straight through; every request after it waits its
+	// turn, one per 1/perSecond
+	slices.SortFunc(arrivals, time.Time.Compare)
+	burst := int(perSecond)
+	interval := time.Second / perSecond
+	const slack = 5 * time.Millisecond
+	for i := burst; i < len(arrivals); i++ {
+		want := time.Duration(i-burst+1) * interval
+		if got := arrivals[i].Sub(start); got < want-slack {
+			t.Errorf("request %d arrived after %v, want at least %v", i+1, got, want)
+		}
+	}
+	if len(arrivals) != calls {
+		t.Errorf("server saw %d requests, want %d", len(arrivals), calls)
+	}
+}
+
+func TestRateLimitWaitRespectsCancellation(t *testing.T) {
+	l := newRateLimiter(1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait with an expiring context: err = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("wait returned after %v, want soon after the context ended", elapsed)
+	}
+
+	// The abandoned token was given back, so the next caller waits about one
+	// interval rather than two
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens after a cancelled wait = %.2f, want the reservation returned", tokens)
+	}
+}
+
+func TestWithRateLimitDisabled(t *testing.T) {
+	httpClient := &http.Client{}
+	if got := withRateLimit(httpClient, 0); got != httpClient {
+		t.Error("withRateLimit(0) wrapped the client, want it returned unchanged")
+	}
+}
@@ -0,0 +1,31 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ratings are the ratings RateVideo accepts; "none" removes a previous rating.
+var Ratings = []string{"like", "dislike", "none"}
+
+// RateVideo likes, dislikes, or clears the rating of a video by ID or URL.
+// Liking adds the video to the user's liked videos, so the library cache is
+// dropped.
+// Quota cost: 50 units.
+func (c *Client) RateVideo(ctx context.Context, videoID, rating string) error {
+	videoID, err := ParseVideoID(videoID)
+	if err != nil {
+		return err
+	}
+
+	call := c.startCall(ctx)
+	err = c.service.Videos.Rate(videoID, rating).Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "videos.rate", c.costs.Write)
+	if err != nil {
+		return fmt.Errorf("failed to rate video %s: %w", videoID, err)
+	}
+
+	c.InvalidateCache()
+	return nil
+}
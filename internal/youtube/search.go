@@ -1,8 +1,15 @@
 package youtube
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/youtube/v3"
 )
 
 // SearchResult represents a single YouTube search result
@@ -11,6 +18,9 @@ type SearchResult struct {
 	Title        string
 	ChannelTitle string
 	Description  string
+	// ThumbnailURL is the video's high-quality thumbnail, else its default
+	// one; empty when it has none.
+	ThumbnailURL string
 }
 
 // VideoDetail represents detailed information about a YouTube video
@@ -19,16 +29,113 @@ type VideoDetail struct {
 	Title        string
 	ChannelTitle string
 	Description  string
-	Duration     string
-	PublishedAt  string
+	// Duration is the raw ISO 8601 duration, e.g. "PT4M30S".
+	Duration string
+	// DurationSeconds is Duration in seconds; 0 for livestreams or unknown durations.
+	DurationSeconds int64
+	// DurationHuman is Duration formatted as "4:30" (or "1:02:03"); empty when unknown.
+	DurationHuman string
+	PublishedAt   string
+	// ThumbnailURL is the video's high-quality thumbnail, else its default
+	// one; empty when it has none.
+	ThumbnailURL string
+	// UploadStatus and PrivacyStatus come from the video's status; empty when unknown.
+	UploadStatus  string
+	PrivacyStatus string
+	// AllowedRegions and BlockedRegions are the video's region restriction,
+	// as ISO 3166-1 alpha-2 codes; both empty when it plays everywhere.
+	AllowedRegions []string
+	BlockedRegions []string
+}
+
+// UnplayableReason returns why the video cannot be played in region (an ISO
+// 3166-1 alpha-2 code, or empty to skip the region check), or "" if it can.
+func (v VideoDetail) UnplayableReason(region string) string {
+	switch {
+	case v.UploadStatus != "" && v.UploadStatus != "processed":
+		return "upload " + v.UploadStatus
+	case v.PrivacyStatus == "private":
+		return "private"
+	case region == "":
+		return ""
+	case slices.Contains(v.BlockedRegions, region),
+		len(v.AllowedRegions) > 0 && !slices.Contains(v.AllowedRegions, region):
+		return "blocked in " + region
+	}
+	return ""
+}
+
+// SearchOptions narrows a search. Empty fields apply no restriction, except
+// RegionCode and RelevanceLanguage which fall back to the client's defaults.
+type SearchOptions struct {
+	// CategoryID restricts results to a video category; empty searches all categories.
+	CategoryID string
+	// RegionCode is an ISO 3166-1 alpha-2 country code, e.g. "DE".
+	RegionCode string
+	// RelevanceLanguage is an ISO 639-1 language code, e.g. "de".
+	RelevanceLanguage string
+	// Order sorts results: one of SearchOrders. Empty means relevance.
+	Order string
+	// PublishedAfter and PublishedBefore bound the upload date; zero is unbounded.
+	// They are applied by YouTube, so filtered-out videos cost no extra quota.
+	PublishedAfter  time.Time
+	PublishedBefore time.Time
+}
+
+// SearchOrders are the result orderings supported by YouTube search.
+var SearchOrders = []string{"relevance", "date", "rating", "viewCount", "title"}
+
+// Validate checks the options before any quota is spent. Region and language
+// codes are checked loosely by length; empty values are valid and mean "unset".
+func (o SearchOptions) Validate() error {
+	if o.RegionCode != "" && len(o.RegionCode) != 2 {
+		return fmt.Errorf("invalid region code %q: expected an ISO 3166-1 alpha-2 code such as \"US\"", o.RegionCode)
+	}
+	// ISO 639-1 codes are 2 letters; YouTube also accepts tags like "zh-Hans"
+	if o.RelevanceLanguage != "" && (len(o.RelevanceLanguage) < 2 || len(o.RelevanceLanguage) > 7) {
+		return fmt.Errorf("invalid relevance language %q: expected an ISO 639-1 code such as \"en\"", o.RelevanceLanguage)
+	}
+	if o.Order != "" && !slices.Contains(SearchOrders, o.Order) {
+		return fmt.Errorf("invalid order %q: must be one of %s", o.Order, strings.Join(SearchOrders, ", "))
+	}
+	if !o.PublishedAfter.IsZero() && !o.PublishedBefore.IsZero() && !o.PublishedAfter.Before(o.PublishedBefore) {
+		return fmt.Errorf("publishedAfter (%s) must be before publishedBefore (%s)", o.PublishedAfter.Format(time.RFC3339), o.PublishedBefore.Format(time.RFC3339))
+	}
+	return nil
 }
 
-// SearchVideos searches YouTube for videos matching the query.
+// SearchVideos searches YouTube for music videos matching the query.
 // Returns only the first page of results (no pagination) to conserve quota.
 // Each search costs 100 quota units.
 func (c *Client) SearchVideos(ctx context.Context, query string, maxResults int64) ([]SearchResult, error) {
+	return c.SearchVideosInCategory(ctx, query, c.ResolveMusicCategoryID(ctx), maxResults)
+}
+
+// SearchVideosInCategory searches YouTube for videos in the given video category.
+// An empty categoryID searches across all categories.
+// Returns only the first page of results (no pagination) to conserve quota.
+// Each search costs 100 quota units.
+func (c *Client) SearchVideosInCategory(ctx context.Context, query, categoryID string, maxResults int64) ([]SearchResult, error) {
+	return c.SearchVideosWithOptions(ctx, query, maxResults, SearchOptions{CategoryID: categoryID})
+}
+
+// SearchVideosWithOptions searches YouTube for videos, narrowed by opts.
+// Region and language default to the client's configured search defaults;
+// when both are unset YouTube infers them from the authenticated account.
+// Returns only the first page of results (no pagination) to conserve quota.
+// Each search costs 100 quota units.
+func (c *Client) SearchVideosWithOptions(ctx context.Context, query string, maxResults int64, opts SearchOptions) ([]SearchResult, error) {
+	results, _, err := c.SearchVideosPage(ctx, query, maxResults, opts, "")
+	return results, err
+}
+
+// SearchVideosPage is SearchVideosWithOptions for a single page of results.
+// Pass the returned nextPageToken to fetch the following page, with the same
+// query and options; it is empty on the last page. Every page is a new search
+// costing 100 quota units, so only page deeper when the user accepts the cost.
+func (c *Client) SearchVideosPage(ctx context.Context, query string, maxResults int64, opts SearchOptions, pageToken string) ([]SearchResult, string, error) {
 	if query == "" {
-		return nil, fmt.Errorf("search query cannot be empty")
+		return nil, "", fmt.Errorf("search query cannot be empty")
 	}
 
 	// Default to 10 results if not specified
@@ -40,17 +147,45 @@ func (c *Client) SearchVideos(ctx context.Context, query string, maxResults int6
 		maxResults = 25
 	}
 
-	// Search for videos in Music category (videoCategoryId=10)
 	// Use single-page .Do() not .Pages() to conserve quota (100 units per page)
-	call := c.service.Search.List([]string{"snippet"}).
+	searchCall := c.service.Search.List([]string{"snippet"}).
 		Q(query).
 		Type("video").
-		VideoCategoryId("10").
 		MaxResults(maxResults)
+	if opts.CategoryID != "" {
+		searchCall = searchCall.VideoCategoryId(opts.CategoryID)
+	}
+	if pageToken != "" {
+		searchCall = searchCall.PageToken(pageToken)
+	}
 
-	resp, err := call.Do()
+	opts.RegionCode = cmp.Or(opts.RegionCode, c.searchDefaults.RegionCode)
+	opts.RelevanceLanguage = cmp.Or(opts.RelevanceLanguage, c.searchDefaults.RelevanceLanguage)
+	if err := opts.Validate(); err != nil {
+		return nil, "", err
+	}
+	if opts.RegionCode != "" {
+		searchCall = searchCall.RegionCode(strings.ToUpper(opts.RegionCode))
+	}
+	if opts.RelevanceLanguage != "" {
+		searchCall = searchCall.RelevanceLanguage(opts.RelevanceLanguage)
+	}
+	if opts.Order != "" {
+		searchCall = searchCall.Order(opts.Order)
+	}
+	if !opts.PublishedAfter.IsZero() {
+		searchCall = searchCall.PublishedAfter(opts.PublishedAfter.Format(time.RFC3339))
+	}
+	if !opts.PublishedBefore.IsZero() {
+		searchCall = searchCall.PublishedBefore(opts.PublishedBefore.Format(time.RFC3339))
+	}
+
+	call := c.startCall(ctx)
+	resp, err := searchCall.Context(call.ctx).Do()
+	err = call.done(err)
+	c.addQuota(ctx, "search.list", c.costs.Search)
 	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+		return nil, "", fmt.Errorf("search failed: %w", err)
 	}
 
 	results := make([]SearchResult, 0, len(resp.Items))
@@ -60,24 +195,94 @@ func (c *Client) SearchVideos(ctx context.Context, query string, maxResults int6
 			Title:        item.Snippet.Title,
 			ChannelTitle: item.Snippet.ChannelTitle,
 			Description:  item.Snippet.Description,
+			ThumbnailURL: thumbnailURL(item.Snippet.Thumbnails),
 		})
 	}
 
-	return results, nil
+	return results, resp.NextPageToken, nil
 }
 
-// GetVideo retrieves detailed information about a specific video by ID.
+// relatedTitleCutRe matches where a title's decorations begin, e.g. "(Official Video)" or "| Live".
+var relatedTitleCutRe = regexp.MustCompile(`[(\[|]`)
+
+// relatedQuery builds the search query GetRelatedVideos uses for a seed video:
+// the channel name (minus the auto-generated " - Topic" suffix) plus the first
+// few words of the title before any "(...)", "[...]" or "|" decorations.
+func relatedQuery(seed VideoDetail) string {
+	title := seed.Title
+	if loc := relatedTitleCutRe.FindStringIndex(title); loc != nil && loc[0] > 0 {
+		title = title[:loc[0]]
+	}
+	words := strings.Fields(title)
+	if len(words) > 4 {
+		words = words[:4]
+	}
+	channel := strings.TrimSuffix(seed.ChannelTitle, " - Topic")
+	return strings.TrimSpace(channel + " " + strings.Join(words, " "))
+}
+
+// GetRelatedVideos returns music videos similar to a seed video.
+// YouTube's API removed the relatedToVideoId search parameter, so this is an
+// approximation: it looks up the seed's title and channel and searches the
+// Music category for them (see relatedQuery), excluding the seed itself.
+// Returns the query used alongside the results.
+// Quota cost: 1 unit (seed lookup) + 100 units (search).
+func (c *Client) GetRelatedVideos(ctx context.Context, videoID string, maxResults int64) ([]SearchResult, string, error) {
+	videoID, err := ParseVideoID(videoID)
+	if err != nil {
+		return nil, "", err
+	}
+	seed, err := c.GetVideo(ctx, videoID)
+	if err != nil {
+		return nil, "", err
+	}
+	if seed == nil {
+		return nil, "", fmt.Errorf("video %s not found", videoID)
+	}
+
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	query := relatedQuery(*seed)
+	// Ask for one extra result since the seed usually ranks first
+	results, err := c.SearchVideos(ctx, query, maxResults+1)
+	if err != nil {
+		return nil, query, err
+	}
+
+	related := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.VideoID == videoID {
+			continue
+		}
+		related = append(related, result)
+	}
+	if int64(len(related)) > maxResults {
+		related = related[:maxResults]
+	}
+
+	return related, query, nil
+}
+
+// GetVideo retrieves detailed information about a specific video by ID or URL.
 // Returns nil, nil if the video is not found (not an error).
 // Costs only 1 quota unit.
 func (c *Client) GetVideo(ctx context.Context, videoID string) (*VideoDetail, error) {
 	if videoID == "" {
 		return nil, fmt.Errorf("video ID cannot be empty")
 	}
+	videoID, err := ParseVideoID(videoID)
+	if err != nil {
+		return nil, err
+	}
 
-	call := c.service.Videos.List([]string{"snippet", "contentDetails"}).
-		Id(videoID)
-
-	resp, err := call.Do()
+	call := c.startCall(ctx)
+	resp, err := c.service.Videos.List([]string{"snippet", "contentDetails", "status"}).
+		Id(videoID).
+		Context(call.ctx).
+		Do()
+	err = call.done(err)
+	c.addQuota(ctx, "videos.list", c.costs.List)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get video: %w", err)
 	}
@@ -87,13 +292,94 @@ func (c *Client) GetVideo(ctx context.Context, videoID string) (*VideoDetail, er
 		return nil, nil
 	}
 
-	item := resp.Items[0]
-	return &VideoDetail{
-		ID:           item.Id,
-		Title:        item.Snippet.Title,
-		ChannelTitle: item.Snippet.ChannelTitle,
-		Description:  item.Snippet.Description,
-		Duration:     item.ContentDetails.Duration,
-		PublishedAt:  item.Snippet.PublishedAt,
-	}, nil
+	return videoDetailFromAPI(resp.Items[0]), nil
+}
+
+// GetVideos retrieves detailed information about multiple videos by ID or URL, batching
+// requests in groups of 50 (the API cap). Results preserve the order of videoIDs;
+// videos that are not found are omitted and duplicate IDs are returned once.
+// Quota cost: 1 unit per 50 videos.
+func (c *Client) GetVideos(ctx context.Context, videoIDs []string) ([]VideoDetail, error) {
+	videoIDs, err := parseVideoIDs(videoIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]*VideoDetail, len(videoIDs))
+
+	for _, batch := range batchIDs(videoIDs) {
+		// Check context cancellation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		call := c.startCall(ctx)
+		resp, err := c.service.Videos.List([]string{"snippet", "contentDetails", "status"}).
+			Id(batch...).
+			Context(call.ctx).
+			Do()
+		err = call.done(err)
+		c.addQuota(ctx, "videos.list", c.costs.List)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get videos: %w", err)
+		}
+
+		for _, item := range resp.Items {
+			found[item.Id] = videoDetailFromAPI(item)
+		}
+	}
+
+	// Merge batches back into input order
+	videos := make([]VideoDetail, 0, len(found))
+	for _, id := range videoIDs {
+		if detail, ok := found[id]; ok {
+			videos = append(videos, *detail)
+			delete(found, id)
+		}
+	}
+
+	return videos, nil
+}
+
+// thumbnailURL returns the high-quality thumbnail URL of a snippet, falling
+// back to the default one, or "" if it has neither.
+func thumbnailURL(thumbnails *youtube.ThumbnailDetails) string {
+	switch {
+	case thumbnails == nil:
+		return ""
+	case thumbnails.High != nil && thumbnails.High.Url != "":
+		return thumbnails.High.Url
+	case thumbnails.Default != nil:
+		return thumbnails.Default.Url
+	}
+	return ""
+}
+
+// videoDetailFromAPI converts an API video resource into a domain VideoDetail.
+func videoDetailFromAPI(item *youtube.Video) *VideoDetail {
+	detail := &VideoDetail{
+		ID: item.Id,
+	}
+	if item.Snippet != nil {
+		detail.Title = item.Snippet.Title
+		detail.ChannelTitle = item.Snippet.ChannelTitle
+		detail.Description = item.Snippet.Description
+		detail.PublishedAt = item.Snippet.PublishedAt
+		detail.ThumbnailURL = thumbnailURL(item.Snippet.Thumbnails)
+	}
+	if item.ContentDetails != nil {
+		detail.Duration = item.ContentDetails.Duration
+		if d, err := ParseISODuration(detail.Duration); err == nil && d > 0 {
+			detail.DurationSeconds = int64(d / time.Second)
+			detail.DurationHuman = FormatDuration(d)
+		}
+		if r := item.ContentDetails.RegionRestriction; r != nil {
+			detail.AllowedRegions = r.Allowed
+			detail.BlockedRegions = r.Blocked
+		}
+	}
+	if item.Status != nil {
+		detail.UploadStatus = item.Status.UploadStatus
+		detail.PrivacyStatus = item.Status.PrivacyStatus
+	}
+	return detail
 }
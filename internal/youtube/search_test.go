@@ -0,0 +1,76 @@
+package youtube
+
+import (
+	"testing"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestVideoDetailUnplayableReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail VideoDetail
+		region string
+		want   string
+	}{
+		{"playable", VideoDetail{UploadStatus: "processed", PrivacyStatus: "public"}, "DE", ""},
+		{"status unknown", VideoDetail{}, "DE", ""},
+		{"still uploading", VideoDetail{UploadStatus: "uploaded"}, "", "upload uploaded"},
+		{"private", VideoDetail{UploadStatus: "processed", PrivacyStatus: "private"}, "", "private"},
+		{"unlisted", VideoDetail{UploadStatus: "processed", PrivacyStatus: "unlisted"}, "DE", ""},
+		{"blocked in region", VideoDetail{BlockedRegions: []string{"DE"}}, "DE", "blocked in DE"},
+		{"blocked elsewhere", VideoDetail{BlockedRegions: []string{"US"}}, "DE", ""},
+		{"not allowed in region", VideoDetail{AllowedRegions: []string{"US"}}, "DE", "blocked in DE"},
+		{"allowed in region", VideoDetail{AllowedRegions: []string{"DE", "US"}}, "DE", ""},
+		{"region check skipped", VideoDetail{BlockedRegions: []string{"DE"}}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.detail.UnplayableReason(tt.region); got != tt.want {
+				t.Errorf("UnplayableReason(%q) = %q, want %q", tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThumbnailURL(t *testing.T) {
+	high := &youtube.Thumbnail{Url: "https://i.ytimg.com/vi/x/hqdefault.jpg"}
+	def := &youtube.Thumbnail{Url: "https://i.ytimg.com/vi/x/default.jpg"}
+	medium := &youtube.Thumbnail{Url: "https://i.ytimg.com/vi/x/mqdefault.jpg"}
+
+	tests := []struct {
+		name       string
+		thumbnails *youtube.ThumbnailDetails
+		want       string
+	}{
+		{"high preferred", &youtube.ThumbnailDetails{Default: def, Medium: medium, High: high}, high.Url},
+		{"default fallback", &youtube.ThumbnailDetails{Default: def, Medium: medium}, def.Url},
+		{"empty high skipped", &youtube.ThumbnailDetails{Default: def, High: &youtube.Thumbnail{}}, def.Url},
+		{"only medium", &youtube.ThumbnailDetails{Medium: medium}, ""},
+		{"none", &youtube.ThumbnailDetails{}, ""},
+		{"absent", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := thumbnailURL(tt.thumbnails); got != tt.want {
+				t.Errorf("thumbnailURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoDetailFromAPIThumbnail(t *testing.T) {
+	item := &youtube.Video{
+		Id: "song0000001",
+		Snippet: &youtube.VideoSnippet{Thumbnails: &youtube.ThumbnailDetails{
+			Default: &youtube.Thumbnail{Url: "default.jpg"},
+			High:    &youtube.Thumbnail{Url: "high.jpg"},
+		}},
+	}
+	if got := videoDetailFromAPI(item).ThumbnailURL; got != "high.jpg" {
+		t.Errorf("ThumbnailURL = %q, want high.jpg", got)
+	}
+	if got := videoDetailFromAPI(&youtube.Video{Id: "song0000001"}).ThumbnailURL; got != "" {
+		t.Errorf("ThumbnailURL without a snippet = %q, want empty", got)
+	}
+}
@@ -15,14 +15,26 @@ type Subscription struct {
 }
 
 // GetSubscriptions retrieves ALL of the user's channel subscriptions with no pagination cap.
+// Results are cached for the client's cache TTL.
 func (c *Client) GetSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return cachedSlice(ctx, c.cache, cacheKeySubscriptions, func() ([]Subscription, error) {
+		return c.fetchSubscriptions(ctx)
+	})
+}
+
+// fetchSubscriptions fetches the subscriptions from the API, bypassing the cache.
+func (c *Client) fetchSubscriptions(ctx context.Context) ([]Subscription, error) {
 	var subscriptions []Subscription
 	subscriptionsCall := c.service.Subscriptions.
 		List([]string{"snippet"}).
 		Mine(true).
 		MaxResults(50)
 
-	err := subscriptionsCall.Pages(ctx, func(response *youtube_v3.SubscriptionListResponse) error {
+	call := c.startCall(ctx)
+	err := subscriptionsCall.Pages(call.ctx, func(response *youtube_v3.SubscriptionListResponse) error {
+		call.nextPage()
+		c.addQuota(ctx, "subscriptions.list", c.costs.List)
+
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return err
@@ -39,6 +51,7 @@ func (c *Client) GetSubscriptions(ctx context.Context) ([]Subscription, error) {
 
 		return nil
 	})
+	err = call.done(err)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve subscriptions: %w", err)
@@ -0,0 +1,56 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultAPITimeout bounds a single YouTube API request.
+const DefaultAPITimeout = 30 * time.Second
+
+// ErrAPITimeout is returned when a YouTube API request exceeds the configured
+// timeout. It is distinct from the caller cancelling the context.
+var ErrAPITimeout = errors.New("youtube API request timed out")
+
+// apiCall bounds one API request, or each page of a paginated request, with
+// the client's timeout. The parent context's cancellation still propagates.
+type apiCall struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+// startCall begins a timed API call derived from ctx. Pass call.ctx to the
+// request and always finish with done. A non-positive timeout disables it.
+func (c *Client) startCall(ctx context.Context) *apiCall {
+	callCtx, cancel := context.WithCancelCause(ctx)
+	call := &apiCall{ctx: callCtx, cancel: cancel, timeout: c.apiTimeout}
+	if c.apiTimeout > 0 {
+		call.timer = time.AfterFunc(c.apiTimeout, func() { cancel(ErrAPITimeout) })
+	}
+	return call
+}
+
+// nextPage restarts the timeout; call it from Pages callbacks so the timeout
+// applies per page rather than to the whole listing.
+func (a *apiCall) nextPage() {
+	if a.timer != nil {
+		a.timer.Reset(a.timeout)
+	}
+}
+
+// done releases the call and returns err, marked with ErrAPITimeout when the
+// call's own timeout (not the caller) ended it.
+func (a *apiCall) done(err error) error {
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	if err != nil && errors.Is(context.Cause(a.ctx), ErrAPITimeout) {
+		err = fmt.Errorf("%w after %s: %w", ErrAPITimeout, a.timeout, err)
+	}
+	a.cancel(nil)
+	return err
+}
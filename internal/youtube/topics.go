@@ -0,0 +1,68 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// FetchVideoTopics returns a map of video ID to the topic categories YouTube
+// assigned to it, such as "Rock music" or "Hip hop music". Videos without
+// topic data, or that no longer exist, are omitted. Processes in batches of 50
+// to stay within API limits.
+// Quota cost: 1 unit per 50 videos.
+func (c *Client) FetchVideoTopics(ctx context.Context, videoIDs []string) (map[string][]string, error) {
+	topics := make(map[string][]string, len(videoIDs))
+
+	for _, batch := range batchIDs(videoIDs) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		call := c.startCall(ctx)
+		resp, err := c.service.Videos.
+			List([]string{"topicDetails"}).
+			Id(batch...).
+			Fields("items(id,topicDetails/topicCategories)").
+			Context(call.ctx).
+			Do()
+		err = call.done(err)
+		c.addQuota(ctx, "videos.list", c.costs.List)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch video topics: %w", err)
+		}
+
+		for _, item := range resp.Items {
+			if item.TopicDetails == nil {
+				continue
+			}
+			var names []string
+			for _, category := range item.TopicDetails.TopicCategories {
+				if name := topicName(category); name != "" {
+					names = append(names, name)
+				}
+			}
+			if len(names) > 0 {
+				topics[item.Id] = names
+			}
+		}
+	}
+
+	return topics, nil
+}
+
+// topicName turns a topic category, a Wikipedia URL such as
+// https://en.wikipedia.org/wiki/Rock_music, into its readable name.
+func topicName(category string) string {
+	u, err := url.Parse(category)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}